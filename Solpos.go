@@ -65,8 +65,8 @@ package solpos
 *                               in calculation of declination angle)
 */
 import (
-	"fmt"
-	"github.com/pkg/errors"
+	"context"
+	"errors"
 	"math"
 	"time"
 )
@@ -105,10 +105,50 @@ import (
 type Solpos interface {
 	// Methods
 	Calculate() error
-	// helper function to get sunrise
+	// helper function to get sunrise, a time two days off (the flag value) on days without sunrise, see Sunrise
 	GetSunrise() time.Time
-	// helper function to get sunset
+	// helper function to get sunset, a time two days off (the flag value) on days without sunset, see Sunset
 	GetSunset() time.Time
+	// helper function to get the dawn of a twilight (the sun rising through its depression angle) on the day of the last calculation,
+	// an error wrapping ErrNoTwilight if the sun does not cross the depression angle on that day
+	GetDawn(twilight Twilight) (time.Time, error)
+	// helper function to get the dusk of a twilight (the sun setting through its depression angle), like GetDawn
+	GetDusk(twilight Twilight) (time.Time, error)
+	// helper function to get the windows (morning and evening) of the day of the last calculation with the refracted elevation within a band,
+	// e.g. GoldenHour or BlueHour. Windows reaching solar midnight start or end there, a day without the band returns none.
+	GetElevationWindows(band ElevationBand) ([]ElevationWindow, error)
+	// helper function to get solar noon (true solar time 12:00) of the day of the last calculation, local standard time.
+	// Returns an error wrapping ErrNotComputed if the last calculation did not run S_TST.
+	GetSolarNoon() (time.Time, error)
+	// helper function to get the refracted elevation of the sun at solar noon with the declination of the last calculation, degrees.
+	// Returns an error wrapping ErrNotComputed if the last calculation did not run S_GEOM.
+	GetMaxElevation() (float64, error)
+	// helper function to get the time from sunrise to sunset (without refraction) from the sunset hour angle of the last calculation,
+	// 24 hours for polar day and 0 for polar night
+	GetDayLength() (time.Duration, error)
+	// helper function to get sunrise like GetSunrise, with an error wrapping ErrPolarDay or ErrPolarNight on days without sunrise instead of a bogus time
+	Sunrise() (time.Time, error)
+	// helper function to get sunset like GetSunset, with an error wrapping ErrPolarDay or ErrPolarNight on days without sunset
+	Sunset() (time.Time, error)
+	// helper function to get sunrise in the event format: time.Time, RFC 3339 string, int64 Unix time or float64 minutes, nil without sunrise
+	GetSunriseAs() interface{}
+	// helper function to get sunset in the event format, like GetSunriseAs
+	GetSunsetAs() interface{}
+	// helper function to get a snapshot of all outputs of the last calculation
+	GetResult() Result
+	// helper function to calculate a series from start to end (inclusive) at a fixed step, returning all results
+	CalculateSeries(start time.Time, end time.Time, step time.Duration) ([]Result, error)
+	// helper function to calculate a series from start to end (inclusive) at a fixed step, writing each result to the sink
+	StreamSeries(start time.Time, end time.Time, step time.Duration, sink ResultsSink) error
+	// helper function calculating a series from start to end (inclusive) at a fixed step in a goroutine on a copy of the calculator, sending each result
+	// as it is computed until ctx is done. The error channel receives the outcome (nil, a calculation error or ctx.Err()) after the results are closed.
+	StreamPositions(ctx context.Context, start time.Time, end time.Time, step time.Duration) (<-chan Result, <-chan error)
+	// helper function like StreamSeries, but calculating one chunk (e.g. a month) at a time and flushing the sink in between.
+	// A chunk is calculated completely before it is written, so a calculation error never leaves a partial chunk in the sink and
+	// the run can be resumed at the last chunk boundary. An error of the sink itself may leave the rows of the chunk written so far.
+	StreamSeriesChunked(start time.Time, end time.Time, step time.Duration, chunk SeriesChunk, sink ResultsSink) error
+	// helper function to calculate exact positions from start to end at a coarse cadence (e.g. a minute), returning an interpolator for any time in between
+	Interpolate(start time.Time, end time.Time, cadence time.Duration) (*Interpolator, error)
 	// using go builtin time functions
 	Getdate() time.Time
 	SetDate(dt time.Time)
@@ -118,8 +158,8 @@ type Solpos interface {
 	GetDay() int
 	SetDay(day int)
 
-	/* I/O: S_DOY Day number (day of year; Feb 1 = 32 ) solpos REQUIRES this by default, but
-	   will optionally calculate it from month and day depending on the setting of the S_DOY function switch. */
+	/* I/O: S_DOY Day number (day of year; Feb 1 = 32 ) solpos calculates it from month and day, unless the S_DOY function
+	   switch is set and the day number was set with SetDaynum after the last SetDate, SetMonth or SetDay. */
 	GetDaynum() int
 	SetDaynum(daydaynum int)
 	/* I: Switch to choose functions for desired output. */
@@ -189,6 +229,12 @@ type Solpos interface {
 	GetHrang() float64
 	/* T:  S_GEOM     Julian Day of 1 JAN 2000 minus 2,400,000 days (in order to regain single precision) */
 	GetJulday() float64
+	/* I:             Date inputs as Julian Date (UTC, to the second) in the Location or Timezone of the date, and as Modified Julian
+	                  Date (JD - 2400000.5) */
+	GetJulianDate() float64
+	SetJulianDate(jd float64)
+	GetModifiedJulianDate() float64
+	SetModifiedJulianDate(mjd float64)
 	/* I: Latitude, degrees north (south negative) */
 	GetLatitude() float64
 	SetLatitude(latitude float64)
@@ -219,9 +265,17 @@ type Solpos interface {
 	/* I:             Shadow-band sky factor */
 	GetSbsky() float64
 	SetSbsky(sbsky float64)
+	/* I:             Shadow-band correction model, DEFAULT = DrummondModel */
+	GetShadowBandModel() ShadowBandModel
+	SetShadowBandModel(model ShadowBandModel)
+	// helper function setting Sbwid, Sbrad and Sbsky to the dimensions of a shadow band, e.g. ShadowBandKippZonen
+	SetShadowBand(band ShadowBand)
 	/* I:             Solar constant (NREL uses 1367 W/sq m) */
 	GetSolcon() float64
 	SetSolcon(solcon float64)
+	/* I:             Total solar irradiance by date, replaces Solcon in every calculation, DEFAULT = nil (fixed Solcon) */
+	GetTSI() TSI
+	SetTSI(tsi TSI)
 	/* T:  S_SRHA     Sunset(/rise) hour angle, degrees */
 	GetSsha() float64
 	/* O:  S_SRSS     Sunrise time, minutes from midnight, local, WITHOUT refraction */
@@ -231,12 +285,34 @@ type Solpos interface {
 	/* I:             Ambient dry-bulb temperature, degrees C, used for refraction correction */
 	GetTemp() float64
 	SetTemp(temp float64)
+	// helper function setting Press and Temp to the International Standard Atmosphere at a pressure altitude in meters (see FlightLevel)
+	SetPressureAltitude(pressureAltitude float64)
+	/* I:             Altitude of the observer above sea level, meters. Sretr and Ssetr (and GetDayLength) are the crossings of the dipped
+	                  horizon above 0 m, SetAltitude sets Press to the barometric pressure of the altitude as well. DEFAULT = 0 */
+	GetAltitude() float64
+	SetAltitude(altitude float64)
+	/* I:             Pressure and temperature by date, replace Press and Temp in every calculation, DEFAULT = nil (fixed Press and Temp) */
+	GetAtmosphere() AtmosphereProvider
+	SetAtmosphere(provider AtmosphereProvider)
 	/* I:             Degrees tilt from horizontal of panel */
 	GetTilt() float64
 	SetTilt(tilt float64)
-	/* I:             Time zone, east (west negative). USA:  Mountain = -7, Central = -6, etc. */
+	/* I:             Dual-axis tracker setting Tilt and Aspect to the refracted sun position (within its limits, stow position at night)
+	                  in every calculation before Cosinc and Etrtilt, DEFAULT = nil (fixed Tilt and Aspect) */
+	GetDualAxis() *DualAxis
+	SetDualAxis(tracker *DualAxis)
+	/* I:             Local horizon line (azimuth to elevation of terrain and buildings), DEFAULT = nil (flat horizon at 0 degrees) */
+	GetHorizon() HorizonProfile
+	SetHorizon(profile HorizonProfile)
+	/* O:  S_SOLAZM, S_REFRAC  Refracted sun below the horizon line of Horizon */
+	GetSunObstructed() bool
+	/* I:             Time zone, east (west negative), fractional hours for offsets like India = +5.5. USA:  Mountain = -7, Central = -6, etc. */
 	GetTimezone() float64
 	SetTimezone(timezone float64)
+	/* I:             Location of the date fields (time zone with DST rules), set by SetDate, nil after SetTimezone. With a location the
+	                  timezone follows the UTC offset of the date, e.g. when the day of year crosses a DST change. DEFAULT = nil */
+	GetLocation() *time.Location
+	SetLocation(loc *time.Location)
 	/* T:  S_TST      True solar time, minutes from midnight */
 	GetTst() float64
 	/* T:  S_TST      True solar time - local standard time */
@@ -250,47 +326,129 @@ type Solpos interface {
 	/* O:  S_REFRAC   Solar zenith angle, deg. from zenith, refracted */
 	GetZenref() float64
 	SetZenref(zenref float64)
+	/* I:             Strict mode: outputs of functions not enabled in the last calculation are NaN instead of stale values, DEFAULT = false */
+	GetStrict() bool
+	SetStrict(strict bool)
+	/* I:             Representation of event times of GetSunriseAs and GetSunsetAs, DEFAULT = EventTime */
+	GetEventFormat() EventFormat
+	SetEventFormat(format EventFormat)
+	/* I:             Solar position algorithm of declination, right ascension, hour angle and earth radius vector, e.g. AlgorithmSPA
+	                  for +-0.0003 degrees within -2000-6000, DEFAULT = AlgorithmSOLPOS */
+	GetAlgorithm() Algorithm
+	SetAlgorithm(algorithm Algorithm)
+	/* I:             Apparent ecliptic coordinates of AlgorithmSOLPOS with nutation in longitude and obliquity and annual aberration, the
+	                  apparent sidereal time and the parallax of the observer, DEFAULT = false (the mean coordinates of the C code) */
+	GetPrecise() bool
+	SetPrecise(precise bool)
+	/* I:             Delta T (TT - UT1) in seconds, the ecliptic coordinates are calculated in terrestrial time, DEFAULT = 0 (neglected) */
+	GetDeltaT() float64
+	SetDeltaT(deltaT float64)
+	/* I:             Delta T by date, replaces DeltaT in every calculation, e.g. DeltaTModel, DEFAULT = nil (fixed DeltaT) */
+	GetDeltaTSource() DeltaTProvider
+	SetDeltaTSource(provider DeltaTProvider)
+	/* I:             Handling of years outside the range of the algorithm (1950-2050 for AlgorithmSOLPOS): YearError, YearWarn or YearExtend (switch to AlgorithmSPA), DEFAULT = YearError */
+	GetYearPolicy() YearPolicy
+	SetYearPolicy(policy YearPolicy)
+	/* I:             Calendar of the date inputs, setting it keeps the instant of the date, DEFAULT = CalendarGregorian */
+	GetCalendar() Calendar
+	SetCalendar(calendar Calendar)
+	// helper function returning an error wrapping ErrNotComputed if one of the given functions did not run in the last calculation
+	Computed(function SPFunctions) error
+	// helper function returning an output of the last calculation by name (ResultColumns or a transitional variable like "julday"), an error wrapping ErrNotComputed if its function did not run
+	GetOutput(name string) (float64, error)
+	// helper function returning the near-degenerate conditions (flag values, undefined outputs) of the last calculation
+	GetWarnings() []Warning
+	// helper function returning the incidence angle and extraterrestrial irradiance of the last calculation on a surface given by its normal vector (east, north, up)
+	PositionOnSurface(normal [3]float64) (SurfaceIncidence, error)
+	// helper function calculating the day of t and returning the signed time from t until sunset (negative after sunset), an error wrapping ErrPolarDay or ErrPolarNight without sunset
+	MinutesUntilSunset(t time.Time) (time.Duration, error)
+	// helper function calculating the day of t and returning the signed time since sunrise until t (negative before sunrise), an error wrapping ErrPolarDay or ErrPolarNight without sunrise
+	MinutesSinceSunrise(t time.Time) (time.Duration, error)
+	// helper function calculating the day of t and returning the signed time from t until solar noon (negative in the afternoon)
+	TimeToSolarNoon(t time.Time) (time.Duration, error)
+	// helper function searching forward day by day (across midnight and polar periods) for the first sunrise after the given time, ErrNoEvent if there is none within a year; the inputs and outputs are left as they are
+	NextSunrise(after time.Time) (time.Time, error)
+	// helper function searching forward day by day (across midnight and polar periods) for the first sunset after the given time, ErrNoEvent if there is none within a year; the inputs and outputs are left as they are
+	NextSunset(after time.Time) (time.Time, error)
+	// helper function calculating the day of date and returning when the refracted elevation of the sun passes the given elevation (degrees), rising in the morning or setting in the evening, an error wrapping ErrNoCrossing if it does not
+	TimeAtElevation(date time.Time, elevation float64, rising bool) (time.Time, error)
+	// helper function returning the first time on the (local) day of date at which the sun is above the horizon at the given azimuth (degrees, N=0, E=90, S=180, W=270), an error wrapping ErrNoCrossing if it never is
+	TimeAtAzimuth(date time.Time, azimuth float64) (time.Time, error)
+	// helper function returning the latitude and longitude (degrees north and east) of the point where the sun is at zenith at the instant of the last calculation, from the declination and the Greenwich hour angle
+	GetSubsolarPoint() (latitude float64, longitude float64)
+	// helper function returning the unit vector (east, north, up) toward the refraction corrected sun position of the last calculation
+	SunVectorENU() [3]float64
+	// helper function returning the unit vector toward the refraction corrected sun position of the last calculation in Earth-centered, Earth-fixed coordinates
+	SunVectorECEF() [3]float64
+	// helper function returning the cosine of the incidence angle and the ETR on many surfaces from the sun geometry of the last calculation, like Cosinc and Etrtilt for Tilt and Aspect
+	IncidenceOn(surfaces []Surface) []TiltResult
+	// helper function returning when the sun rises above the horizon line of Horizon on the (local) day of the last calculation, an error wrapping ErrNoCrossing if it does not
+	EffectiveSunrise() (time.Time, error)
+	// helper function returning when the sun sets behind the horizon line of Horizon on the (local) day of the last calculation, an error wrapping ErrNoCrossing if it does not
+	EffectiveSunset() (time.Time, error)
 }
 
 // NewSolpos creates new instance of Solpos
 func NewSolpos(dt time.Time, latitude float64, longitude float64, optionalParameters map[string]interface{}) (Solpos, error) {
-	var sp solpos
+	var sp PosData
 	sp.setTrigdata(trigdata{1.0, 1.0, 1.0, -999.0, 1.0})
-	sp.init()
+	sp.Init()
 	sp.Latitude = latitude
 	sp.Longitude = longitude
+	if calendar, ok := optionalParameters["calendar"].(Calendar); ok {
+		/* the date fields of dt are set in the calendar */
+		sp.Calendar = calendar
+	}
+	if altitude, ok := optionalParameters["altitude"].(float64); ok && altitude != 0.0 {
+		/* the barometric pressure of the altitude, unless press is given as well */
+		sp.SetAltitude(altitude)
+	}
 	sp.SetDate(dt)
 	for key, value := range optionalParameters {
 		switch key {
 		case "press":
-			tmpValue, ok := value.(float64)
+			tmpValue, ok := millibars(value)
 			if !ok {
-				err := errors.New("wrong type press, expected float64")
+				err := errors.New("wrong type press, expected float64 or Millibars")
 				return nil, err
 			}
 			sp.Press = tmpValue
 		case "temp":
-			tmpValue, ok := value.(float64)
+			tmpValue, ok := celsius(value)
 			if !ok {
-				err := errors.New("wrong type temp, expected float64")
+				err := errors.New("wrong type temp, expected float64 or Celsius")
 				return nil, err
 			}
 			sp.Temp = tmpValue
 		case "tilt":
-			tmpValue, ok := value.(float64)
+			tmpValue, ok := degrees(value)
 			if !ok {
-				err := errors.New("wrong type tilt, expected float64")
+				err := errors.New("wrong type tilt, expected float64 or Degrees")
 				return nil, err
 			}
 			sp.Tilt = tmpValue
 
 		case "aspect":
-			tmpValue, ok := value.(float64)
+			tmpValue, ok := degrees(value)
 			if !ok {
-				err := errors.New("wrong type aspect, expected float64")
+				err := errors.New("wrong type aspect, expected float64 or Degrees")
 				return nil, err
 			}
 			sp.Aspect = tmpValue
+		case "sbwid":
+			tmpValue, ok := centimeters(value)
+			if !ok {
+				err := errors.New("wrong type sbwid, expected float64 or Centimeters")
+				return nil, err
+			}
+			sp.Sbwid = tmpValue
+		case "sbrad":
+			tmpValue, ok := centimeters(value)
+			if !ok {
+				err := errors.New("wrong type sbrad, expected float64 or Centimeters")
+				return nil, err
+			}
+			sp.Sbrad = tmpValue
 		case "month":
 			tmpValue, ok := value.(int)
 			if !ok {
@@ -312,13 +470,118 @@ func NewSolpos(dt time.Time, latitude float64, longitude float64, optionalParame
 				return nil, err
 			}
 			sp.Function = tmpValue
+		case "shadowband":
+			tmpValue, ok := value.(ShadowBand)
+			if !ok {
+				err := errors.New("wrong type shadowband, expected ShadowBand")
+				return nil, err
+			}
+			sp.SetShadowBand(tmpValue)
+		case "sbmodel":
+			tmpValue, ok := value.(ShadowBandModel)
+			if !ok {
+				err := errors.New("wrong type sbmodel, expected ShadowBandModel")
+				return nil, err
+			}
+			sp.ShadowBandModel = tmpValue
+		case "tsi":
+			tmpValue, ok := value.(TSI)
+			if !ok {
+				err := errors.New("wrong type tsi, expected TSI")
+				return nil, err
+			}
+			sp.TSI = tmpValue
+		case "atmosphere":
+			tmpValue, ok := value.(AtmosphereProvider)
+			if !ok {
+				err := errors.New("wrong type atmosphere, expected AtmosphereProvider")
+				return nil, err
+			}
+			sp.Atmosphere = tmpValue
+		case "eventformat":
+			tmpValue, ok := value.(EventFormat)
+			if !ok {
+				err := errors.New("wrong type eventformat, expected EventFormat")
+				return nil, err
+			}
+			sp.EventFormat = tmpValue
+		case "horizon":
+			tmpValue, ok := value.(HorizonProfile)
+			if !ok {
+				err := errors.New("wrong type horizon, expected HorizonProfile")
+				return nil, err
+			}
+			sp.Horizon = tmpValue
+		case "dualaxis":
+			tmpValue, ok := value.(*DualAxis)
+			if !ok {
+				err := errors.New("wrong type dualaxis, expected *DualAxis")
+				return nil, err
+			}
+			sp.DualAxis = tmpValue
+		case "yearpolicy":
+			tmpValue, ok := value.(YearPolicy)
+			if !ok {
+				err := errors.New("wrong type yearpolicy, expected YearPolicy")
+				return nil, err
+			}
+			sp.YearPolicy = tmpValue
+		case "calendar":
+			tmpValue, ok := value.(Calendar)
+			if !ok {
+				err := errors.New("wrong type calendar, expected Calendar")
+				return nil, err
+			}
+			sp.Calendar = tmpValue
+		case "algorithm":
+			tmpValue, ok := value.(Algorithm)
+			if !ok {
+				err := errors.New("wrong type algorithm, expected Algorithm")
+				return nil, err
+			}
+			sp.Algorithm = tmpValue
+		case "deltat":
+			tmpValue, ok := value.(float64)
+			if !ok {
+				err := errors.New("wrong type deltat, expected float64")
+				return nil, err
+			}
+			sp.DeltaT = tmpValue
+		case "deltatsource":
+			tmpValue, ok := value.(DeltaTProvider)
+			if !ok {
+				err := errors.New("wrong type deltatsource, expected DeltaTProvider")
+				return nil, err
+			}
+			sp.DeltaTSource = tmpValue
+		case "precise":
+			tmpValue, ok := value.(bool)
+			if !ok {
+				err := errors.New("wrong type precise, expected bool")
+				return nil, err
+			}
+			sp.Precise = tmpValue
+		case "strict":
+			tmpValue, ok := value.(bool)
+			if !ok {
+				err := errors.New("wrong type strict, expected bool")
+				return nil, err
+			}
+			sp.Strict = tmpValue
+		case "altitude":
+			if _, ok := value.(float64); !ok {
+				err := errors.New("wrong type altitude, expected float64")
+				return nil, err
+			}
 		}
 	}
 	return &sp, sp.Calculate()
 
 }
 
-type solpos struct {
+// PosData holds the inputs, transitional and output variables of a calculation like the posdata struct of the C code. Use it
+// directly (Init, set the inputs, Calculate, read the outputs) or through the Solpos interface, which it implements.
+type PosData struct {
 	Day       int         // Day of month (May 27 = 27, etc.) solpos will CALCULATE this by default, or will optionally require it as input depending on the setting of the S_DOY  function switch.
 	Daynum    int         // Day number (day of year; Feb 1 = 32 )	solpos REQUIRES this by default, but will optionally calculate it from month and day depending on the setting of the S_DOY function switch.
 	Function  SPFunctions // Switch to choose functions for desired output.
@@ -374,41 +637,76 @@ type solpos struct {
 	Utime     float64     // Universal (Greenwich) standard time */
 	Zenetr    float64     // Solar zenith angle, no atmospheric correction (= ETR) */
 	Zenref    float64     // Solar zenith angle, deg. from zenith, refracted */
+	Strict    bool        // Outputs of functions not enabled in the last calculation are NaN instead of stale values
 	Tdat      trigdata
-}
-
-func (sp *solpos) GetSunrise() time.Time {
-	h, m, s := sp.calculateHourMinSec(sp.Sretr)
-	dt := time.Date(sp.Year, time.Month(sp.Month), sp.Day, 0, 0, 0, 0, time.FixedZone("ManualTimeZone", int(sp.Timezone*3600)))
-	return dt.Add(time.Hour*time.Duration(h) +
+	computed  SPFunctions // functions run by the last successful calculation
+	warnings  []Warning   // near-degenerate conditions of the last successful calculation
+
+	YearPolicy      YearPolicy         // Handling of years outside 1950-2050, the limits of the algorithm
+	ShadowBandModel ShadowBandModel    // Shadow-band correction model, DEFAULT (nil) = DrummondModel
+	TSI             TSI                // Source of the solar constant by date, DEFAULT (nil) = the fixed Solcon
+	Atmosphere      AtmosphereProvider // Source of Press and Temp by date, DEFAULT (nil) = the fixed Press and Temp
+	EventFormat     EventFormat        // Representation of event times of GetSunriseAs and GetSunsetAs, DEFAULT = EventTime
+	Calendar        Calendar           // Calendar of Year, Month, Day and Daynum, DEFAULT = CalendarGregorian
+	Location        *time.Location     // Location (time zone with DST rules) of the date fields, nil = the fixed Timezone
+	DualAxis        *DualAxis          // Dual-axis tracker setting Tilt and Aspect in every calculation, DEFAULT (nil) = the fixed Tilt and Aspect
+	Horizon         HorizonProfile     // Local horizon line (terrain, buildings), DEFAULT (nil) = the flat horizon
+	SunObstructed   bool               // Refracted sun below the horizon line of Horizon
+	Altitude        float64            // Altitude of the observer above sea level, meters, DEFAULT (0) = sea level
+	Algorithm       Algorithm          // Solar position algorithm of the basic geometry, DEFAULT = AlgorithmSOLPOS
+	DeltaT          float64            // TT - UT1, seconds, applied to the ecliptic time, DEFAULT (0) = neglected like in the C code
+	DeltaTSource    DeltaTProvider     // Source of DeltaT by date, DEFAULT (nil) = the fixed DeltaT
+	Precise         bool               // Nutation, annual aberration and parallax in the coordinates of AlgorithmSOLPOS, DEFAULT = false
+	daynumInput     bool               // Daynum was set by SetDaynum after the last SetDate, SetMonth or SetDay
+	shared          *sharedGeometry    // ecliptic geometry shared with other sites of a fleet, nil = not shared
+}
+
+func (sp *PosData) GetSunrise() time.Time {
+	return minutesToTime(sp.Getdate(), sp.Sretr)
+}
+
+// minutesToTime converts minutes from midnight (like sretr and ssetr) at the UTC offset of dt to a time on the day of dt, in the location of dt
+func minutesToTime(dt time.Time, decMinutes float64) time.Time {
+	h, m, s := calculateHourMinSec(decMinutes)
+	return standardMidnight(dt).Add(time.Hour*time.Duration(h) +
 		time.Minute*time.Duration(m) +
-		time.Second*time.Duration(s))
+		time.Second*time.Duration(s)).In(dt.Location())
+}
 
+// standardMidnight returns midnight of the day of dt at the UTC offset of dt, the origin of minutes from midnight like sretr
+// and ssetr, which are local standard time of the calculation even on days of a DST change
+func standardMidnight(dt time.Time) time.Time {
+	_, offset := dt.Zone()
+	return time.Date(dt.Year(), dt.Month(), dt.Day(), 0, 0, 0, 0, time.FixedZone("", offset))
 }
-func (sp *solpos) calculateHourMinSec(decMinutes float64) (hours int, minutes int, seconds int) {
+
+func calculateHourMinSec(decMinutes float64) (hours int, minutes int, seconds int) {
 	hour := decMinutes / 60
 	hours = int(math.Floor(hour))
 	minutes = int(math.Floor(60 * (hour - float64(hours))))
-	seconds = int((60.0 * (hour - float64(hours))) - float64(minutes)/60)
+	seconds = int(60.0 * (60.0*(hour-float64(hours)) - float64(minutes)))
 	if seconds < 0 {
 		seconds = 0
 	}
 	return
 }
 
-func (sp *solpos) GetSunset() time.Time {
-	h, m, s := sp.calculateHourMinSec(sp.Ssetr)
-	dt := time.Date(sp.Year, time.Month(sp.Month), sp.Day, 0, 0, 0, 0, time.FixedZone("ManualTimeZone", int(sp.Timezone*3600)))
-	return dt.Add(time.Hour*time.Duration(h) +
-		time.Minute*time.Duration(m) +
-		time.Second*time.Duration(s))
+func (sp *PosData) GetSunset() time.Time {
+	return minutesToTime(sp.Getdate(), sp.Ssetr)
 }
 
-func (sp *solpos) Getdate() time.Time {
-	return time.Date(sp.Year, time.Month(sp.Month), sp.Day, sp.Hour, sp.Minute, sp.Second, 0, time.FixedZone("ManualTimeZone", int(sp.Timezone*3600)))
+func (sp *PosData) Getdate() time.Time {
+	loc := sp.Location
+	if loc == nil {
+		loc = time.FixedZone("ManualTimeZone", int(math.Round(sp.Timezone*3600.0)))
+	}
+	if sp.Calendar != CalendarGregorian {
+		return sp.Calendar.Time(sp.Year, time.Month(sp.Month), sp.Day, sp.Hour, sp.Minute, sp.Second, loc)
+	}
+	return time.Date(sp.Year, time.Month(sp.Month), sp.Day, sp.Hour, sp.Minute, sp.Second, 0, loc)
 }
 
-func (sp *solpos) SetDate(dt time.Time) {
+func (sp *PosData) SetDate(dt time.Time) {
 	_, offset := dt.Zone()
 	sp.Year = dt.Year()
 	sp.Month = int(dt.Month())
@@ -417,313 +715,331 @@ func (sp *solpos) SetDate(dt time.Time) {
 	sp.Hour = dt.Hour()
 	sp.Minute = dt.Minute()
 	sp.Second = dt.Second()
-	sp.Timezone = float64(offset / 3600)
+	sp.Timezone = float64(offset) / 3600.0 /* fractional for offsets like +5:30 or -3:30 */
+	sp.Location = dt.Location()
+	sp.daynumInput = false
+	if sp.Calendar != CalendarGregorian {
+		sp.setCalendarDate(dt)
+	}
 }
 
-func (sp *solpos) SetDay(day int) {
+func (sp *PosData) SetDay(day int) {
 	sp.Day = day
+	sp.daynumInput = false
 }
 
-func (sp *solpos) SetDaynum(daynum int) {
+func (sp *PosData) SetDaynum(daynum int) {
 	sp.Daynum = daynum
+	sp.daynumInput = true
 }
 
-func (sp *solpos) SetFunction(function SPFunctions) {
+func (sp *PosData) SetFunction(function SPFunctions) {
 	sp.Function = function
 }
 
-func (sp *solpos) SetHour(hour int) {
+func (sp *PosData) SetHour(hour int) {
 	sp.Hour = hour
 }
 
-func (sp *solpos) SetInterval(interval int) {
+func (sp *PosData) SetInterval(interval int) {
 	sp.Interval = interval
 }
 
-func (sp *solpos) SetMinute(minute int) {
+func (sp *PosData) SetMinute(minute int) {
 	sp.Minute = minute
 }
 
-func (sp *solpos) SetMonth(month int) {
+func (sp *PosData) SetMonth(month int) {
 	sp.Month = month
+	sp.daynumInput = false
 }
 
-func (sp *solpos) SetSecond(second int) {
+func (sp *PosData) SetSecond(second int) {
 	sp.Second = second
 }
 
-func (sp *solpos) SetYear(year int) {
+func (sp *PosData) SetYear(year int) {
 	sp.Year = year
 }
 
-func (sp *solpos) SetAspect(aspect float64) {
+func (sp *PosData) SetAspect(aspect float64) {
 	sp.Aspect = aspect
 }
 
-func (sp *solpos) SetLatitude(latitude float64) {
+func (sp *PosData) SetLatitude(latitude float64) {
 	sp.Latitude = latitude
 }
 
-func (sp *solpos) SetLongitude(longitude float64) {
+func (sp *PosData) SetLongitude(longitude float64) {
 	sp.Longitude = longitude
 }
 
-func (sp *solpos) SetPress(press float64) {
+func (sp *PosData) SetPress(press float64) {
 	sp.Press = press
 }
 
-func (sp *solpos) SetSbwid(sbwid float64) {
+func (sp *PosData) SetSbwid(sbwid float64) {
 	sp.Sbwid = sbwid
 }
 
-func (sp *solpos) SetSbrad(sbrad float64) {
+func (sp *PosData) SetSbrad(sbrad float64) {
 	sp.Sbrad = sbrad
 }
 
-func (sp *solpos) SetSbsky(sbsky float64) {
+func (sp *PosData) SetSbsky(sbsky float64) {
 	sp.Sbsky = sbsky
 }
 
-func (sp *solpos) SetSolcon(solcon float64) {
+func (sp *PosData) SetSolcon(solcon float64) {
 	sp.Solcon = solcon
 }
 
-func (sp *solpos) SetTemp(temp float64) {
+func (sp *PosData) SetTemp(temp float64) {
 	sp.Temp = temp
 }
 
-func (sp *solpos) SetTilt(tilt float64) {
+func (sp *PosData) SetTilt(tilt float64) {
 	sp.Tilt = tilt
 }
 
-func (sp *solpos) SetTimezone(timezone float64) {
+func (sp *PosData) SetTimezone(timezone float64) {
 	sp.Timezone = timezone
+	/* a fixed offset replaces the location */
+	sp.Location = nil
 }
 
-func (sp *solpos) SetZenref(zenref float64) {
+func (sp *PosData) SetLocation(loc *time.Location) {
+	sp.Location = loc
+}
+
+func (sp *PosData) GetLocation() *time.Location {
+	return sp.Location
+}
+
+func (sp *PosData) SetZenref(zenref float64) {
 	sp.Zenref = zenref
 }
 
-func (sp *solpos) setTrigdata(tdat trigdata) {
+func (sp *PosData) setTrigdata(tdat trigdata) {
 	sp.Tdat = tdat
 }
-func (sp *solpos) GetDay() int {
+func (sp *PosData) GetDay() int {
 	return sp.Day
 }
 
-func (sp *solpos) GetDaynum() int {
+func (sp *PosData) GetDaynum() int {
 	return sp.Daynum
 }
 
-func (sp *solpos) GetFunction() SPFunctions {
+func (sp *PosData) GetFunction() SPFunctions {
 	return sp.Function
 }
 
-func (sp *solpos) GetHour() int {
+func (sp *PosData) GetHour() int {
 	return sp.Hour
 }
 
-func (sp *solpos) GetInterval() int {
+func (sp *PosData) GetInterval() int {
 	return sp.Interval
 }
 
-func (sp *solpos) GetMinute() int {
+func (sp *PosData) GetMinute() int {
 	return sp.Minute
 }
 
-func (sp *solpos) GetMonth() int {
+func (sp *PosData) GetMonth() int {
 	return sp.Month
 }
 
-func (sp *solpos) GetSecond() int {
+func (sp *PosData) GetSecond() int {
 	return sp.Second
 }
 
-func (sp *solpos) GetYear() int {
+func (sp *PosData) GetYear() int {
 	return sp.Year
 }
 
-func (sp *solpos) GetAmass() float64 {
+func (sp *PosData) GetAmass() float64 {
 	return sp.Amass
 }
 
-func (sp *solpos) GetAmpress() float64 {
+func (sp *PosData) GetAmpress() float64 {
 	return sp.Ampress
 }
 
-func (sp *solpos) GetAspect() float64 {
+func (sp *PosData) GetAspect() float64 {
 	return sp.Aspect
 }
 
-func (sp *solpos) GetAzim() float64 {
+func (sp *PosData) GetAzim() float64 {
 	return sp.Azim
 }
 
-func (sp *solpos) GetCosinc() float64 {
+func (sp *PosData) GetCosinc() float64 {
 	return sp.Cosinc
 }
 
-func (sp *solpos) GetCoszen() float64 {
+func (sp *PosData) GetCoszen() float64 {
 	return sp.Coszen
 }
 
-func (sp *solpos) GetDayang() float64 {
+func (sp *PosData) GetDayang() float64 {
 	return sp.Dayang
 }
 
-func (sp *solpos) GetDeclin() float64 {
+func (sp *PosData) GetDeclin() float64 {
 	return sp.Declin
 }
 
-func (sp *solpos) GetEclong() float64 {
+func (sp *PosData) GetEclong() float64 {
 	return sp.Eclong
 }
 
-func (sp *solpos) GetEcobli() float64 {
+func (sp *PosData) GetEcobli() float64 {
 	return sp.Ecobli
 }
 
-func (sp *solpos) GetEctime() float64 {
+func (sp *PosData) GetEctime() float64 {
 	return sp.Ectime
 }
 
-func (sp *solpos) GetElevetr() float64 {
+func (sp *PosData) GetElevetr() float64 {
 	return sp.Elevetr
 }
 
-func (sp *solpos) GetElevref() float64 {
+func (sp *PosData) GetElevref() float64 {
 	return sp.Elevref
 }
 
-func (sp *solpos) GetEqntim() float64 {
+func (sp *PosData) GetEqntim() float64 {
 	return sp.Eqntim
 }
 
-func (sp *solpos) GetErv() float64 {
+func (sp *PosData) GetErv() float64 {
 	return sp.Erv
 }
 
-func (sp *solpos) GetEtr() float64 {
+func (sp *PosData) GetEtr() float64 {
 	return sp.Etr
 }
 
-func (sp *solpos) GetEtrn() float64 {
+func (sp *PosData) GetEtrn() float64 {
 	return sp.Etrn
 }
 
-func (sp *solpos) GetEtrtilt() float64 {
+func (sp *PosData) GetEtrtilt() float64 {
 	return sp.Etrtilt
 }
 
-func (sp *solpos) GetGmst() float64 {
+func (sp *PosData) GetGmst() float64 {
 	return sp.Gmst
 }
 
-func (sp *solpos) GetHrang() float64 {
+func (sp *PosData) GetHrang() float64 {
 	return sp.Hrang
 }
 
-func (sp *solpos) GetJulday() float64 {
+func (sp *PosData) GetJulday() float64 {
 	return sp.Julday
 }
 
-func (sp *solpos) GetLatitude() float64 {
+func (sp *PosData) GetLatitude() float64 {
 	return sp.Latitude
 }
 
-func (sp *solpos) GetLongitude() float64 {
+func (sp *PosData) GetLongitude() float64 {
 	return sp.Longitude
 }
 
-func (sp *solpos) GetLmst() float64 {
+func (sp *PosData) GetLmst() float64 {
 	return sp.Lmst
 }
 
-func (sp *solpos) GetMnanom() float64 {
+func (sp *PosData) GetMnanom() float64 {
 	return sp.Mnanom
 }
 
-func (sp *solpos) GetMnlong() float64 {
+func (sp *PosData) GetMnlong() float64 {
 	return sp.Mnlong
 }
 
-func (sp *solpos) GetRascen() float64 {
+func (sp *PosData) GetRascen() float64 {
 	return sp.Rascen
 }
 
-func (sp *solpos) GetPress() float64 {
+func (sp *PosData) GetPress() float64 {
 	return sp.Press
 }
 
-func (sp *solpos) GetPrime() float64 {
+func (sp *PosData) GetPrime() float64 {
 	return sp.Prime
 }
 
-func (sp *solpos) GetSbcf() float64 {
+func (sp *PosData) GetSbcf() float64 {
 	return sp.Sbcf
 }
 
-func (sp *solpos) GetSbwid() float64 {
+func (sp *PosData) GetSbwid() float64 {
 	return sp.Sbwid
 }
 
-func (sp *solpos) GetSbrad() float64 {
+func (sp *PosData) GetSbrad() float64 {
 	return sp.Sbrad
 }
 
-func (sp *solpos) GetSbsky() float64 {
+func (sp *PosData) GetSbsky() float64 {
 	return sp.Sbsky
 }
 
-func (sp *solpos) GetSolcon() float64 {
+func (sp *PosData) GetSolcon() float64 {
 	return sp.Solcon
 }
 
-func (sp *solpos) GetSsha() float64 {
+func (sp *PosData) GetSsha() float64 {
 	return sp.Ssha
 }
 
-func (sp *solpos) GetSretr() float64 {
+func (sp *PosData) GetSretr() float64 {
 	return sp.Sretr
 }
 
-func (sp *solpos) GetSsetr() float64 {
+func (sp *PosData) GetSsetr() float64 {
 	return sp.Ssetr
 }
 
-func (sp *solpos) GetTemp() float64 {
+func (sp *PosData) GetTemp() float64 {
 	return sp.Temp
 }
 
-func (sp *solpos) GetTilt() float64 {
+func (sp *PosData) GetTilt() float64 {
 	return sp.Tilt
 }
 
-func (sp *solpos) GetTimezone() float64 {
+func (sp *PosData) GetTimezone() float64 {
 	return sp.Timezone
 }
 
-func (sp *solpos) GetTst() float64 {
+func (sp *PosData) GetTst() float64 {
 	return sp.Tst
 }
 
-func (sp *solpos) GetTstfix() float64 {
+func (sp *PosData) GetTstfix() float64 {
 	return sp.Tstfix
 }
 
-func (sp *solpos) GetUnprime() float64 {
+func (sp *PosData) GetUnprime() float64 {
 	return sp.Unprime
 }
 
-func (sp *solpos) GetUtime() float64 {
+func (sp *PosData) GetUtime() float64 {
 	return sp.Utime
 }
 
-func (sp *solpos) GetZenetr() float64 {
+func (sp *PosData) GetZenetr() float64 {
 	return sp.Zenetr
 }
 
-func (sp *solpos) GetZenref() float64 {
+func (sp *PosData) GetZenref() float64 {
 	return sp.Zenref
 }
 
@@ -766,11 +1082,49 @@ func (sp *solpos) GetZenref() float64 {
 *        everything defined at the top of this listing.
 *----------------------------------------------------------------------------*/
 
-func (sp *solpos) Calculate() error {
-	// renew the date
+func (sp *PosData) Calculate() error {
+	/* month and day are the date unless the day of year was set as input with S_DOY */
+	if sp.Function.HasFlag(LDoy) && sp.daynumInput {
+		/* convert input doy to month-day */
+		month, day, err := sp.Calendar.monthDay(sp.Year, sp.Daynum)
+		if err != nil {
+			return err
+		}
+		sp.Month, sp.Day = month, day
+	} else {
+		/* reject month-days which do not exist before the date is renewed and would roll over */
+		daynum, err := sp.Calendar.dayOfYear(sp.Year, sp.Month, sp.Day)
+		if err != nil {
+			return err
+		}
+		sp.Daynum = daynum
+	}
+	sp.foldLeapSecond()
+	err := sp.normalizeHour24()
+	if err != nil {
+		return err
+	}
+	// renew the date, which also sets the day of year
 	sp.SetDate(sp.Getdate())
+	// reset the local trig cache, it belongs to a single calculation
+	sp.setTrigdata(trigdata{1.0, 1.0, 1.0, -999.0, 1.0})
+	sp.computed = 0
+	sp.warnings = nil
+	if sp.TSI != nil {
+		sp.Solcon = sp.TSI.At(sp.Getdate())
+	}
+	if sp.Atmosphere != nil {
+		sp.Press, sp.Temp = sp.Atmosphere.Atmosphere(sp.Getdate())
+	}
+	if sp.DeltaTSource != nil {
+		sp.DeltaT = sp.DeltaTSource.DeltaT(sp.Getdate())
+	}
 	/* validate the inputs */
-	err := sp.validate()
+	err = sp.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.checkInputs()
 	if err != nil {
 		return err
 	}
@@ -778,14 +1132,6 @@ func (sp *solpos) Calculate() error {
 		return errors.New("No function set")
 	}
 
-	if sp.Function.HasFlag(LDoy) {
-		/* convert input doy to month-day */
-		sp.doy2dom()
-	} else {
-		/* convert input month-day to doy */
-		sp.dom2doy()
-	}
-
 	if sp.Function.HasFlag(LGeom) {
 		/* do basic geometry calculations */
 		sp.geometry()
@@ -803,7 +1149,10 @@ func (sp *solpos) Calculate() error {
 
 	if sp.Function.HasFlag(LSbcf) {
 		/* Shadowband correction factor */
-		sp.sbcf()
+		err = sp.sbcf()
+		if err != nil {
+			return err
+		}
 	}
 
 	if sp.Function.HasFlag(LTst) {
@@ -824,18 +1173,32 @@ func (sp *solpos) Calculate() error {
 	if sp.Function.HasFlag(LRefrac) {
 		/* atmospheric refraction calculations */
 
-		sp.refrac()
+		err = sp.refrac()
+		if err != nil {
+			return err
+		}
+	}
+
+	if sp.Function.HasFlag(LSolazm) && sp.Function.HasFlag(LRefrac) {
+		/* sun behind the local horizon line */
+		sp.SunObstructed = sp.Elevref < sp.Horizon.ElevationAt(sp.Azim)
 	}
 
 	if sp.Function.HasFlag(LAmass) {
 
 		/* airmass calculations */
-		sp.amass()
+		err = sp.amass()
+		if err != nil {
+			return err
+		}
 	}
 
 	if sp.Function.HasFlag(LPrime) {
 		/* kt-prime/unprime calculations */
-		sp.prime()
+		err = sp.prime()
+		if err != nil {
+			return err
+		}
 	}
 
 	if sp.Function.HasFlag(LEtr) {
@@ -844,10 +1207,23 @@ func (sp *solpos) Calculate() error {
 	}
 
 	if sp.Function.HasFlag(LTilt) {
+		if sp.DualAxis != nil {
+			/* the tracker points the panel at the sun of this calculation */
+			sp.Tilt, sp.Aspect = sp.DualAxis.orientation(sp.Zenref, sp.Azim)
+		}
 		/* tilt calculations */
 		sp.tilt()
 	}
 
+	err = sp.checkOutputs()
+	if err != nil {
+		return err
+	}
+	sp.computed = sp.Function & outputFunctions
+	if sp.Strict {
+		sp.invalidate(outputFunctions &^ sp.computed)
+	}
+	sp.checkWarnings()
 	return nil
 }
 
@@ -864,7 +1240,7 @@ func (sp *solpos) Calculate() error {
 *    Returns: Void
 *
 *----------------------------------------------------------------------------*/
-func (sp *solpos) init() {
+func (sp *PosData) Init() {
 	sp.Day = -99              /* Day of month (May 27 = 27, etc.) */
 	sp.Daynum = -999          /* Day number (day of year; Feb 1 = 32 ) */
 	sp.Hour = -99             /* Hour of day, 0 - 23 */
@@ -915,14 +1291,14 @@ const raddeg float64 = 0.0174532925 /* converts from degrees to radians */
 /*============================================================================
  *    Local function prototypes
  ============================================================================*/
-func (sp *solpos) validate() error {
+func (sp *PosData) validate() error {
 
 	/* No absurd dates, please. */
 	if sp.Function.HasFlag(LGeom) {
 
-		if (sp.Year < 1950) || (sp.Year > 2050) { /* limits of algorithm */
+		if first, last := sp.algorithm().years(); ((sp.Year < first) || (sp.Year > last)) && sp.YearPolicy != YearWarn { /* limits of algorithm */
 
-			return errors.New("Please fix the year: [1950-2050]")
+			return sp.algorithm().yearError()
 		}
 		if !(sp.Function.HasFlag(SDoy)) && ((sp.Month < 1) || (sp.Month > 12)) {
 			return errors.New("Please fix the month [1-12]")
@@ -952,8 +1328,8 @@ func (sp *solpos) validate() error {
 
 			return errors.New("Please fix hour and second")
 		}
-		if math.Abs(sp.Timezone) > 12.0 {
-			return errors.New("Please fix timezone [-12 - +12]")
+		if (sp.Timezone < -12.0) || (sp.Timezone > 14.0) { /* UTC-12 to UTC+14 (Line Islands) */
+			return errors.New("Please fix timezone [-12 - +14]")
 		}
 		if (sp.Interval < 0) || (sp.Interval > 28800) {
 			return errors.New("Please fix interval (seconds) [0 - 28800]")
@@ -966,6 +1342,9 @@ func (sp *solpos) validate() error {
 		if math.Abs(sp.Latitude) > 90.0 {
 			return errors.New("Please fix latitude [-90 - +90]")
 		}
+		if (sp.Function.HasFlag(LSrss)) && !((sp.Altitude >= -500.0) && (sp.Altitude <= 20000.0)) {
+			return errors.New("Please fix altitude [-500 - 20000]")
+		}
 		return nil
 	}
 
@@ -975,7 +1354,7 @@ func (sp *solpos) validate() error {
 	}
 
 	if (sp.Function.HasFlag(LRefrac)) &&
-		(sp.Press < 0.0) || (sp.Press > 2000.0) {
+		((sp.Press < 0.0) || (sp.Press > 2000.0)) {
 		return errors.New("Please fix press [0-2000]")
 	}
 
@@ -990,11 +1369,11 @@ func (sp *solpos) validate() error {
 
 	/* No oddball shadowbands, please */
 	if (sp.Function.HasFlag(LSbcf)) &&
-		(sp.Sbwid < 1.0) || (sp.Sbwid > 100.0) {
+		((sp.Sbwid < 1.0) || (sp.Sbwid > 100.0)) {
 		return errors.New("Please fix shadow band width cm [1-100]")
 	}
 
-	if (sp.Function.HasFlag(LSbcf)) && (sp.Sbrad < 1.0) || (sp.Sbrad > 100.0) {
+	if (sp.Function.HasFlag(LSbcf)) && ((sp.Sbrad < 1.0) || (sp.Sbrad > 100.0)) {
 		return errors.New("Please fix shadow band radius (cm) [1-100]")
 	}
 
@@ -1005,90 +1384,27 @@ func (sp *solpos) validate() error {
 	return nil
 }
 
-/*============================================================================
- *    Local Void function dom2doy
- *
- *    Converts day-of-month to day-of-year
- *
- *    Requires (from struct posdata parameter):
- *            year
- *            month
- *            day
- *
- *    Returns (via the struct posdata parameter):
- *            year
- *            daynum
- *----------------------------------------------------------------------------*/
-func (sp *solpos) dom2doy() {
-	sp.Daynum = sp.Day + monthDays[0][sp.Month]
-
-	/* (adjust for leap year) */
-	if sp.Year%4 == 0 && (sp.Year%100 != 0 || sp.Year%400 == 0) && sp.Month > 2 {
-		sp.Daynum += 1
-	}
-
-}
-
-/*============================================================================
- *    Local void function doy2dom
- *
- *    This function computes the month/day from the day number.
- *
- *    Requires (from struct posdata parameter):
- *        Year and day number:
- *            year
- *            daynum
- *
- *    Returns (via the struct posdata parameter):
- *            year
- *            month
- *            day
- *----------------------------------------------------------------------------*/
-func (sp *solpos) doy2dom() {
-	var imon int /* Month (month_days) array counter */
-	var leap int /* leap year switch */
-
-	/* Set the leap year switch */
-	if ((sp.Year % 4) == 0) &&
-		(((sp.Year % 100) != 0) || ((sp.Year % 400) == 0)) {
-		leap = 1
-	} else {
-		leap = 0
-	}
-
-	/* Find the month */
-	imon = 12
-
-	for sp.Daynum <= monthDays[leap][imon] {
-		imon--
-	}
-
-	/* Set the month and day of month */
-	sp.Month = imon
-	sp.Day = sp.Daynum - monthDays[leap][imon]
-
-}
-
 /*============================================================================
  *    Local Void function geometry
  *
  *    Does the underlying geometry for a given time and location
  *----------------------------------------------------------------------------*/
-func (sp *solpos) geometry() {
-	var bottom float64 /* denominator (bottom) of the fraction */
-	var c2 float64     /* cosine of d2 */
-	var cd float64     /* cosine of the day angle or delination */
-	var d2 float64     /* pdat->dayang times two */
-	var delta float64  /* difference between current year and 1949 */
-	var s2 float64     /* sine of d2 */
-	var sd float64     /* sine of the day angle */
-	var top float64    /* numerator (top) of the fraction */
-	var leap int       /* leap year counter */
+func (sp *PosData) geometry() {
+	var c2 float64    /* cosine of d2 */
+	var cd float64    /* cosine of the day angle or delination */
+	var d2 float64    /* pdat->dayang times two */
+	var delta float64 /* difference between current year and 1949 */
+	var s2 float64    /* sine of d2 */
+	var sd float64    /* sine of the day angle */
+	var leap int      /* leap year counter */
+
+	/* the algorithm counts days in the proleptic Gregorian calendar */
+	year, daynum := sp.gregorianDay()
 
 	/* Day angle */
 	/*  Iqbal, M.  1983.  An Introduction to Solar Radiation.
 	    Academic Press, NY., page 3 */
-	sp.Dayang = 360.0 * (float64(sp.Daynum) - 1.0) / 365.0
+	sp.Dayang = 360.0 * (float64(daynum) - 1.0) / 365.0
 
 	/* Earth radius vector * solar constant = solar energy */
 	/*  Spencer, J. W.  1971.  Fourier series representation of the
@@ -1114,18 +1430,83 @@ func (sp *solpos) geometry() {
 	    approximate solar position (1950-2050).  Solar Energy 40 (3),
 	    pp. 227-235. */
 
-	/* No adjustment for century non-leap years since this function is
-	   bounded by 1950 - 2050 */
-	delta = float64(sp.Year - 1949)
-	leap = (int((delta / 4.0)))
-	sp.Julday = 32916.5 + (delta * 365.0) + float64(leap) + float64(sp.Daynum) + (sp.Utime / 24.0)
+	/* Gregorian leap days since 1949, the same as int(delta / 4) within
+	   1950 - 2050 and also right for century non-leap years outside */
+	delta = float64(year - 1949)
+	leap = leapDays(year-1) - leapDays(1948)
+	sp.Julday = 32916.5 + (delta * 365.0) + float64(leap) + float64(daynum) + (sp.Utime / 24.0)
+
+	/* the topocentric geometry of the other algorithms depends on the site, it is not shared */
+	switch sp.algorithm() {
+	case AlgorithmSPA:
+		sp.spa()
+		return
+	case AlgorithmGrena1, AlgorithmGrena2, AlgorithmGrena3, AlgorithmGrena4, AlgorithmGrena5:
+		sp.grena()
+		return
+	case AlgorithmPSA2001, AlgorithmPSA2020:
+		sp.psa()
+		return
+	}
+
+	/* the ecliptic coordinates and sidereal time only depend on the instant, sites calculated for the same
+	   instant (see FleetPositions) share them */
+	if sp.shared != nil && sp.shared.julday == sp.Julday && sp.shared.deltaT == sp.DeltaT && sp.shared.precise == sp.Precise {
+		sp.shared.restore(sp)
+	} else {
+		sp.ecliptic()
+		if sp.shared != nil {
+			sp.shared.store(sp)
+		}
+	}
+
+	/* Local mean sidereal time */
+	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
+	    approximate solar position (1950-2050).  Solar Energy 40 (3),
+	    pp. 227-235. */
+	sp.Lmst = sp.Gmst*15.0 + sp.Longitude
+
+	/* (dump the multiples of 360, so the answer is between 0 and 360) */
+	sp.Lmst -= float64(360 * (int(sp.Lmst / 360.0)))
+	if sp.Lmst < 0.0 {
+		sp.Lmst += 360.0
+	}
+
+	/* Hour angle */
+	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
+	    approximate solar position (1950-2050).  Solar Energy 40 (3),
+	    pp. 227-235. */
+	sp.Hrang = sp.Lmst - sp.Rascen
+
+	/* (force it between -180 and 180 degrees) */
+	if sp.Hrang < -180.0 {
+		sp.Hrang += 360.0
+	}
+	if sp.Hrang > 180.0 {
+		sp.Hrang -= 360.0
+	}
+
+	if sp.Precise {
+		/* parallax of the observer at the mean distance of the sun, topocentric like the other algorithms */
+		dalpha, deltaPrime := sp.parallax(sp.Declin, sp.Hrang, 1.0)
+		sp.Declin = deltaPrime
+		sp.Rascen = limitDegrees(sp.Rascen + dalpha)
+		sp.Hrang -= dalpha
+	}
+
+}
+
+// ecliptic calculates the ecliptic coordinates, declination, right ascension and Greenwich mean sidereal time of Julday and Utime
+func (sp *PosData) ecliptic() {
+	var bottom float64 /* denominator (bottom) of the fraction */
+	var top float64    /* numerator (top) of the fraction */
 
 	/* Time used in the calculation of ecliptic coordinates */
 	/* Noon 1 JAN 2000 = 2,400,000 + 51,545 days Julian Date */
 	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
 	    approximate solar position (1950-2050).  Solar Energy 40 (3),
 	    pp. 227-235. */
-	sp.Ectime = sp.Julday - 51545.0
+	sp.Ectime = sp.Julday - 51545.0 + sp.DeltaT/86400.0
 
 	/* Mean longitude */
 	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
@@ -1172,6 +1553,13 @@ func (sp *solpos) geometry() {
 	/*  pdat->ecobli = 23.439 + 4.0e-07 * pdat->ectime;     */
 	sp.Ecobli = 23.439 - 4.0e-07*sp.Ectime
 
+	/* equation of the equinoxes, hours */
+	var eqeq float64
+	if sp.Precise {
+		/* the apparent coordinates with nutation and annual aberration */
+		eqeq = sp.apparent()
+	}
+
 	/* Declination */
 	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
 	    approximate solar position (1950-2050).  Solar Energy 40 (3),
@@ -1196,40 +1584,14 @@ func (sp *solpos) geometry() {
 	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
 	    approximate solar position (1950-2050).  Solar Energy 40 (3),
 	    pp. 227-235. */
-	sp.Gmst = 6.697375 + 0.0657098242*sp.Ectime + sp.Utime
+	/* (in universal time, the rotation of the Earth) */
+	sp.Gmst = 6.697375 + 0.0657098242*(sp.Julday-51545.0) + sp.Utime + eqeq
 
 	/* (dump the multiples of 24, so the answer is between 0 and 24) */
 	sp.Gmst -= float64(24 * (int(sp.Gmst / 24.0)))
 	if sp.Gmst < 0.0 {
 		sp.Gmst += 24.0
 	}
-
-	/* Local mean sidereal time */
-	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
-	    approximate solar position (1950-2050).  Solar Energy 40 (3),
-	    pp. 227-235. */
-	sp.Lmst = sp.Gmst*15.0 + sp.Longitude
-
-	/* (dump the multiples of 360, so the answer is between 0 and 360) */
-	sp.Lmst -= float64(360 * (int(sp.Lmst / 360.0)))
-	if sp.Lmst < 0.0 {
-		sp.Lmst += 360.0
-	}
-
-	/* Hour angle */
-	/*  Michalsky, J.  1988.  The Astronomical Almanac's algorithm for
-	    approximate solar position (1950-2050).  Solar Energy 40 (3),
-	    pp. 227-235. */
-	sp.Hrang = sp.Lmst - sp.Rascen
-
-	/* (force it between -180 and 180 degrees) */
-	if sp.Hrang < -180.0 {
-		sp.Hrang += 360.0
-	}
-	if sp.Hrang > 180.0 {
-		sp.Hrang -= 360.0
-	}
-
 }
 
 /*============================================================================
@@ -1239,7 +1601,7 @@ func (sp *solpos) geometry() {
  *       Iqbal, M.  1983.  An Introduction to Solar Radiation.
  *            Academic Press, NY., page 15
  *----------------------------------------------------------------------------*/
-func (sp *solpos) zenNoRef() {
+func (sp *PosData) zenNoRef() {
 	var cz float64 /* cosine of the solar zenith angle */
 
 	sp.localtrig()
@@ -1272,7 +1634,7 @@ func (sp *solpos) zenNoRef() {
  *       Iqbal, M.  1983.  An Introduction to Solar Radiation.
  *            Academic Press, NY., page 16
  *----------------------------------------------------------------------------*/
-func (sp *solpos) ssha() {
+func (sp *PosData) ssha() {
 	var cssha float64 /* cosine of the sunset hour angle */
 	var cdcl float64  /* ( cd * cl ) */
 
@@ -1305,13 +1667,20 @@ func (sp *solpos) ssha() {
  *       Drummond, A. J.  1956.  A contribution to absolute pyrheliometry.
  *            Q. J. R. Meteorol. Soc. 82, pp. 481-493
  *----------------------------------------------------------------------------*/
-func (sp *solpos) sbcf() {
-	var p, t1, t2 float64 /* used to compute sbcf */
-	sp.localtrig()
-	p = 0.6366198 * sp.Sbwid / sp.Sbrad * math.Pow(sp.Tdat.Cd, 3)
-	t1 = sp.Tdat.Sl * sp.Tdat.Sd * sp.Ssha * raddeg
-	t2 = sp.Tdat.Cl * sp.Tdat.Cd * math.Sin(sp.Ssha*raddeg)
-	sp.Sbcf = sp.Sbsky + 1.0/(1.0-p*(t1+t2))
+func (sp *PosData) sbcf() error {
+	sbcf, err := sp.GetShadowBandModel().Correction(ShadowBandInput{
+		Latitude:    sp.Latitude,
+		Declination: sp.Declin,
+		Ssha:        sp.Ssha,
+		Width:       sp.Sbwid,
+		Radius:      sp.Sbrad,
+		Sky:         sp.Sbsky,
+	})
+	if err != nil {
+		return err
+	}
+	sp.Sbcf = sbcf
+	return nil
 }
 
 /*============================================================================
@@ -1322,18 +1691,16 @@ func (sp *solpos) sbcf() {
  *        Iqbal, M.  1983.  An Introduction to Solar Radiation.
  *            Academic Press, NY., page 13
  *----------------------------------------------------------------------------*/
-func (sp *solpos) tst() {
+func (sp *PosData) tst() {
 	sp.Tst = (180.0 + sp.Hrang) * 4.0
 	sp.Tstfix = sp.Tst - float64(sp.Hour)*60.0 - float64(sp.Minute) - float64(sp.Second)/60.0 + float64(sp.Interval)/120.0 /* add back half of the interval */
 
 	/* bound tstfix to this day */
 	for sp.Tstfix > 720.0 {
-		fmt.Println("bigger")
 		sp.Tstfix -= 1440.0
 	}
 
 	for sp.Tstfix < -720.0 {
-		fmt.Println("smaller")
 		sp.Tstfix += 1440.0
 	}
 
@@ -1345,16 +1712,18 @@ func (sp *solpos) tst() {
  *
  *    Sunrise and sunset times (minutes from midnight)
  *----------------------------------------------------------------------------*/
-func (sp *solpos) srss() {
-	if sp.Ssha <= 1.0 {
+func (sp *PosData) srss() {
+	/* below the dipped horizon of an elevated observer */
+	ssha := sp.riseSetHourAngle()
+	if ssha <= 1.0 {
 		sp.Sretr = 2999.0
 		sp.Ssetr = -2999.0
-	} else if sp.Ssha >= 179.0 {
+	} else if ssha >= 179.0 {
 		sp.Sretr = -2999.0
 		sp.Ssetr = 2999.0
 	} else {
-		sp.Sretr = 720.0 - 4.0*sp.Ssha - sp.Tstfix
-		sp.Ssetr = 720.0 + 4.0*sp.Ssha - sp.Tstfix
+		sp.Sretr = 720.0 - 4.0*ssha - sp.Tstfix
+		sp.Ssetr = 720.0 + 4.0*ssha - sp.Tstfix
 	}
 }
 
@@ -1365,7 +1734,7 @@ func (sp *solpos) srss() {
  *       Iqbal, M.  1983.  An Introduction to Solar Radiation.
  *            Academic Press, NY., page 15
  *----------------------------------------------------------------------------*/
-func (sp *solpos) sazm() {
+func (sp *PosData) sazm() {
 	var ca float64   /* cosine of the solar azimuth angle */
 	var ce float64   /* cosine of the solar elevation */
 	var cecl float64 /* ( ce * cl ) */
@@ -1401,53 +1770,68 @@ func (sp *solpos) sazm() {
  *            SAND81-0761, Experimental Systems Operation Division 4721,
  *            Sandia National Laboratories, Albuquerque, NM.
  *----------------------------------------------------------------------------*/
-func (sp *solpos) refrac() {
+func (sp *PosData) refrac() error {
+	/* the pressure/temperature correction divides by the absolute temperature */
+	if 273.0+sp.Temp <= 0.0 {
+		return wrap(ErrNumericalDomain, "refrac: temperature at or below absolute zero")
+	}
+	/* Refracted solar elevation angle */
+	sp.Elevref = sp.Elevetr + refraction(sp.Elevetr, sp.Press, sp.Temp)
+
+	/* (limit the degrees below the horizon to 9) */
+	if sp.Elevref < -9.0 {
+		sp.Elevref = -9.0
+	}
+
+	/* Refracted solar zenith angle */
+	sp.Zenref = 90.0 - sp.Elevref
+	sp.Coszen = math.Cos(raddeg * sp.Zenref)
+	return nil
+}
+
+// refraction returns the refraction correction in degrees for an unrefracted solar elevation, pressure and temperature
+func refraction(elevetr float64, press float64, temp float64) float64 {
 	var prestemp float64 /* temporary pressure/temperature correction */
 	var refcor float64   /* temporary refraction correction */
 	var tanelev float64  /* tangent of the solar elevation angle */
 
 	/* If the sun is near zenith, the algorithm bombs; refraction near 0 */
-	if sp.Elevetr > 85.0 {
+	if elevetr > 85.0 {
 		refcor = 0.0
 	} else {
 		/* Otherwise, we have refraction */
-		tanelev = math.Tan(raddeg * sp.Elevetr)
-		if sp.Elevetr >= 5.0 {
+		tanelev = math.Tan(raddeg * elevetr)
+		if elevetr >= 5.0 {
 			refcor = 58.1/tanelev - 0.07/(math.Pow(tanelev, 3)) + 0.000086/(math.Pow(tanelev, 5))
-		} else if sp.Elevetr >= -0.575 {
-			refcor = 1735.0 + sp.Elevetr*(-518.2+sp.Elevetr*(103.4+sp.Elevetr*(-12.79+sp.Elevetr*0.711)))
+		} else if elevetr >= -0.575 {
+			refcor = 1735.0 + elevetr*(-518.2+elevetr*(103.4+elevetr*(-12.79+elevetr*0.711)))
 		} else {
 			refcor = -20.774 / tanelev
 		}
 		prestemp =
-			(sp.Press * 283.0) / (1013.0 * (273.0 + sp.Temp))
+			(press * 283.0) / (1013.0 * (273.0 + temp))
 		refcor *= prestemp / 3600.0
 
 	}
-
-	/* Refracted solar elevation angle */
-	sp.Elevref = sp.Elevetr + refcor
-
-	/* (limit the degrees below the horizon to 9) */
-	if sp.Elevref < -9.0 {
-		sp.Elevref = -9.0
-	}
-
-	/* Refracted solar zenith angle */
-	sp.Zenref = 90.0 - sp.Elevref
-	sp.Coszen = math.Cos(raddeg * sp.Zenref)
+	return refcor
 }
-func (sp *solpos) amass() {
+
+func (sp *PosData) amass() error {
 	if sp.Zenref > 93.0 {
 		sp.Amass = -1.0
 		sp.Ampress = -1.0
 	} else {
-		sp.Amass =
-			1.0 / (math.Cos(raddeg*sp.Zenref) + (0.50572 *
-				math.Pow(96.07995-sp.Zenref, -1.6364)))
+		/* positive for refracted zenith angles of 0 - 93 degrees, an input zenref may be anything */
+		denom := math.Cos(raddeg*sp.Zenref) + (0.50572 *
+			math.Pow(96.07995-sp.Zenref, -1.6364))
+		if denom <= 0.0 {
+			return wrap(ErrNumericalDomain, "amass: zenref outside the Kasten-Young formula")
+		}
+		sp.Amass = 1.0 / denom
 
 		sp.Ampress = sp.Amass * sp.Press / 1013.0
 	}
+	return nil
 }
 
 /*============================================================================
@@ -1460,9 +1844,14 @@ func (sp *solpos) amass() {
  *            full use of the clearness index for parameterizing hourly
  *            insolation conditions. Solar Energy 45 (2), pp. 111-114
  *----------------------------------------------------------------------------*/
-func (sp *solpos) prime() {
+func (sp *PosData) prime() error {
+	/* the airmass is a divisor, -1 below the horizon */
+	if sp.Amass == 0.0 || 0.9+9.4/sp.Amass == 0.0 {
+		return wrap(ErrNumericalDomain, "prime: airmass of 0 or -9.4/0.9")
+	}
 	sp.Unprime = 1.031*math.Exp(-1.4/(0.9+9.4/sp.Amass)) + 0.1
 	sp.Prime = 1.0 / sp.Unprime
+	return nil
 }
 
 /*============================================================================
@@ -1470,7 +1859,7 @@ func (sp *solpos) prime() {
  *
  *    Extraterrestrial (top-of-atmosphere) solar irradiance
  *----------------------------------------------------------------------------*/
-func (sp *solpos) etr() {
+func (sp *PosData) etr() {
 	if sp.Coszen > 0.0 {
 		sp.Etrn = sp.Solcon * sp.Erv
 		sp.Etr = sp.Etrn * sp.Coszen
@@ -1486,7 +1875,12 @@ func (sp *solpos) etr() {
  *
  *    ETR on a tilted surface
  *----------------------------------------------------------------------------*/
-func (sp *solpos) tilt() {
+func (sp *PosData) tilt() {
+	sp.Cosinc, sp.Etrtilt = sp.incidence(sp.Tilt, sp.Aspect)
+}
+
+// incidence returns the cosine of the solar incidence angle and the ETR on a surface with the given tilt and aspect
+func (sp *PosData) incidence(tilt float64, aspect float64) (cosinc float64, etrtilt float64) {
 	var ca float64  /* cosine of the solar azimuth angle */
 	var cp float64  /* cosine of the panel aspect */
 	var ct float64  /* cosine of the panel tilt */
@@ -1498,20 +1892,20 @@ func (sp *solpos) tilt() {
 	/* Cosine of the angle between the sun and a tipped flat surface,
 	   useful for calculating solar energy on tilted surfaces */
 	ca = math.Cos(raddeg * sp.Azim)
-	cp = math.Cos(raddeg * sp.Aspect)
-	ct = math.Cos(raddeg * sp.Tilt)
+	cp = math.Cos(raddeg * aspect)
+	ct = math.Cos(raddeg * tilt)
 	sa = math.Sin(raddeg * sp.Azim)
-	spp = math.Sin(raddeg * sp.Aspect)
-	st = math.Sin(raddeg * sp.Tilt)
+	spp = math.Sin(raddeg * aspect)
+	st = math.Sin(raddeg * tilt)
 	sz = math.Sin(raddeg * sp.Zenref)
-	sp.Cosinc = sp.Coszen*ct + sz*st*(ca*cp+sa*spp)
+	cosinc = sp.Coszen*ct + sz*st*(ca*cp+sa*spp)
 
-	if sp.Cosinc > 0.0 {
-		sp.Etrtilt = sp.Etrn * sp.Cosinc
+	if cosinc > 0.0 {
+		etrtilt = sp.Etrn * cosinc
 	} else {
-		sp.Etrtilt = 0.0
+		etrtilt = 0.0
 	}
-
+	return cosinc, etrtilt
 }
 
 /*============================================================================
@@ -1519,7 +1913,7 @@ func (sp *solpos) tilt() {
  *
  *    Does trig on internal variable used by several functions
  *----------------------------------------------------------------------------*/
-func (sp *solpos) localtrig() {
+func (sp *PosData) localtrig() {
 	/* define masks to prevent calculation of uninitialized variables */
 
 	if sp.Tdat.Sd < -900.0 { // sd was initialized -999 as flag
@@ -0,0 +1,52 @@
+package solpos
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Algorithm selects the solar position algorithm of the basic geometry (declination, right ascension, hour angle and
+// earth radius vector). Zenith, azimuth, refraction, sunrise and sunset and all further outputs follow from it the same
+// way for every algorithm.
+type Algorithm int
+
+const (
+	AlgorithmSOLPOS  Algorithm = iota // Michalsky (1988) as in the C code of SOLPOS, about 0.01 degrees within 1950-2050
+	AlgorithmSPA                      // NREL Solar Position Algorithm (Reda and Andreas 2004), +-0.0003 degrees within -2000-6000, topocentric
+	AlgorithmGrena1                   // Grena (2012) algorithm 1, the fastest, 0.19 degrees within 2010-2110
+	AlgorithmGrena2                   // Grena (2012) algorithm 2, 0.034 degrees within 2010-2110
+	AlgorithmGrena3                   // Grena (2012) algorithm 3, 0.0093 degrees within 2010-2110
+	AlgorithmGrena4                   // Grena (2012) algorithm 4 with nutation, 0.0091 degrees within 2010-2110
+	AlgorithmGrena5                   // Grena (2012) algorithm 5 with nutation and planetary perturbations, 0.0027 degrees within 2010-2110
+	AlgorithmPSA2001                  // Plataforma Solar de Almeria (Blanco-Muriel et al. 2001), about 0.008 degrees within 1999-2015
+	AlgorithmPSA2020                  // Plataforma Solar de Almeria with the coefficients of Blanco et al. (2020), about 0.008 degrees within 2020-2050
+)
+
+func (sp *PosData) SetAlgorithm(algorithm Algorithm) {
+	sp.Algorithm = algorithm
+}
+
+func (sp *PosData) GetAlgorithm() Algorithm {
+	return sp.Algorithm
+}
+
+// years returns the first and last year the algorithm is valid for
+func (a Algorithm) years() (first int, last int) {
+	switch a {
+	case AlgorithmSPA:
+		return -2000, 6000
+	case AlgorithmGrena1, AlgorithmGrena2, AlgorithmGrena3, AlgorithmGrena4, AlgorithmGrena5:
+		return 2010, 2110
+	case AlgorithmPSA2001:
+		return 1999, 2015
+	case AlgorithmPSA2020:
+		return 2020, 2050
+	}
+	return 1950, 2050
+}
+
+// yearError returns the error of a year outside the range of the algorithm
+func (a Algorithm) yearError() error {
+	first, last := a.years()
+	return errors.New("Please fix the year: [" + strconv.Itoa(first) + "-" + strconv.Itoa(last) + "]")
+}
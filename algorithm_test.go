@@ -0,0 +1,76 @@
+package solpos
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// separation returns the angle between two positions of the sun, degrees
+func separation(zenith1 float64, azimuth1 float64, zenith2 float64, azimuth2 float64) float64 {
+	c := math.Cos(raddeg*zenith1)*math.Cos(raddeg*zenith2) + math.Sin(raddeg*zenith1)*math.Sin(raddeg*zenith2)*math.Cos(raddeg*(azimuth1-azimuth2))
+	return degrad * math.Acos(math.Max(-1.0, math.Min(1.0, c)))
+}
+
+func position(t testing.TB, algorithm Algorithm, dt time.Time, latitude float64, longitude float64) (float64, float64) {
+	sp, err := New(dt, latitude, longitude, WithAlgorithm(algorithm), WithFunction(SSolazm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sp.GetZenetr(), sp.GetAzim()
+}
+
+func TestGrenaAccuracy(t *testing.T) {
+	/* maximum errors of the paper (Grena 5 against the SPA differs by the nutation model) with a margin */
+	limits := []struct {
+		name      string
+		algorithm Algorithm
+		max       float64
+	}{
+		{"Grena 1", AlgorithmGrena1, 0.2},
+		{"Grena 2", AlgorithmGrena2, 0.04},
+		{"Grena 3", AlgorithmGrena3, 0.01},
+		{"Grena 4", AlgorithmGrena4, 0.01},
+		{"Grena 5", AlgorithmGrena5, 0.005},
+	}
+	sites := [][2]float64{{52.52, 13.40}, {39.74, -105.18}, {-33.87, 151.21}, {1.35, 103.82}}
+	for _, l := range limits {
+		var max float64
+		for dt := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC); dt.Year() < 2110; dt = dt.Add(613*time.Hour + 17*time.Minute) {
+			for _, s := range sites {
+				zenith, azimuth := position(t, AlgorithmSPA, dt, s[0], s[1])
+				/* the zenith angle is limited to 99 degrees, the azimuth below the horizon is not comparable */
+				if zenith >= 90.0 {
+					continue
+				}
+				z, a := position(t, l.algorithm, dt, s[0], s[1])
+				max = math.Max(max, separation(z, a, zenith, azimuth))
+			}
+		}
+		if max > l.max {
+			t.Errorf("%s: maximum error %.5f degrees against the SPA, want <= %v", l.name, max, l.max)
+		}
+	}
+}
+
+func benchmarkAlgorithm(b *testing.B, algorithm Algorithm) {
+	sp, err := New(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), 52.52, 13.40, WithAlgorithm(algorithm), WithFunction(SSolazm))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err = sp.Calculate()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSOLPOS(b *testing.B) { benchmarkAlgorithm(b, AlgorithmSOLPOS) }
+func BenchmarkGrena1(b *testing.B) { benchmarkAlgorithm(b, AlgorithmGrena1) }
+func BenchmarkGrena2(b *testing.B) { benchmarkAlgorithm(b, AlgorithmGrena2) }
+func BenchmarkGrena3(b *testing.B) { benchmarkAlgorithm(b, AlgorithmGrena3) }
+func BenchmarkGrena4(b *testing.B) { benchmarkAlgorithm(b, AlgorithmGrena4) }
+func BenchmarkGrena5(b *testing.B) { benchmarkAlgorithm(b, AlgorithmGrena5) }
+func BenchmarkSPA(b *testing.B)    { benchmarkAlgorithm(b, AlgorithmSPA) }
@@ -0,0 +1,35 @@
+package solpos
+
+import "math"
+
+// SetAltitude sets the altitude of the observer above sea level in meters and Press to the barometric pressure of the
+// altitude (International Standard Atmosphere, see ISA). Set Press after SetAltitude to use a measured pressure.
+func (sp *PosData) SetAltitude(altitude float64) {
+	sp.Altitude = altitude
+	sp.Press, _ = ISA(altitude)
+}
+
+func (sp *PosData) GetAltitude() float64 {
+	return sp.Altitude
+}
+
+// riseSetHourAngle returns the hour angle of sunrise and sunset in degrees, Ssha for an observer at sea level and the
+// wider hour angle at which the sun crosses the dipped horizon of an elevated observer, 0 for polar night and 180 for
+// polar day like Ssha
+func (sp *PosData) riseSetHourAngle() float64 {
+	if sp.Altitude <= 0.0 {
+		return sp.Ssha
+	}
+	cdcl := sp.Tdat.Cd * sp.Tdat.Cl
+	if math.Abs(cdcl) < 0.001 {
+		return sp.Ssha
+	}
+	/* sin(-dip) = sl sd + cl cd cos(hour angle) */
+	cssha := (math.Sin(-raddeg*HorizonDip(sp.Altitude)) - sp.Tdat.Sl*sp.Tdat.Sd) / cdcl
+	if cssha < -1.0 {
+		return 180.0
+	} else if cssha > 1.0 {
+		return 0.0
+	}
+	return degrad * math.Acos(cssha)
+}
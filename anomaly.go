@@ -0,0 +1,122 @@
+package solpos
+
+import (
+	"errors"
+	"time"
+)
+
+// AnomalyFlag tells why a measured irradiance sample is suspicious
+type AnomalyFlag int
+
+const (
+	AnomalyAboveClearSky  AnomalyFlag = iota + 1 // above the clear-sky envelope longer than cloud enhancement lasts
+	AnomalyZeroInDaylight                        // zero (or near zero) with the sun well above the horizon
+)
+
+func (f AnomalyFlag) String() string {
+	switch f {
+	case AnomalyAboveClearSky:
+		return "above clear sky"
+	case AnomalyZeroInDaylight:
+		return "zero in daylight"
+	default:
+		return "none"
+	}
+}
+
+// AnomalyConfig configures DetectAnomalies
+type AnomalyConfig struct {
+	Margin             float64                // Relative margin above the clear-sky GHI, DEFAULT (0) = 0.2
+	Offset             float64                // Absolute margin above the clear-sky GHI, W/sq m, DEFAULT (0) = 50
+	Enhancement        time.Duration          // Exceedances lasting at most this long are cloud enhancement, not flagged, 0 = flag all
+	MinElevation       float64                // Solar elevation above which a zero sample is flagged, degrees, DEFAULT (0) = 10
+	ZeroThreshold      float64                // Samples at or below this irradiance count as zero, W/sq m, DEFAULT (0) = 1
+	LinkeTurbidity     float64                // Linke turbidity of the clear sky (see ClearSkyIneichen), DEFAULT (0) = 3
+	Altitude           float64                // Altitude of the site, meters
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos, e.g. "press"
+}
+
+// Anomaly is a flagged sample
+type Anomaly struct {
+	Time     time.Time   // Time of the sample
+	Value    float64     // Measured GHI, W/sq m
+	ClearSky float64     // Modeled clear-sky GHI, W/sq m
+	Flag     AnomalyFlag // Why the sample is flagged
+}
+
+// DetectAnomalies checks measured global horizontal irradiance samples (times not decreasing) of a site against the
+// clear-sky envelope (Ineichen-Perez) for automated sensor health monitoring. It flags samples above the clear-sky GHI
+// plus margin and offset, unless the exceedance is short enough to be cloud enhancement, and samples stuck at zero
+// while the sun is well above the horizon. Only flagged samples are returned.
+func DetectAnomalies(site Site, series []IrradianceSample, config AnomalyConfig) ([]Anomaly, error) {
+	if config.Margin < 0.0 || config.Offset < 0.0 || config.ZeroThreshold < 0.0 || config.Enhancement < 0 {
+		return nil, errors.New("Please fix margin, offset, enhancement and zero threshold, must not be negative")
+	}
+	margin := config.Margin
+	if margin == 0.0 {
+		margin = 0.2
+	}
+	offset := config.Offset
+	if offset == 0.0 {
+		offset = 50.0
+	}
+	minElevation := config.MinElevation
+	if minElevation == 0.0 {
+		minElevation = 10.0
+	}
+	zero := config.ZeroThreshold
+	if zero == 0.0 {
+		zero = 1.0
+	}
+	linke := config.LinkeTurbidity
+	if linke == 0.0 {
+		linke = 3.0
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+	sp, err := NewSolpos(series[0].Time, site.Latitude, site.Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var anomalies []Anomaly
+	/* exceeding samples are held back until the run is known to last longer than cloud enhancement */
+	var run []Anomaly
+	flush := func(end time.Time) {
+		if len(run) > 0 && end.Sub(run[0].Time) > config.Enhancement {
+			anomalies = append(anomalies, run...)
+		}
+		run = run[:0]
+	}
+	for i, s := range series {
+		if i > 0 && s.Time.Before(series[i-1].Time) {
+			return nil, errors.New("Please fix series, times must not decrease")
+		}
+		sp.SetDate(s.Time)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		r := sp.GetResult()
+		clear := ClearSkyIneichen(r, linke, config.Altitude).GHI
+		if s.Value > clear*(1.0+margin)+offset {
+			run = append(run, Anomaly{Time: s.Time, Value: s.Value, ClearSky: clear, Flag: AnomalyAboveClearSky})
+			continue
+		}
+		/* the run ends with this sample */
+		flush(s.Time)
+		if s.Value <= zero && r.Elevref > minElevation {
+			anomalies = append(anomalies, Anomaly{Time: s.Time, Value: s.Value, ClearSky: clear, Flag: AnomalyZeroInDaylight})
+		}
+	}
+	if len(run) > 0 {
+		/* a run at the end of the series lasts at least to its last sample plus one step */
+		end := run[len(run)-1].Time
+		if len(series) > 1 {
+			end = end.Add(series[len(series)-1].Time.Sub(series[len(series)-2].Time))
+		}
+		flush(end)
+	}
+	return anomalies, nil
+}
@@ -0,0 +1,25 @@
+package solpos
+
+import "time"
+
+// AtmosphereProvider provides the surface pressure (millibars) and the ambient temperature (degrees C) at a time,
+// e.g. read from a barometer and thermometer of the station
+type AtmosphereProvider interface {
+	Atmosphere(t time.Time) (press float64, temp float64)
+}
+
+// AtmosphereFunc is a function implementing AtmosphereProvider
+type AtmosphereFunc func(t time.Time) (press float64, temp float64)
+
+// Atmosphere calls f(t)
+func (f AtmosphereFunc) Atmosphere(t time.Time) (press float64, temp float64) {
+	return f(t)
+}
+
+func (sp *PosData) SetAtmosphere(provider AtmosphereProvider) {
+	sp.Atmosphere = provider
+}
+
+func (sp *PosData) GetAtmosphere() AtmosphereProvider {
+	return sp.Atmosphere
+}
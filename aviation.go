@@ -0,0 +1,129 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+/*============================================================================
+*    Aviation
+*
+*    Ambient pressure and temperature of the International Standard
+*    Atmosphere (ISA) at a pressure altitude, and sun glare through the
+*    windscreen of an aircraft.
+*       ISO 2533:1975.  Standard Atmosphere
+*----------------------------------------------------------------------------*/
+
+const (
+	earthRadius = 6371000.0 // mean radius of the Earth, meters
+	feet        = 0.3048    // meters per foot
+)
+
+// ISA returns the pressure (millibars) and temperature (degrees C) of the International Standard Atmosphere at a
+// pressure altitude in meters, valid up to 20 km (the tropopause is at 11 km)
+func ISA(pressureAltitude float64) (press float64, temp float64) {
+	if pressureAltitude <= 11000.0 {
+		t := 288.15 - 0.0065*pressureAltitude
+		return 1013.25 * math.Pow(t/288.15, 5.255877), t - 273.15
+	}
+	return 226.3206 * math.Exp(-(pressureAltitude-11000.0)/6341.62), -56.5
+}
+
+// FlightLevel returns the pressure altitude in meters of a flight level (hundreds of feet, e.g. 350 for FL350)
+func FlightLevel(level float64) float64 {
+	return level * 100.0 * feet
+}
+
+// HorizonDip returns the angle in degrees the visible horizon lies below the horizontal at an altitude in meters,
+// without refraction
+func HorizonDip(altitude float64) float64 {
+	if altitude <= 0.0 {
+		return 0.0
+	}
+	return degrad * math.Acos(earthRadius/(earthRadius+altitude))
+}
+
+// SetPressureAltitude sets Press and Temp to the International Standard Atmosphere at a pressure altitude in meters
+// (see FlightLevel), e.g. for the refraction seen from an aircraft
+func (sp *PosData) SetPressureAltitude(pressureAltitude float64) {
+	sp.Press, sp.Temp = ISA(pressureAltitude)
+}
+
+// GlareConfig configures GlareWindows, the sector of the windscreen in which the sun glares
+type GlareConfig struct {
+	HalfWidth          float64                // Half width of the sector left and right of the nose, degrees, DEFAULT (0) = 30
+	MinElevation       float64                // Lowest sun elevation in the aircraft frame, degrees, DEFAULT (0) = the dip of the horizon
+	MaxElevation       float64                // Highest sun elevation in the aircraft frame, degrees, DEFAULT (0) = 25
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos
+}
+
+// GlareWindow is a time range with the sun in the glare sector
+type GlareWindow struct {
+	Start    time.Time // First track point with glare
+	End      time.Time // Last track point with glare
+	MinAngle float64   // Smallest angle between the sun and the nose during the window, degrees
+}
+
+// GlareWindows returns the time ranges along a flight track with the refracted sun in the glare sector ahead of the
+// aircraft. The Altitude of the track points is the pressure altitude, it determines pressure and temperature of the
+// refraction (ISA) and the dip of the horizon, below which the sun is hidden by the Earth.
+func GlareWindows(track []TrackPoint, config GlareConfig) ([]GlareWindow, error) {
+	halfWidth := config.HalfWidth
+	if halfWidth == 0.0 {
+		halfWidth = 30.0
+	}
+	maxElevation := config.MaxElevation
+	if maxElevation == 0.0 {
+		maxElevation = 25.0
+	}
+	if halfWidth < 0.0 || halfWidth > 180.0 {
+		return nil, errors.New("Please fix half width [0-180]")
+	}
+	if len(track) == 0 {
+		return nil, nil
+	}
+	sp, err := NewSolpos(track[0].Time, track[0].Latitude, track[0].Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+	var windows []GlareWindow
+	open := false
+	for k, t := range track {
+		if k > 0 && t.Time.Before(track[k-1].Time) {
+			return nil, errors.New("Please fix track, times must not decrease")
+		}
+		sp.SetDate(t.Time)
+		sp.SetLatitude(t.Latitude)
+		sp.SetLongitude(t.Longitude)
+		sp.SetPressureAltitude(t.Altitude)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, wrap(err, "track point "+strconv.Itoa(k))
+		}
+		r := sp.GetResult()
+		dip := HorizonDip(t.Altitude)
+		minElevation := config.MinElevation
+		if minElevation == 0.0 {
+			minElevation = -dip
+		}
+		b := t.Attitude.SunPosition(r)
+		/* relative azimuth -180..180, 0 = nose */
+		relative := math.Remainder(b.Azimuth, 360.0)
+		glare := r.Elevref > -dip && math.Abs(relative) <= halfWidth && b.Elevation >= minElevation && b.Elevation <= maxElevation
+		if !glare {
+			open = false
+			continue
+		}
+		angle := degrad * math.Acos(math.Max(-1.0, math.Min(1.0, b.Vector[0])))
+		if !open {
+			windows = append(windows, GlareWindow{Start: t.Time, MinAngle: angle})
+			open = true
+		}
+		w := &windows[len(windows)-1]
+		w.End = t.Time
+		w.MinAngle = math.Min(w.MinAngle, angle)
+	}
+	return windows, nil
+}
@@ -0,0 +1,69 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// azimuthSearchStep is the sampling interval of the azimuth in TimeAtAzimuth, short enough to follow the fast swing of the
+// azimuth when the sun passes close to the zenith
+const azimuthSearchStep = 5 * time.Minute
+
+func (sp *PosData) TimeAtAzimuth(date time.Time, azimuth float64) (time.Time, error) {
+	if math.IsNaN(azimuth) || azimuth < 0.0 || azimuth > 360.0 {
+		return time.Time{}, errors.New("Please fix azimuth [0 - 360]")
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+	prev, _, err := sp.azimuthOffsetAt(start, azimuth)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for t0 := start; t0.Before(end); {
+		t1 := t0.Add(azimuthSearchStep)
+		if t1.After(end) {
+			t1 = end
+		}
+		d1, _, err := sp.azimuthOffsetAt(t1, azimuth)
+		if err != nil {
+			return time.Time{}, err
+		}
+		/* a change of sign through 0, not through the opposite bearing at +-180 */
+		if (prev < 0.0) != (d1 < 0.0) && math.Abs(d1-prev) < 180.0 {
+			before := prev < 0.0
+			t, err := bisect(t0, t1, func(mid time.Time) (bool, error) {
+				d, _, err := sp.azimuthOffsetAt(mid, azimuth)
+				return (d < 0.0) != before, err
+			})
+			if err != nil {
+				return time.Time{}, err
+			}
+			_, elevref, err := sp.azimuthOffsetAt(t, azimuth)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if elevref > 0.0 {
+				/* the calculation is left at the crossing */
+				return t, nil
+			}
+		}
+		t0, prev = t1, d1
+	}
+	return time.Time{}, wrap(ErrNoCrossing, "below the horizon at the azimuth")
+}
+
+// azimuthOffsetAt calculates dt and returns the azimuth of the sun relative to azimuth (degrees, -180 to 180) and the
+// refracted elevation
+func (sp *PosData) azimuthOffsetAt(dt time.Time, azimuth float64) (offset float64, elevref float64, err error) {
+	sp.SetDate(dt)
+	err = sp.Calculate()
+	if err == nil {
+		err = sp.Computed(LSolazm | LRefrac)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	offset = math.Mod(sp.Azim-azimuth+540.0, 360.0) - 180.0
+	return offset, sp.Elevref, nil
+}
@@ -0,0 +1,73 @@
+package solpos
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchCalculator calculates many timestamps of a location in parallel, every worker with its own calculator
+type BatchCalculator struct {
+	Latitude  float64  // Latitude, degrees north (south negative)
+	Longitude float64  // Longitude, degrees east (west negative)
+	Workers   int      // Number of goroutines, DEFAULT (0) = GOMAXPROCS
+	Options   *Options // Optional parameters, DEFAULT (nil) = DefaultOptions()
+}
+
+// Calculate returns the results of all times, in the order of times. Every worker calculates a contiguous part of the times,
+// the first error (in the order of times) stops the batch.
+func (b BatchCalculator) Calculate(times []time.Time) ([]Result, error) {
+	workers := b.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers < 0 {
+		return nil, errors.New("Please fix workers, must not be negative")
+	}
+	if workers > len(times) {
+		workers = len(times)
+	}
+	options := DefaultOptions()
+	if b.Options != nil {
+		options = *b.Options
+	}
+	results := make([]Result, len(times))
+	errs := make([]error, workers)
+	var failed int32
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		lo, hi := w*len(times)/workers, (w+1)*len(times)/workers
+		wg.Add(1)
+		go func(w int, lo int, hi int) {
+			defer wg.Done()
+			sp, err := NewSolposWithOptions(times[lo], b.Latitude, b.Longitude, options)
+			if err != nil {
+				errs[w] = err
+				atomic.StoreInt32(&failed, 1)
+				return
+			}
+			for i := lo; i < hi; i++ {
+				if atomic.LoadInt32(&failed) != 0 {
+					return
+				}
+				sp.SetDate(times[i])
+				err = sp.Calculate()
+				if err != nil {
+					errs[w] = err
+					atomic.StoreInt32(&failed, 1)
+					return
+				}
+				results[i] = sp.GetResult()
+			}
+		}(w, lo, hi)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
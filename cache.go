@@ -0,0 +1,219 @@
+package solpos
+
+import (
+	"container/heap"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// cacheShards is the number of independently locked parts of a ResultCache
+const cacheShards = 16
+
+// cacheLocationScale rounds latitude and longitude to 4 decimals (about 11 m)
+const cacheLocationScale = 1e4
+
+// Cache stores results by key for a time to live. Implementations must be safe for concurrent use; a backend failing
+// (e.g. a Redis or memcached server being unreachable) reports a miss, the result is calculated then.
+type Cache interface {
+	Get(key string) (Result, bool)
+	Set(key string, r Result, ttl time.Duration)
+}
+
+// ResultCache is a concurrency-safe cache in front of position and sunrise/sunset calculations.
+// Queries are keyed by time rounded to the cache resolution and location rounded to 4 decimals,
+// so near-duplicate queries (as received by web services) are answered from memory or a shared Cache.
+type ResultCache struct {
+	cache              Cache
+	ttl                time.Duration
+	resolution         time.Duration
+	optionalParameters map[string]interface{}
+}
+
+// NewResultCache creates new instance of ResultCache in memory. Entries live for ttl, about maxEntries are kept (0 = unlimited)
+// and times are rounded to resolution (e.g. time.Minute). The optional parameters are the same as for NewSolpos.
+func NewResultCache(ttl time.Duration, maxEntries int, resolution time.Duration, optionalParameters map[string]interface{}) *ResultCache {
+	return NewResultCacheWith(NewMemoryCache(maxEntries), ttl, resolution, optionalParameters)
+}
+
+// NewResultCacheWith creates new instance of ResultCache storing the results in cache, e.g. backed by Redis to share them
+// across replicas of a service. All replicas sharing a cache must use the same optional parameters, they are not part of the keys.
+func NewResultCacheWith(cache Cache, ttl time.Duration, resolution time.Duration, optionalParameters map[string]interface{}) *ResultCache {
+	return &ResultCache{cache: cache, ttl: ttl, resolution: resolution, optionalParameters: optionalParameters}
+}
+
+// Position returns the result for the given time and location, calculating it on a cache miss
+func (c *ResultCache) Position(dt time.Time, latitude float64, longitude float64) (Result, error) {
+	if c.resolution > 0 {
+		dt = dt.Round(c.resolution)
+	}
+	latitude, longitude = roundLocation(latitude), roundLocation(longitude)
+	return c.get(cacheKey('p', dt, latitude, longitude), dt, latitude, longitude)
+}
+
+// Events returns sunrise and sunset of the (local) day of dt for the given location, calculating them on a cache miss.
+// Days without sunrise and sunset return an error wrapping ErrPolarDay or ErrPolarNight.
+func (c *ResultCache) Events(dt time.Time, latitude float64, longitude float64) (sunrise time.Time, sunset time.Time, err error) {
+	// all queries of the same day share the calculation at local noon
+	dt = time.Date(dt.Year(), dt.Month(), dt.Day(), 12, 0, 0, 0, dt.Location())
+	latitude, longitude = roundLocation(latitude), roundLocation(longitude)
+	r, err := c.get(cacheKey('e', dt, latitude, longitude), dt, latitude, longitude)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	err = polarError(r.Sretr, r.Time)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return minutesToTime(r.Time, r.Sretr), minutesToTime(r.Time, r.Ssetr), nil
+}
+
+func (c *ResultCache) get(key string, dt time.Time, latitude float64, longitude float64) (Result, error) {
+	r, hit := c.cache.Get(key)
+	instrumentation.CacheLookup(hit)
+	if hit {
+		return r, nil
+	}
+	// concurrent misses of the same key simply store the same result
+	sp, err := NewSolpos(dt, latitude, longitude, c.optionalParameters)
+	if err != nil {
+		return Result{}, err
+	}
+	r = sp.GetResult()
+	c.cache.Set(key, r, c.ttl)
+	return r, nil
+}
+
+// NewMemoryCache creates an in-process Cache keeping about maxEntries results (0 = unlimited).
+// It is sharded to keep lock contention low under concurrent queries.
+func NewMemoryCache(maxEntries int) Cache {
+	c := &memoryCache{}
+	if maxEntries > 0 {
+		// the limit is enforced per shard
+		c.shardLimit = maxEntries / cacheShards
+		if c.shardLimit < 1 {
+			c.shardLimit = 1
+		}
+	}
+	for i := range c.shards {
+		c.shards[i].entries = make(map[string]*cacheEntry)
+	}
+	return c
+}
+
+type memoryCache struct {
+	shards     [cacheShards]cacheShard
+	shardLimit int
+}
+
+type cacheShard struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	expiry  expiryHeap // the entries, the next to expire first
+}
+
+type cacheEntry struct {
+	key     string
+	result  Result
+	expires time.Time
+	index   int // position in the expiry heap
+}
+
+// expiryHeap orders cache entries by expiry, implementing heap.Interface
+type expiryHeap []*cacheEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	e := x.(*cacheEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+func (c *memoryCache) Get(key string) (Result, bool) {
+	shard := c.shard(key)
+	now := time.Now()
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	entry, ok := shard.entries[key]
+	if !ok {
+		return Result{}, false
+	}
+	if !now.Before(entry.expires) {
+		shard.remove(entry)
+		return Result{}, false
+	}
+	return entry.result, true
+}
+
+func (c *memoryCache) Set(key string, r Result, ttl time.Duration) {
+	shard := c.shard(key)
+	now := time.Now()
+	shard.mu.Lock()
+	shard.store(key, r, now.Add(ttl), c.shardLimit, now)
+	shard.mu.Unlock()
+}
+
+func (c *memoryCache) shard(key string) *cacheShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &c.shards[h.Sum32()%cacheShards]
+}
+
+// store adds or updates an entry after removing the expired entries, evicting the entry closest to expiry if the
+// shard is full. The expiry heap keeps both at O(log n) per entry.
+func (s *cacheShard) store(key string, r Result, expires time.Time, limit int, now time.Time) {
+	for len(s.expiry) > 0 && !now.Before(s.expiry[0].expires) {
+		s.remove(s.expiry[0])
+	}
+	if entry, ok := s.entries[key]; ok {
+		entry.result, entry.expires = r, expires
+		heap.Fix(&s.expiry, entry.index)
+		return
+	}
+	if limit > 0 && len(s.entries) >= limit {
+		s.remove(s.expiry[0])
+	}
+	entry := &cacheEntry{key: key, result: r, expires: expires}
+	heap.Push(&s.expiry, entry)
+	s.entries[key] = entry
+}
+
+// remove deletes an entry from the map and the expiry heap
+func (s *cacheShard) remove(entry *cacheEntry) {
+	heap.Remove(&s.expiry, entry.index)
+	delete(s.entries, entry.key)
+}
+
+func roundLocation(deg float64) float64 {
+	return math.Round(deg*cacheLocationScale) / cacheLocationScale
+}
+
+func cacheKey(kind byte, dt time.Time, latitude float64, longitude float64) string {
+	_, offset := dt.Zone()
+	b := make([]byte, 0, 64)
+	b = append(b, kind, '|')
+	b = strconv.AppendInt(b, dt.Unix(), 10)
+	b = append(b, '|')
+	b = strconv.AppendInt(b, int64(offset), 10)
+	b = append(b, '|')
+	b = strconv.AppendFloat(b, latitude, 'f', 4, 64)
+	b = append(b, '|')
+	b = strconv.AppendFloat(b, longitude, 'f', 4, 64)
+	return string(b)
+}
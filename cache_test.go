@@ -0,0 +1,82 @@
+package solpos
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// cacheLen returns the number of entries of all shards
+func cacheLen(c Cache) int {
+	n := 0
+	m := c.(*memoryCache)
+	for i := range m.shards {
+		m.shards[i].mu.Lock()
+		n += len(m.shards[i].entries)
+		m.shards[i].mu.Unlock()
+	}
+	return n
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	c := NewMemoryCache(0)
+	c.Set("a", Result{Azim: 1}, time.Hour)
+	c.Set("b", Result{Azim: 2}, -time.Second)
+	if r, ok := c.Get("a"); !ok || r.Azim != 1 {
+		t.Errorf("Get(a) = %v, %v, want 1, true", r.Azim, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) hit an expired entry")
+	}
+	if n := cacheLen(c); n != 1 {
+		t.Errorf("%d entries after reading the expired one, want 1", n)
+	}
+}
+
+func TestMemoryCacheUnlimitedSweep(t *testing.T) {
+	c := NewMemoryCache(0)
+	for i := 0; i < 100000; i++ {
+		c.Set(strconv.Itoa(i), Result{}, -time.Second)
+	}
+	/* unlimited caches must not keep expired entries which are never read */
+	if n := cacheLen(c); n > cacheShards {
+		t.Errorf("%d expired entries kept", n)
+	}
+}
+
+func TestMemoryCacheLimit(t *testing.T) {
+	c := NewMemoryCache(cacheShards * 10)
+	for i := 0; i < 10000; i++ {
+		c.Set(strconv.Itoa(i), Result{Azim: float64(i)}, time.Hour+time.Duration(i)*time.Second)
+	}
+	if n := cacheLen(c); n > cacheShards*10 {
+		t.Errorf("%d entries, want at most %d", n, cacheShards*10)
+	}
+	/* the entries closest to expiry are evicted, the latest one stays */
+	if r, ok := c.Get("9999"); !ok || r.Azim != 9999 {
+		t.Errorf("Get(9999) = %v, %v, want 9999, true", r.Azim, ok)
+	}
+	if _, ok := c.Get("0"); ok {
+		t.Error("Get(0) hit an evicted entry")
+	}
+}
+
+// BenchmarkMemoryCacheSetFull inserts into full shards, the time per insert must not grow with the size of the cache
+func BenchmarkMemoryCacheSetFull(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			c := NewMemoryCache(size)
+			for i := 0; i < 2*size; i++ {
+				c.Set(strconv.Itoa(i), Result{}, time.Hour+time.Duration(i)*time.Millisecond)
+			}
+			keys := make([]string, 4096)
+			for i := range keys {
+				keys[i] = "k" + strconv.Itoa(i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.Set(keys[i%len(keys)]+strconv.Itoa(i), Result{}, 2*time.Hour)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package solpos
+
+import (
+	"errors"
+	"time"
+)
+
+// Calendar defines the calendar of the date inputs Year, Month, Day and Daynum. It matters for historical dates only, which
+// are calculated with YearPolicy YearWarn. time.Time values (SetDate, Getdate) always are instants and are converted.
+type Calendar int
+
+const (
+	CalendarGregorian       Calendar = iota // proleptic Gregorian calendar for all dates, like time.Time and ISO 8601
+	CalendarJulianGregorian                 // Julian calendar until 1582-10-04, followed by the Gregorian calendar from 1582-10-15 (the Gregorian reform)
+)
+
+// gregorianReform is the Julian Day Number of 1582-10-15, the first day of the Gregorian calendar
+const gregorianReform = 2299161
+
+func (sp *PosData) SetCalendar(calendar Calendar) {
+	/* keep the instant, the date fields follow the calendar */
+	dt := sp.Getdate()
+	sp.Calendar = calendar
+	sp.SetDate(dt)
+}
+
+func (sp *PosData) GetCalendar() Calendar {
+	return sp.Calendar
+}
+
+// Time returns the time of a date of the calendar, normalized like time.Date. Dates of the Julian calendar before
+// 1582-10-15 are converted to the proleptic Gregorian calendar of time.Time.
+func (c Calendar) Time(year int, month time.Month, day int, hour int, minute int, second int, loc *time.Location) time.Time {
+	y, m, d := CalendarGregorian.date(c.jdn(year, int(month), day))
+	return time.Date(y, time.Month(m), d, hour, minute, second, 0, loc)
+}
+
+// Date returns the date of t (in the location of t) in the calendar
+func (c Calendar) Date(t time.Time) (int, time.Month, int) {
+	y, m, d := t.Date()
+	year, mon, day := c.date(CalendarGregorian.jdn(y, int(m), d))
+	return year, time.Month(mon), day
+}
+
+// julian returns whether a Julian Day Number is reckoned in the Julian calendar
+func (c Calendar) julian(jdn int) bool {
+	return c == CalendarJulianGregorian && jdn < gregorianReform
+}
+
+// jdn returns the Julian Day Number (the day beginning at noon UT) of a date, days and months beyond their ranges are normalized
+func (c Calendar) jdn(year int, month int, day int) int {
+	/* Fliegel, H. F. and Van Flandern, T. C. 1968. A machine algorithm for processing calendar dates.
+	   Communications of the ACM 11 (10), page 657, with the year starting in March */
+	year += floorDiv(month-1, 12)
+	month -= 12 * floorDiv(month-1, 12)
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	days := (153*m+2)/5 + 365*y + floorDiv(y, 4)
+	j := days - 32083 + day
+	if c == CalendarJulianGregorian && j < gregorianReform {
+		return j
+	}
+	/* (dates of the gap, 1582-10-05 to 14, end up before the reform and are rejected by dayOfYear) */
+	return days - floorDiv(y, 100) + floorDiv(y, 400) - 32045 + day
+}
+
+// date returns the date of a Julian Day Number
+func (c Calendar) date(jdn int) (year int, month int, day int) {
+	/* Richards, E. G. 2013. Calendars. In Explanatory Supplement to the Astronomical Almanac, 3rd ed., pp. 617-619 */
+	b, cc := 0, jdn+32082
+	if !c.julian(jdn) {
+		a := jdn + 32044
+		b = floorDiv(4*a+3, 146097)
+		cc = a - floorDiv(146097*b, 4)
+	}
+	d := floorDiv(4*cc+3, 1461)
+	e := cc - floorDiv(1461*d, 4)
+	m := floorDiv(5*e+2, 153)
+	day = e - floorDiv(153*m+2, 5) + 1
+	month = m + 3 - 12*floorDiv(m, 10)
+	year = 100*b + d - 4800 + floorDiv(m, 10)
+	return year, month, day
+}
+
+// daysInYear returns the number of days of a year of the calendar (355 for 1582 of the Julian-Gregorian calendar)
+func (c Calendar) daysInYear(year int) int {
+	if c == CalendarGregorian {
+		return 365 + leap(year)
+	}
+	return c.jdn(year+1, 1, 1) - c.jdn(year, 1, 1)
+}
+
+// dayOfYear is DayOfYear in the calendar
+func (c Calendar) dayOfYear(year int, month int, day int) (int, error) {
+	if c == CalendarGregorian {
+		return DayOfYear(year, month, day)
+	}
+	if month < 1 || month > 12 {
+		return 0, errors.New("Please fix the month [1-12]")
+	}
+	j := c.jdn(year, month, day)
+	if y, m, d := c.date(j); y != year || m != month || d != day {
+		return 0, errors.New("Please fix the day, the date does not exist in the Julian-Gregorian calendar")
+	}
+	return j - c.jdn(year, 1, 1) + 1, nil
+}
+
+// monthDay is MonthDay in the calendar
+func (c Calendar) monthDay(year int, doy int) (month int, day int, err error) {
+	if c == CalendarGregorian {
+		return MonthDay(year, doy)
+	}
+	if doy < 1 || doy > c.daysInYear(year) {
+		return 0, 0, errors.New("Please fix the day of year, beyond the end of the year in the Julian-Gregorian calendar")
+	}
+	_, month, day = c.date(c.jdn(year, 1, 1) + doy - 1)
+	return month, day, nil
+}
+
+// setCalendarDate sets the date fields of a time in a calendar other than the proleptic Gregorian one
+func (sp *PosData) setCalendarDate(dt time.Time) {
+	year, month, day := sp.Calendar.Date(dt)
+	sp.Year, sp.Month, sp.Day = year, int(month), day
+	sp.Daynum, _ = sp.Calendar.dayOfYear(sp.Year, sp.Month, sp.Day)
+}
+
+// gregorianDay returns the year and day of year in the proleptic Gregorian calendar, the time scale of the algorithm
+func (sp *PosData) gregorianDay() (year int, daynum int) {
+	if sp.Calendar == CalendarGregorian {
+		return sp.Year, sp.Daynum
+	}
+	dt := sp.Getdate()
+	return dt.Year(), dt.YearDay()
+}
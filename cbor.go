@@ -0,0 +1,69 @@
+package solpos
+
+import (
+	"math"
+	"time"
+)
+
+/* CBOR, see RFC 8949 */
+
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborArray    = 4 << 5
+	cborMap      = 5 << 5
+	cborTag      = 6 << 5
+)
+
+type cborEncoder struct{}
+
+// cborHead appends the initial byte of a data item with its argument in the shortest form
+func cborHead(b []byte, major byte, v uint64) []byte {
+	switch {
+	case v < 24:
+		return append(b, major|byte(v))
+	case v <= math.MaxUint8:
+		return append(b, major|24, byte(v))
+	case v <= math.MaxUint16:
+		return appendUint16(append(b, major|25), uint16(v))
+	case v <= math.MaxUint32:
+		return appendUint32(append(b, major|26), uint32(v))
+	default:
+		return appendUint64(append(b, major|27), v)
+	}
+}
+
+func (cborEncoder) mapHeader(b []byte, n int) []byte {
+	return cborHead(b, cborMap, uint64(n))
+}
+
+func (cborEncoder) arrayHeader(b []byte, n int) []byte {
+	return cborHead(b, cborArray, uint64(n))
+}
+
+func (cborEncoder) float32(b []byte, v float32) []byte {
+	return appendUint32(append(b, 0xfa), math.Float32bits(v))
+}
+
+func (cborEncoder) bool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xf5)
+	}
+	return append(b, 0xf4)
+}
+
+func (cborEncoder) int(b []byte, v int64) []byte {
+	if v < 0 {
+		return cborHead(b, cborNegative, uint64(-1-v))
+	}
+	return cborHead(b, cborUnsigned, uint64(v))
+}
+
+// time appends an epoch-based date/time (tag 1), integer seconds unless there is a fraction
+func (e cborEncoder) time(b []byte, t time.Time) []byte {
+	b = cborHead(b, cborTag, 1)
+	if t.Nanosecond() == 0 {
+		return e.int(b, t.Unix())
+	}
+	return appendUint64(append(b, 0xfb), math.Float64bits(float64(t.Unix())+float64(t.Nanosecond())/1e9))
+}
@@ -0,0 +1,54 @@
+package solpos
+
+import "math"
+
+/*============================================================================
+*    Clear-sky irradiance
+*
+*    Global horizontal irradiance under a cloudless sky.
+*       Haurwitz, B.  1945.  Insolation in relation to cloudiness and cloud
+*            density.  Journal of Meteorology 2, pp. 154-166
+*       Ineichen, P., Perez, R.  2002.  A new airmass independent formulation
+*            for the Linke turbidity coefficient.  Solar Energy 73 (3),
+*            pp. 151-157
+*----------------------------------------------------------------------------*/
+
+// ClearSky is the irradiance under a cloudless sky, W/sq m
+type ClearSky struct {
+	GHI float64 // Global horizontal irradiance
+	DNI float64 // Direct normal irradiance
+	DHI float64 // Diffuse horizontal irradiance
+}
+
+// ClearSkyHaurwitz returns the clear-sky global horizontal irradiance in W/sq m of the Haurwitz model for a calculated
+// sun position (Zenref of r is used). The model needs no atmospheric inputs, which makes it a robust default.
+func ClearSkyHaurwitz(r Result) float64 {
+	if r.Zenref >= 90.0 {
+		return 0.0
+	}
+	coszen := math.Cos(raddeg * r.Zenref)
+	return 1098.0 * coszen * math.Exp(-0.059/coszen)
+}
+
+// ClearSkyIneichen returns the clear-sky irradiance of the Ineichen-Perez model for a calculated sun position (Zenref,
+// Ampress and Etrn of r are used, so press of the calculation should be the pressure of the site), the Linke turbidity
+// (about 2 for very clean, 3 for rural and 5 for polluted air) and the altitude of the site in meters.
+func ClearSkyIneichen(r Result, linkeTurbidity float64, altitude float64) ClearSky {
+	if r.Zenref >= 90.0 || r.Ampress <= 0.0 {
+		return ClearSky{}
+	}
+	coszen := math.Cos(raddeg * r.Zenref)
+	fh1 := math.Exp(-altitude / 8000.0)
+	fh2 := math.Exp(-altitude / 1250.0)
+	cg1 := 5.09e-05*altitude + 0.868
+	cg2 := 3.92e-05*altitude + 0.0387
+
+	ghi := cg1 * r.Etrn * coszen * math.Max(0.0, math.Exp(-cg2*r.Ampress*(fh1+fh2*(linkeTurbidity-1.0))))
+
+	/* beam, limited so that the diffuse part is not negative */
+	b := 0.664 + 0.163/fh1
+	dni := r.Etrn * math.Max(0.0, b*math.Exp(-0.09*r.Ampress*(linkeTurbidity-1.0)))
+	limit := ghi * math.Max(0.0, (1.0-(0.1-0.2*math.Exp(-linkeTurbidity))/(0.1+0.882/fh1))/coszen)
+	dni = math.Min(dni, limit)
+	return ClearSky{GHI: ghi, DNI: dni, DHI: ghi - dni*coszen}
+}
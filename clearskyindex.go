@@ -0,0 +1,77 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ClearSkyModel selects the clear-sky model of ComputeClearSkyIndex
+type ClearSkyModel int
+
+const (
+	ModelIneichen ClearSkyModel = iota // Ineichen-Perez (ClearSkyIneichen), with Linke turbidity and altitude
+	ModelHaurwitz                      // Haurwitz (ClearSkyHaurwitz), no atmospheric inputs
+)
+
+// ClearSkyIndexConfig configures ComputeClearSkyIndex
+type ClearSkyIndexConfig struct {
+	Model              ClearSkyModel          // Clear-sky model, DEFAULT (0) = ModelIneichen
+	LinkeTurbidity     float64                // Linke turbidity of ModelIneichen, DEFAULT (0) = 3
+	Altitude           float64                // Altitude of the site for ModelIneichen, meters
+	MinElevation       float64                // Solar elevation below which the index is undefined (NaN), degrees, DEFAULT (0) = 5
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos, e.g. "press"
+}
+
+// ClearSkyIndexSample is the clear-sky index of a measured sample
+type ClearSkyIndexSample struct {
+	Time     time.Time // Time of the sample
+	GHI      float64   // Measured global horizontal irradiance, W/sq m
+	ClearSky float64   // Modeled clear-sky GHI, W/sq m
+	Index    float64   // Clear-sky index kc = GHI / clear-sky GHI, NaN with the sun below MinElevation
+}
+
+// ComputeClearSkyIndex returns the clear-sky index kc = GHI / GHI clear of every measured global horizontal irradiance
+// sample of a site, the usual normalization before forecasting. At low sun the ratio of two small numbers is mostly
+// noise, the index is NaN with the sun below MinElevation and should be masked (NaN values cannot be encoded as JSON).
+func ComputeClearSkyIndex(site Site, series []IrradianceSample, config ClearSkyIndexConfig) ([]ClearSkyIndexSample, error) {
+	if config.Model < ModelIneichen || config.Model > ModelHaurwitz {
+		return nil, errors.New("Please fix model, unknown")
+	}
+	linke := config.LinkeTurbidity
+	if linke == 0.0 {
+		linke = 3.0
+	}
+	minElevation := config.MinElevation
+	if minElevation == 0.0 {
+		minElevation = 5.0
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+	sp, err := NewSolpos(series[0].Time, site.Latitude, site.Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+	index := make([]ClearSkyIndexSample, len(series))
+	for i, s := range series {
+		sp.SetDate(s.Time)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		r := sp.GetResult()
+		k := ClearSkyIndexSample{Time: s.Time, GHI: s.Value, Index: math.NaN()}
+		switch config.Model {
+		case ModelHaurwitz:
+			k.ClearSky = ClearSkyHaurwitz(r)
+		default:
+			k.ClearSky = ClearSkyIneichen(r, linke, config.Altitude).GHI
+		}
+		if r.Elevref >= minElevation && k.ClearSky > 0.0 {
+			k.Index = s.Value / k.ClearSky
+		}
+		index[i] = k
+	}
+	return index, nil
+}
@@ -0,0 +1,187 @@
+package solpos
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"time"
+)
+
+/* MessagePack and CBOR share the layout, sized for constrained links: a result is a map with small integer keys and
+   single precision values (about 6 bytes per output), a series is an array of results.
+       0        time (timestamp)
+       1        UTC offset, seconds east of UTC
+       2        warnings, array of Warning values (only if any)
+       3        true if the sun is obstructed (only if it is)
+       4 + i    output i of ResultColumns, float32
+   The encoders need no reflection. */
+
+const (
+	compactKeyTime = iota
+	compactKeyUTCOffset
+	compactKeyWarnings
+	compactKeySunObstructed
+	compactKeyColumns
+)
+
+// compactEncoder appends the items of a binary, self-describing format
+type compactEncoder interface {
+	mapHeader(b []byte, n int) []byte
+	arrayHeader(b []byte, n int) []byte
+	float32(b []byte, v float32) []byte
+	int(b []byte, v int64) []byte
+	bool(b []byte, v bool) []byte
+	time(b []byte, t time.Time) []byte
+}
+
+// CompactEncoding are the outputs of the MessagePack and CBOR encodings, the key of an output is 4 plus its index in
+// ResultColumns. Outputs are rounded to float32, about 7 significant digits: angles keep a resolution of about 3e-5
+// degrees (latitude and longitude about 1 m), times in minutes of about 1e-4 minutes. Use the JSON or protobuf encodings
+// where the full precision is needed.
+type CompactEncoding struct {
+	columns []int
+}
+
+// NewCompactEncoding creates new instance of CompactEncoding with the given outputs of ResultColumns (e.g. "zenref",
+// "azim"), all outputs if no columns are given
+func NewCompactEncoding(columns ...string) (CompactEncoding, error) {
+	idx := make([]int, 0, len(resultColumns))
+	if len(columns) == 0 {
+		for i := range resultColumns {
+			idx = append(idx, i)
+		}
+		return CompactEncoding{idx}, nil
+	}
+	for _, c := range columns {
+		i := 0
+		for i < len(resultColumns) && resultColumns[i] != c {
+			i++
+		}
+		if i == len(resultColumns) {
+			return CompactEncoding{}, errors.New("Please fix the column " + c + ", see ResultColumns")
+		}
+		idx = append(idx, i)
+	}
+	return CompactEncoding{idx}, nil
+}
+
+// compactAll encodes all outputs
+var compactAll, _ = NewCompactEncoding()
+
+func (c CompactEncoding) appendResult(e compactEncoder, b []byte, r Result) []byte {
+	n := 2 + len(c.columns)
+	if len(r.Warnings) > 0 {
+		n++
+	}
+	if r.SunObstructed {
+		n++
+	}
+	b = e.mapHeader(b, n)
+	b = e.time(e.int(b, compactKeyTime), r.Time)
+	_, offset := r.Time.Zone()
+	b = e.int(e.int(b, compactKeyUTCOffset), int64(offset))
+	if len(r.Warnings) > 0 {
+		b = e.arrayHeader(e.int(b, compactKeyWarnings), len(r.Warnings))
+		for _, w := range r.Warnings {
+			b = e.int(b, int64(w))
+		}
+	}
+	if r.SunObstructed {
+		b = e.bool(e.int(b, compactKeySunObstructed), true)
+	}
+	values := r.Values()
+	for _, i := range c.columns {
+		b = e.float32(e.int(b, int64(compactKeyColumns+i)), float32(values[i]))
+	}
+	return b
+}
+
+func (c CompactEncoding) appendSeries(e compactEncoder, b []byte, results []Result) []byte {
+	b = e.arrayHeader(b, len(results))
+	for _, r := range results {
+		b = c.appendResult(e, b, r)
+	}
+	return b
+}
+
+// AppendMsgpack appends the MessagePack encoding of the outputs of the result to b
+func (c CompactEncoding) AppendMsgpack(b []byte, r Result) []byte {
+	return c.appendResult(msgpackEncoder{}, b, r)
+}
+
+// AppendCBOR appends the CBOR (RFC 8949) encoding of the outputs of the result to b
+func (c CompactEncoding) AppendCBOR(b []byte, r Result) []byte {
+	return c.appendResult(cborEncoder{}, b, r)
+}
+
+// AppendMsgpackSeries appends the MessagePack encoding of the outputs of a series of results (an array) to b
+func (c CompactEncoding) AppendMsgpackSeries(b []byte, results []Result) []byte {
+	return c.appendSeries(msgpackEncoder{}, b, results)
+}
+
+// AppendCBORSeries appends the CBOR encoding of the outputs of a series of results (an array) to b
+func (c CompactEncoding) AppendCBORSeries(b []byte, results []Result) []byte {
+	return c.appendSeries(cborEncoder{}, b, results)
+}
+
+// NewMsgpackSink creates a ResultsSink writing one MessagePack map of the outputs per result to w
+func (c CompactEncoding) NewMsgpackSink(w io.Writer) ResultsSink {
+	return &compactSink{w: bufio.NewWriter(w), enc: msgpackEncoder{}, layout: c}
+}
+
+// NewCBORSink creates a ResultsSink writing one CBOR map of the outputs per result to w (a CBOR sequence, RFC 8742)
+func (c CompactEncoding) NewCBORSink(w io.Writer) ResultsSink {
+	return &compactSink{w: bufio.NewWriter(w), enc: cborEncoder{}, layout: c}
+}
+
+// AppendMsgpack appends the MessagePack encoding of all outputs of the result to b, see CompactEncoding to select outputs
+func (r Result) AppendMsgpack(b []byte) []byte {
+	return compactAll.AppendMsgpack(b, r)
+}
+
+// AppendCBOR appends the CBOR (RFC 8949) encoding of all outputs of the result to b, see CompactEncoding to select outputs
+func (r Result) AppendCBOR(b []byte) []byte {
+	return compactAll.AppendCBOR(b, r)
+}
+
+// AppendMsgpackSeries appends the MessagePack encoding of a series of results (an array) to b
+func AppendMsgpackSeries(b []byte, results []Result) []byte {
+	return compactAll.AppendMsgpackSeries(b, results)
+}
+
+// AppendCBORSeries appends the CBOR encoding of a series of results (an array) to b
+func AppendCBORSeries(b []byte, results []Result) []byte {
+	return compactAll.AppendCBORSeries(b, results)
+}
+
+// NewMsgpackSink creates a ResultsSink writing one MessagePack map per result to w
+func NewMsgpackSink(w io.Writer) ResultsSink {
+	return compactAll.NewMsgpackSink(w)
+}
+
+// NewCBORSink creates a ResultsSink writing one CBOR map per result to w (a CBOR sequence, RFC 8742)
+func NewCBORSink(w io.Writer) ResultsSink {
+	return compactAll.NewCBORSink(w)
+}
+
+type compactSink struct {
+	w      *bufio.Writer
+	enc    compactEncoder
+	layout CompactEncoding
+	buf    []byte
+}
+
+func (s *compactSink) Write(r Result) error {
+	// reuse the buffer to keep allocations low on long series
+	s.buf = s.layout.appendResult(s.enc, s.buf[:0], r)
+	_, err := s.w.Write(s.buf)
+	return err
+}
+
+func (s *compactSink) Flush() error {
+	return s.w.Flush()
+}
+
+func (s *compactSink) Close() error {
+	return s.Flush()
+}
@@ -0,0 +1,194 @@
+package solpos
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMsgpackInt(t *testing.T) {
+	/* the boundaries of the forms of the MessagePack specification */
+	tests := []struct {
+		v    int64
+		want string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "cc80"},
+		{255, "ccff"},
+		{256, "cd0100"},
+		{65535, "cdffff"},
+		{65536, "ce00010000"},
+		{math.MaxUint32, "ceffffffff"},
+		{math.MaxUint32 + 1, "cf0000000100000000"},
+		{-1, "ff"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{-128, "d080"},
+		{-129, "d1ff7f"},
+		{-32768, "d18000"},
+		{-32769, "d2ffff7fff"},
+		{math.MinInt32, "d280000000"},
+		{math.MinInt32 - 1, "d3ffffffff7fffffff"},
+		{math.MinInt64, "d38000000000000000"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(msgpackEncoder{}.int(nil, tt.v)); got != tt.want {
+			t.Errorf("int(%d) = %s, want %s", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestCBORInt(t *testing.T) {
+	/* RFC 8949, appendix A */
+	tests := []struct {
+		v    int64
+		want string
+	}{
+		{0, "00"},
+		{10, "0a"},
+		{23, "17"},
+		{24, "1818"},
+		{100, "1864"},
+		{1000, "1903e8"},
+		{1000000, "1a000f4240"},
+		{1000000000000, "1b000000e8d4a51000"},
+		{-1, "20"},
+		{-10, "29"},
+		{-100, "3863"},
+		{-1000, "3903e7"},
+		{math.MinInt64, "3b7fffffffffffffff"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(cborEncoder{}.int(nil, tt.v)); got != tt.want {
+			t.Errorf("int(%d) = %s, want %s", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestCompactFloat32(t *testing.T) {
+	tests := []struct {
+		v       float64
+		msgpack string
+		cbor    string
+	}{
+		{1.5, "ca3fc00000", "fa3fc00000"},
+		{100000.0, "ca47c35000", "fa47c35000"},
+		{3.4028234663852886e+38, "ca7f7fffff", "fa7f7fffff"},
+		{math.Inf(1), "ca7f800000", "fa7f800000"},
+		{math.Inf(-1), "caff800000", "faff800000"},
+		{math.NaN(), "ca7fc00000", "fa7fc00000"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(msgpackEncoder{}.float32(nil, float32(tt.v))); got != tt.msgpack {
+			t.Errorf("msgpack float32(%v) = %s, want %s", tt.v, got, tt.msgpack)
+		}
+		if got := hex.EncodeToString(cborEncoder{}.float32(nil, float32(tt.v))); got != tt.cbor {
+			t.Errorf("CBOR float32(%v) = %s, want %s", tt.v, got, tt.cbor)
+		}
+	}
+}
+
+func TestCompactHeaders(t *testing.T) {
+	tests := []struct {
+		n                        int
+		msgpackMap, msgpackArray string
+		cborMap, cborArray       string
+	}{
+		{0, "80", "90", "a0", "80"},
+		{15, "8f", "9f", "af", "8f"},
+		{16, "de0010", "dc0010", "b0", "90"},
+		{23, "de0017", "dc0017", "b7", "97"},
+		{24, "de0018", "dc0018", "b818", "9818"},
+		{255, "de00ff", "dc00ff", "b8ff", "98ff"},
+		{256, "de0100", "dc0100", "b90100", "990100"},
+		{65535, "deffff", "dcffff", "b9ffff", "99ffff"},
+		{65536, "df00010000", "dd00010000", "ba00010000", "9a00010000"},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct{ got, want string }{
+			{hex.EncodeToString(msgpackEncoder{}.mapHeader(nil, tt.n)), tt.msgpackMap},
+			{hex.EncodeToString(msgpackEncoder{}.arrayHeader(nil, tt.n)), tt.msgpackArray},
+			{hex.EncodeToString(cborEncoder{}.mapHeader(nil, tt.n)), tt.cborMap},
+			{hex.EncodeToString(cborEncoder{}.arrayHeader(nil, tt.n)), tt.cborArray},
+		} {
+			if c.got != c.want {
+				t.Errorf("header of %d = %s, want %s", tt.n, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestCompactTime(t *testing.T) {
+	tests := []struct {
+		t             time.Time
+		msgpack, cbor string
+	}{
+		/* timestamp 32 and 64 of the MessagePack specification, RFC 8949 appendix A */
+		{time.Unix(1363896240, 0), "d6ff514b67b0", "c11a514b67b0"},
+		{time.Unix(1363896240, 500000000), "d7ff77359400514b67b0", "c1fb41d452d9ec200000"},
+		{time.Unix(0, 0), "d6ff00000000", "c100"},
+		/* timestamp 96 before 1970 */
+		{time.Unix(-1, 0), "c70cff00000000ffffffffffffffff", "c120"},
+		{time.Unix(1<<34, 0), "c70cff000000000000000400000000", "c11b0000000400000000"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(msgpackEncoder{}.time(nil, tt.t)); got != tt.msgpack {
+			t.Errorf("msgpack time(%v) = %s, want %s", tt.t, got, tt.msgpack)
+		}
+		if got := hex.EncodeToString(cborEncoder{}.time(nil, tt.t)); got != tt.cbor {
+			t.Errorf("CBOR time(%v) = %s, want %s", tt.t, got, tt.cbor)
+		}
+	}
+}
+
+func TestCompactResult(t *testing.T) {
+	c, err := NewCompactEncoding("zenref", "azim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Result{
+		Time:     time.Date(2021, 6, 1, 12, 0, 0, 0, time.FixedZone("", 7200)),
+		Zenref:   32.5,
+		Azim:     math.NaN(),
+		Warnings: []Warning{WarnPole, WarnPolarDay},
+
+		SunObstructed: true,
+	}
+	/* {0: time, 1: 7200, 2: [1, 2], 3: true, 27: 32.5, 8: NaN} */
+	msgpack := "86" + "00d6ff60b60520" + "01cd1c20" + "02920102" + "03c3" + "1bca42020000" + "08ca7fc00000"
+	cbor := "a6" + "00c11a60b60520" + "01191c20" + "02820102" + "03f5" + "181bfa42020000" + "08fa7fc00000"
+	if got := hex.EncodeToString(c.AppendMsgpack(nil, r)); got != msgpack {
+		t.Errorf("AppendMsgpack() = %s, want %s", got, msgpack)
+	}
+	if got := hex.EncodeToString(c.AppendCBOR(nil, r)); got != cbor {
+		t.Errorf("AppendCBOR() = %s, want %s", got, cbor)
+	}
+	var buf bytes.Buffer
+	sink := c.NewCBORSink(&buf)
+	for i := 0; i < 2; i++ {
+		if err := sink.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(buf.Bytes()); got != cbor+cbor {
+		t.Errorf("CBOR sequence = %s, want %s", got, cbor+cbor)
+	}
+	series := make([]Result, 70000)
+	for i := range series {
+		series[i] = r
+	}
+	b := c.AppendMsgpackSeries(nil, series)
+	if want := 5 + len(series)*len(msgpack)/2; !bytes.HasPrefix(b, []byte{0xdd, 0, 1, 0x11, 0x70}) || len(b) != want {
+		t.Errorf("msgpack series of %d results: %d bytes starting with %x, want %d", len(series), len(b), b[:5], want)
+	}
+	b = c.AppendCBORSeries(nil, series[:16])
+	if want := 1 + 16*len(cbor)/2; b[0] != 0x90 || len(b) != want {
+		t.Errorf("CBOR series of 16 results: %d bytes starting with %x, want %d", len(b), b[0], want)
+	}
+}
@@ -0,0 +1,28 @@
+package solpos
+
+import "time"
+
+// Input are the inputs of Compute
+type Input struct {
+	Time      time.Time // Date and time, the location of the time sets the timezone
+	Latitude  float64   // Latitude, degrees north (south negative)
+	Longitude float64   // Longitude, degrees east (west negative)
+	Options   *Options  // Optional parameters, DEFAULT (nil) = DefaultOptions()
+}
+
+// Output are the outputs of Compute, the same snapshot as GetResult returns
+type Output = Result
+
+// Compute calculates the outputs of a single set of inputs without any shared state, it is safe for concurrent use as long
+// as the TSI and AtmosphereProvider of the options are
+func Compute(in Input) (Output, error) {
+	options := DefaultOptions()
+	if in.Options != nil {
+		options = *in.Options
+	}
+	sp, err := NewSolposWithOptions(in.Time, in.Latitude, in.Longitude, options)
+	if err != nil {
+		return Output{}, err
+	}
+	return sp.GetResult(), nil
+}
@@ -0,0 +1,68 @@
+package solpos
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCompute(t *testing.T) {
+	in := Input{Time: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), Latitude: 52.52, Longitude: 13.40}
+	out, err := Compute(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp, err := New(in.Time, in.Latitude, in.Longitude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, sp.GetResult()) {
+		t.Errorf("Compute = %+v, want %+v", out, sp.GetResult())
+	}
+}
+
+// TestComputeConcurrent runs Compute from many goroutines with shared options, run with -race to detect shared state
+func TestComputeConcurrent(t *testing.T) {
+	options := DefaultOptions()
+	options.Tilt = 30.0
+	var inputs []Input
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 64; i++ {
+		inputs = append(inputs, Input{Time: start.Add(time.Duration(i) * 137 * time.Hour), Latitude: float64(i%150) - 75.0,
+			Longitude: float64(i*7%360) - 180.0, Options: &options})
+	}
+	want := make([]Output, len(inputs))
+	for i, in := range inputs {
+		out, err := Compute(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[i] = out
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, len(inputs)*8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for k := range inputs {
+				i := (k + g*len(inputs)/8) % len(inputs)
+				out, err := Compute(inputs[i])
+				if err != nil {
+					errs <- err.Error()
+					continue
+				}
+				if !reflect.DeepEqual(out, want[i]) {
+					errs <- "different output for input " + inputs[i].Time.String()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		t.Error(e)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/maltegrosse/go-solpos/contrib/gonumsolpos
+
+go 1.14
+
+require (
+	github.com/maltegrosse/go-solpos v0.1.0
+	gonum.org/v1/gonum v0.9.1
+)
+
+// v0.1.0 is the first release with the series sinks. Until it is tagged the module is built against the working
+// tree, remove this directive after tagging.
+replace github.com/maltegrosse/go-solpos => ../..
@@ -0,0 +1,114 @@
+// Package gonumsolpos returns series of go-solpos results as gonum matrices, rows are timestamps and columns the
+// selected outputs, to feed them straight into linear algebra and fitting routines.
+//
+// It lives in its own module, so the solpos package itself stays free of dependencies.
+package gonumsolpos
+
+import (
+	"errors"
+	"time"
+
+	solpos "github.com/maltegrosse/go-solpos"
+	"gonum.org/v1/gonum/mat"
+)
+
+// indices returns the positions of the columns in solpos.Result.Values, all columns if none are given
+func indices(columns []string) ([]int, error) {
+	all := solpos.ResultColumns()
+	if len(columns) == 0 {
+		idx := make([]int, len(all))
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx, nil
+	}
+	idx := make([]int, len(columns))
+	for i, c := range columns {
+		idx[i] = -1
+		for j, name := range all {
+			if name == c {
+				idx[i] = j
+				break
+			}
+		}
+		if idx[i] < 0 {
+			return nil, errors.New("Please fix column " + c + ", not an output (see solpos.ResultColumns)")
+		}
+	}
+	return idx, nil
+}
+
+// Dense returns the results as a matrix with one row per result and one column per selected output, named as in
+// solpos.ResultColumns (e.g. "zenref", "azim"), all outputs if no columns are given
+func Dense(results []solpos.Result, columns ...string) (*mat.Dense, error) {
+	if len(results) == 0 {
+		return nil, errors.New("Please fix results, at least one is required")
+	}
+	idx, err := indices(columns)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]float64, 0, len(results)*len(idx))
+	for _, r := range results {
+		values := r.Values()
+		for _, j := range idx {
+			data = append(data, values[j])
+		}
+	}
+	return mat.NewDense(len(results), len(idx), data), nil
+}
+
+// Times returns the times of the results, the row index of Dense
+func Times(results []solpos.Result) []time.Time {
+	times := make([]time.Time, len(results))
+	for i, r := range results {
+		times[i] = r.Time
+	}
+	return times
+}
+
+// Sink is a solpos.ResultsSink collecting the selected outputs of a streamed series, e.g. of StreamSeries
+type Sink struct {
+	idx   []int
+	data  []float64
+	times []time.Time
+}
+
+// NewSink creates new instance of Sink collecting the given columns, all outputs if no columns are given
+func NewSink(columns ...string) (*Sink, error) {
+	idx, err := indices(columns)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{idx: idx}, nil
+}
+
+// Write stores the selected outputs of a single result
+func (s *Sink) Write(r solpos.Result) error {
+	values := r.Values()
+	for _, j := range s.idx {
+		s.data = append(s.data, values[j])
+	}
+	s.times = append(s.times, r.Time)
+	return nil
+}
+
+// Close does nothing, the collected results stay available
+func (s *Sink) Close() error {
+	return nil
+}
+
+// Dense returns the collected results as a matrix, one row per result, nil if no result was written
+func (s *Sink) Dense() *mat.Dense {
+	if len(s.times) == 0 {
+		return nil
+	}
+	return mat.NewDense(len(s.times), len(s.idx), s.data)
+}
+
+// Times returns the times of the collected results, the row index of Dense
+func (s *Sink) Times() []time.Time {
+	return s.times
+}
+
+var _ solpos.ResultsSink = (*Sink)(nil)
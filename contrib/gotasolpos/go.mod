@@ -0,0 +1,12 @@
+module github.com/maltegrosse/go-solpos/contrib/gotasolpos
+
+go 1.13
+
+require (
+	github.com/go-gota/gota v0.12.0
+	github.com/maltegrosse/go-solpos v0.1.0
+)
+
+// v0.1.0 is the first release with the series sinks. Until it is tagged the module is built against the working
+// tree, remove this directive after tagging.
+replace github.com/maltegrosse/go-solpos => ../..
@@ -0,0 +1,83 @@
+// Package gotasolpos returns series of go-solpos results as gota DataFrames, for exploratory analysis e.g. in Go
+// notebooks.
+//
+// It lives in its own module, so the solpos package itself stays free of dependencies.
+package gotasolpos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+	solpos "github.com/maltegrosse/go-solpos"
+)
+
+// TimeColumn is the name of the timestamp column, the index of the DataFrame. Gota has no time type, the times are
+// formatted as RFC 3339 with nanoseconds and the offset of the calculation, which sorts chronologically within one offset.
+const TimeColumn = "time"
+
+// DataFrame returns the results as a DataFrame with the timestamp column followed by one float column per selected output,
+// named as in solpos.ResultColumns (e.g. "zenref", "azim"), all outputs if no columns are given
+func DataFrame(results []solpos.Result, columns ...string) (dataframe.DataFrame, error) {
+	all := solpos.ResultColumns()
+	if len(columns) == 0 {
+		columns = all
+	}
+	idx := make([]int, len(columns))
+	for i, c := range columns {
+		idx[i] = -1
+		for j, name := range all {
+			if name == c {
+				idx[i] = j
+				break
+			}
+		}
+		if idx[i] < 0 {
+			return dataframe.DataFrame{}, errors.New("Please fix column " + c + ", not an output (see solpos.ResultColumns)")
+		}
+	}
+
+	times := make([]string, len(results))
+	values := make([][]float64, len(columns))
+	for i := range values {
+		values[i] = make([]float64, len(results))
+	}
+	for k, r := range results {
+		times[k] = r.Time.Format(time.RFC3339Nano)
+		v := r.Values()
+		for i, j := range idx {
+			values[i][k] = v[j]
+		}
+	}
+	s := make([]series.Series, 0, len(columns)+1)
+	s = append(s, series.New(times, series.String, TimeColumn))
+	for i, c := range columns {
+		s = append(s, series.New(values[i], series.Float, c))
+	}
+	df := dataframe.New(s...)
+	return df, df.Err
+}
+
+// Sink is a solpos.ResultsSink collecting a streamed series, e.g. of StreamSeries, for DataFrame
+type Sink struct {
+	results []solpos.Result
+}
+
+// Write stores a single result
+func (s *Sink) Write(r solpos.Result) error {
+	s.results = append(s.results, r)
+	return nil
+}
+
+// Close does nothing, the collected results stay available
+func (s *Sink) Close() error {
+	return nil
+}
+
+// DataFrame returns the collected results as a DataFrame, see DataFrame
+func (s *Sink) DataFrame(columns ...string) (dataframe.DataFrame, error) {
+	return DataFrame(s.results, columns...)
+}
+
+var _ solpos.ResultsSink = (*Sink)(nil)
@@ -0,0 +1,14 @@
+module github.com/maltegrosse/go-solpos/contrib/otelsolpos
+
+go 1.19
+
+require (
+	github.com/maltegrosse/go-solpos v0.1.0
+	go.opentelemetry.io/otel v1.16.0
+	go.opentelemetry.io/otel/metric v1.16.0
+	go.opentelemetry.io/otel/trace v1.16.0
+)
+
+// v0.1.0 is the first release with the instrumentation hook. Until it is tagged the module is built against the
+// working tree, remove this directive after tagging.
+replace github.com/maltegrosse/go-solpos => ../..
@@ -0,0 +1,92 @@
+// Package otelsolpos reports the computations of go-solpos as OpenTelemetry spans and metrics.
+//
+// It lives in its own module, so the solpos package itself stays free of dependencies. Install it once at startup:
+//
+//	i, err := otelsolpos.New(otel.GetTracerProvider(), otel.GetMeterProvider())
+//	if err == nil {
+//		solpos.SetInstrumentation(i)
+//	}
+package otelsolpos
+
+import (
+	"context"
+	"time"
+
+	solpos "github.com/maltegrosse/go-solpos"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scope is the instrumentation scope name of tracer and meter
+const scope = "github.com/maltegrosse/go-solpos"
+
+// Instrumentation implements solpos.Instrumentation with OpenTelemetry. Every computation becomes a span with the
+// attribute solpos.points, and the following metrics are recorded:
+//
+//	solpos.operation.duration  histogram (s) of computation durations, by solpos.operation
+//	solpos.points              counter of points computed, by solpos.operation
+//	solpos.cache.lookups       counter of ResultCache lookups, by solpos.cache.hit (the hit rate is hit=true / all)
+type Instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	points   metric.Int64Counter
+	lookups  metric.Int64Counter
+}
+
+var _ solpos.Instrumentation = (*Instrumentation)(nil)
+
+var (
+	hitAttributes  = metric.WithAttributes(attribute.Bool("solpos.cache.hit", true))
+	missAttributes = metric.WithAttributes(attribute.Bool("solpos.cache.hit", false))
+)
+
+// New creates an Instrumentation with a tracer and meter of the given providers
+func New(tp trace.TracerProvider, mp metric.MeterProvider) (*Instrumentation, error) {
+	meter := mp.Meter(scope)
+	i := &Instrumentation{tracer: tp.Tracer(scope)}
+	var err error
+	i.duration, err = meter.Float64Histogram("solpos.operation.duration",
+		metric.WithDescription("Duration of solar position computations"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	i.points, err = meter.Int64Counter("solpos.points",
+		metric.WithDescription("Number of solar positions computed"), metric.WithUnit("{point}"))
+	if err != nil {
+		return nil, err
+	}
+	i.lookups, err = meter.Int64Counter("solpos.cache.lookups",
+		metric.WithDescription("Number of result cache lookups"), metric.WithUnit("{lookup}"))
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// Start implements solpos.Instrumentation
+func (i *Instrumentation) Start(operation string) func(points int, err error) {
+	begin := time.Now()
+	ctx, span := i.tracer.Start(context.Background(), "solpos."+operation)
+	return func(points int, err error) {
+		op := metric.WithAttributes(attribute.String("solpos.operation", operation))
+		i.duration.Record(ctx, time.Since(begin).Seconds(), op)
+		i.points.Add(ctx, int64(points), op)
+		span.SetAttributes(attribute.Int("solpos.points", points))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// CacheLookup implements solpos.Instrumentation
+func (i *Instrumentation) CacheLookup(hit bool) {
+	if hit {
+		i.lookups.Add(context.Background(), 1, hitAttributes)
+	} else {
+		i.lookups.Add(context.Background(), 1, missAttributes)
+	}
+}
@@ -0,0 +1,34 @@
+package solpos
+
+import "math"
+
+// AcceptanceCheck tells whether the sun is within the acceptance angle of a concentrator (CPV) module
+type AcceptanceCheck struct {
+	Within    bool    // Sun is up and within the acceptance half-angle
+	Incidence float64 // Angle between the sun and the module optical axis, degrees
+	Margin    float64 // Acceptance half-angle minus incidence, degrees (negative outside of the acceptance)
+}
+
+// CheckAcceptance checks a CPV module with the given acceptance half-angle (degrees) mounted on a tracker in state s
+// against the calculated (refracted) sun position of r
+func CheckAcceptance(r Result, s TrackerState, halfAngle float64) AcceptanceCheck {
+	return CheckAcceptancePointing(r, s.SurfaceAzimuth, 90.0-s.SurfaceTilt, halfAngle)
+}
+
+// CheckAcceptancePointing checks a CPV module with the given acceptance half-angle (degrees) whose optical axis points
+// to azimuth and elevation (e.g. a two-axis tracker) against the calculated (refracted) sun position of r
+func CheckAcceptancePointing(r Result, azimuth float64, elevation float64, halfAngle float64) AcceptanceCheck {
+	sun := sunVector(r.Zenref, r.Azim)
+	axis := sunVector(90.0-elevation, azimuth)
+	/* angle from cross and dot product, acos is imprecise for the small angles of interest */
+	cross := [3]float64{
+		sun[1]*axis[2] - sun[2]*axis[1],
+		sun[2]*axis[0] - sun[0]*axis[2],
+		sun[0]*axis[1] - sun[1]*axis[0],
+	}
+	dot := sun[0]*axis[0] + sun[1]*axis[1] + sun[2]*axis[2]
+	c := AcceptanceCheck{Incidence: degrad * math.Atan2(math.Sqrt(cross[0]*cross[0]+cross[1]*cross[1]+cross[2]*cross[2]), dot)}
+	c.Margin = halfAngle - c.Incidence
+	c.Within = c.Margin >= 0.0 && r.Zenref <= 90.0
+	return c
+}
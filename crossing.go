@@ -0,0 +1,75 @@
+package solpos
+
+import (
+	"time"
+)
+
+// crossing is a time at which the sun passes an elevation
+type crossing struct {
+	Time   time.Time
+	Rising bool // true if the sun rises above the elevation, false if it sets below it
+}
+
+// crossingResolution is the resolution of bisected crossings, the calculation resolves whole seconds
+const crossingResolution = time.Second
+
+// bisect returns the time, truncated to crossingResolution, at which after changes from false (at lo) to true (at hi)
+func bisect(lo time.Time, hi time.Time, after func(t time.Time) (bool, error)) (time.Time, error) {
+	for hi.Sub(lo) > crossingResolution {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		a, err := after(mid)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if a {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi.Truncate(crossingResolution), nil
+}
+
+// elevationAt calculates the unrefracted solar elevation (Elevetr) at dt
+func elevationAt(sp Solpos, dt time.Time) (float64, error) {
+	sp.SetDate(dt)
+	err := sp.Calculate()
+	if err != nil {
+		return 0, err
+	}
+	return sp.GetElevetr(), nil
+}
+
+// elevationCrossings returns the times from start to end at which the unrefracted elevation of the sun passes elevation, in
+// order. The elevation is sampled every step and each change of side is bisected to crossingResolution, so two crossings closer than step
+// (the sun grazing the elevation) may be missed. Elevetr is limited to -9 degrees at night, lower elevations are never crossed.
+func elevationCrossings(sp Solpos, start time.Time, end time.Time, step time.Duration, elevation float64) ([]crossing, error) {
+	var crossings []crossing
+	prev, err := elevationAt(sp, start)
+	if err != nil {
+		return nil, err
+	}
+	for t0 := start; t0.Before(end); {
+		t1 := t0.Add(step)
+		if t1.After(end) {
+			t1 = end
+		}
+		e1, err := elevationAt(sp, t1)
+		if err != nil {
+			return nil, err
+		}
+		if (prev < elevation) != (e1 < elevation) {
+			rising := e1 >= elevation
+			t, err := bisect(t0, t1, func(mid time.Time) (bool, error) {
+				e, err := elevationAt(sp, mid)
+				return (e >= elevation) == rising, err
+			})
+			if err != nil {
+				return nil, err
+			}
+			crossings = append(crossings, crossing{Time: t, Rising: rising})
+		}
+		t0, prev = t1, e1
+	}
+	return crossings, nil
+}
@@ -0,0 +1,131 @@
+package solpos
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// YearPolicy defines the handling of years outside the limits of the algorithm, 1950-2050 for AlgorithmSOLPOS
+type YearPolicy int
+
+const (
+	YearError  YearPolicy = iota // Calculate returns an error
+	YearWarn                     // Calculate uses the Michalsky formulae anyway and reports WarnYearRange, the accuracy degrades with the distance to the range
+	YearExtend                   // Calculate switches to AlgorithmSPA (-2000-6000) for the years outside the range of the Algorithm
+)
+
+func (sp *PosData) SetYearPolicy(policy YearPolicy) {
+	sp.YearPolicy = policy
+}
+
+func (sp *PosData) GetYearPolicy() YearPolicy {
+	return sp.YearPolicy
+}
+
+// algorithm returns the algorithm calculating the year, AlgorithmSPA for years outside the range of Algorithm with YearExtend
+func (sp *PosData) algorithm() Algorithm {
+	if first, last := sp.Algorithm.years(); sp.YearPolicy == YearExtend && (sp.Year < first || sp.Year > last) {
+		return AlgorithmSPA
+	}
+	return sp.Algorithm
+}
+
+// daysPerMonth is the number of days of each month (January = 1) in common and leap years
+var daysPerMonth = [2][13]int{{0, 31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}, {0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}}
+
+// leap returns 1 for leap years of the Gregorian calendar and 0 otherwise, the index into monthDays and daysPerMonth
+func leap(year int) int {
+	if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		return 1
+	}
+	return 0
+}
+
+// leapDays returns the number of Gregorian leap years from year 0 up to and including year, negative before year 0
+func leapDays(year int) int {
+	return floorDiv(year, 4) - floorDiv(year, 100) + floorDiv(year, 400)
+}
+
+func floorDiv(a int, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// DayOfYear returns the day of year (Feb 1 = 32) of a date, or an error for dates which do not exist, e.g. Feb 30 or Feb 29 of a common year
+func DayOfYear(year int, month int, day int) (int, error) {
+	if month < 1 || month > 12 {
+		return 0, errors.New("Please fix the month [1-12]")
+	}
+	if days := daysPerMonth[leap(year)][month]; day < 1 || day > days {
+		return 0, errors.New("Please fix the day [1-" + strconv.Itoa(days) + "]")
+	}
+	return monthDays[leap(year)][month] + day, nil
+}
+
+// MonthDay returns month and day of month of a day of year, or an error for days beyond the end of the year, e.g. 366 of a common year
+func MonthDay(year int, doy int) (month int, day int, err error) {
+	l := leap(year)
+	if doy < 1 || doy > 365+l {
+		return 0, 0, errors.New("Please fix the day of year [1-" + strconv.Itoa(365+l) + "]")
+	}
+	/* Find the month */
+	month = 12
+	for doy <= monthDays[l][month] {
+		month--
+	}
+	return month, doy - monthDays[l][month], nil
+}
+
+// normalizeHour24 turns 24:00:00, the end of an interval at midnight, into 00:00:00 of the following day,
+// adjusting day of year, month, day and year. Month, day and day of year must be consistent.
+func (sp *PosData) normalizeHour24() error {
+	if sp.Hour != 24 {
+		return nil
+	}
+	/* no more than 24 hrs */
+	if sp.Minute != 0 || sp.Second != 0 {
+		return errors.New("Please fix hour, minute and second, hour 24 is only valid at 24:00:00")
+	}
+	sp.Hour = 0
+	sp.Daynum++
+	if sp.Daynum > sp.Calendar.daysInYear(sp.Year) {
+		sp.Year++
+		sp.Daynum = 1
+	}
+	month, day, err := sp.Calendar.monthDay(sp.Year, sp.Daynum)
+	if err != nil {
+		return err
+	}
+	sp.Month, sp.Day = month, day
+	return nil
+}
+
+// foldLeapSecond clamps second 60 (a positive leap second) to 59 of the same minute. The sun moves about 15 arcseconds in that
+// second, which is far below the accuracy of the algorithm, and the date and day of year stay those of the leap second.
+func (sp *PosData) foldLeapSecond() {
+	if sp.Second == 60 {
+		sp.Second = 59
+	}
+}
+
+// ParseInLocation parses a time like time.ParseInLocation, but accepts leap seconds (second 60, e.g. "2016-12-31T23:59:60Z"),
+// which are clamped to second 59 of the same minute like the leap seconds set by SetSecond
+func ParseInLocation(layout string, value string, loc *time.Location) (time.Time, error) {
+	dt, err := time.ParseInLocation(layout, value, loc)
+	perr, ok := err.(*time.ParseError)
+	if !ok || !strings.Contains(perr.Message, "second out of range") || !strings.Contains(value, ":60") {
+		return dt, err
+	}
+	i := strings.LastIndex(value, ":60")
+	folded, foldErr := time.ParseInLocation(layout, value[:i]+":59"+value[i+3:], loc)
+	if foldErr != nil {
+		/* not a leap second, report the original error */
+		return dt, err
+	}
+	return folded, nil
+}
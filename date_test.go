@@ -0,0 +1,166 @@
+package solpos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayOfYear(t *testing.T) {
+	tests := []struct {
+		year, month, day int
+		doy              int
+		ok               bool
+	}{
+		{2021, 1, 1, 1, true},
+		{2021, 2, 1, 32, true},
+		{2021, 12, 31, 365, true},
+		{2020, 2, 29, 60, true},
+		{2020, 3, 1, 61, true},
+		{2020, 12, 31, 366, true},
+		{2000, 2, 29, 60, true},
+		{2021, 2, 29, 0, false},
+		{1900, 2, 29, 0, false},
+		{2020, 2, 30, 0, false},
+		{2021, 4, 31, 0, false},
+		{2021, 0, 1, 0, false},
+		{2021, 13, 1, 0, false},
+		{2021, 1, 0, 0, false},
+	}
+	for _, tt := range tests {
+		doy, err := DayOfYear(tt.year, tt.month, tt.day)
+		if (err == nil) != tt.ok {
+			t.Errorf("DayOfYear(%d, %d, %d) error = %v, want ok %v", tt.year, tt.month, tt.day, err, tt.ok)
+			continue
+		}
+		if doy != tt.doy {
+			t.Errorf("DayOfYear(%d, %d, %d) = %d, want %d", tt.year, tt.month, tt.day, doy, tt.doy)
+		}
+	}
+}
+
+func TestMonthDay(t *testing.T) {
+	tests := []struct {
+		year, doy  int
+		month, day int
+		ok         bool
+	}{
+		{2021, 1, 1, 1, true},
+		{2021, 32, 2, 1, true},
+		{2021, 59, 2, 28, true},
+		{2021, 60, 3, 1, true},
+		{2021, 365, 12, 31, true},
+		{2020, 60, 2, 29, true},
+		{2020, 366, 12, 31, true},
+		{2021, 366, 0, 0, false},
+		{1900, 366, 0, 0, false},
+		{2020, 367, 0, 0, false},
+		{2021, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		month, day, err := MonthDay(tt.year, tt.doy)
+		if (err == nil) != tt.ok {
+			t.Errorf("MonthDay(%d, %d) error = %v, want ok %v", tt.year, tt.doy, err, tt.ok)
+			continue
+		}
+		if month != tt.month || day != tt.day {
+			t.Errorf("MonthDay(%d, %d) = %d, %d, want %d, %d", tt.year, tt.doy, month, day, tt.month, tt.day)
+		}
+	}
+}
+
+func TestDayOfYearRoundTrip(t *testing.T) {
+	for _, year := range []int{1900, 2000, 2020, 2021} {
+		for doy := 1; doy <= 365+leap(year); doy++ {
+			month, day, err := MonthDay(year, doy)
+			if err != nil {
+				t.Fatalf("MonthDay(%d, %d): %v", year, doy, err)
+			}
+			got, err := DayOfYear(year, month, day)
+			if err != nil || got != doy {
+				t.Fatalf("DayOfYear(%d, %d, %d) = %d, %v, want %d", year, month, day, got, err, doy)
+			}
+		}
+	}
+}
+
+func TestCalculateSetMonthDay(t *testing.T) {
+	sp, err := New(time.Date(2021, 7, 22, 12, 0, 0, 0, time.UTC), 40.0, -105.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp.SetMonth(12)
+	sp.SetDay(1)
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if sp.GetMonth() != 12 || sp.GetDay() != 1 || sp.GetDaynum() != 335 {
+		t.Errorf("month, day, day of year = %d, %d, %d, want 12, 1, 335", sp.GetMonth(), sp.GetDay(), sp.GetDaynum())
+	}
+}
+
+func TestCalculateSetDaynum(t *testing.T) {
+	sp, err := New(time.Date(2021, 7, 22, 12, 0, 0, 0, time.UTC), 40.0, -105.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp.SetDaynum(335)
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if sp.GetMonth() != 12 || sp.GetDay() != 1 || sp.GetDaynum() != 335 {
+		t.Errorf("month, day, day of year = %d, %d, %d, want 12, 1, 335", sp.GetMonth(), sp.GetDay(), sp.GetDaynum())
+	}
+
+	/* without S_DOY the day of year follows month and day */
+	sp.SetFunction(SAll &^ LDoy)
+	sp.SetDaynum(100)
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if sp.GetDaynum() != 335 {
+		t.Errorf("day of year = %d, want 335", sp.GetDaynum())
+	}
+}
+
+func TestYearExtend(t *testing.T) {
+	dt := time.Date(1850, 6, 21, 12, 0, 0, 0, time.UTC)
+	if _, err := New(dt, 40.0, -105.0); err == nil {
+		t.Error("no error for 1850 with YearError")
+	}
+	want, err := New(dt, 40.0, -105.0, WithAlgorithm(AlgorithmSPA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp, err := New(dt, 40.0, -105.0, WithYearPolicy(YearExtend))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range sp.GetWarnings() {
+		if w == WarnYearRange {
+			t.Error("WarnYearRange with YearExtend")
+		}
+	}
+	if sp.GetZenref() != want.GetZenref() || sp.GetAzim() != want.GetAzim() {
+		t.Errorf("zenref, azim = %v, %v, want %v, %v of the SPA", sp.GetZenref(), sp.GetAzim(), want.GetZenref(), want.GetAzim())
+	}
+	if sp.GetAlgorithm() != AlgorithmSOLPOS {
+		t.Errorf("algorithm = %v, want AlgorithmSOLPOS", sp.GetAlgorithm())
+	}
+
+	/* years within the range keep the algorithm */
+	sp.SetDate(time.Date(2021, 6, 21, 12, 0, 0, 0, time.UTC))
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	solpos, err := New(sp.Getdate(), 40.0, -105.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.GetZenref() != solpos.GetZenref() {
+		t.Errorf("zenref = %v, want %v of SOLPOS", sp.GetZenref(), solpos.GetZenref())
+	}
+
+	if _, err := New(time.Date(6500, 1, 1, 0, 0, 0, 0, time.UTC), 40.0, -105.0, WithYearPolicy(YearExtend)); err == nil {
+		t.Error("no error for 6500, beyond the range of the SPA")
+	}
+}
@@ -0,0 +1,129 @@
+package solpos
+
+import (
+	"sort"
+	"time"
+)
+
+/*============================================================================
+*    Delta T
+*
+*    TT - UT1, the difference between the uniform terrestrial time of the
+*    ecliptic coordinates and the time of the rotation of the Earth (UTC,
+*    within 0.9 s), about 69 s in 2020. SOLPOS neglects it, about 0.0008
+*    degrees of ecliptic longitude.
+*       Espenak, F., Meeus, J.  2006.  Five Millennium Canon of Solar
+*            Eclipses: -1999 to +3000.  NASA/TP-2006-214141
+*----------------------------------------------------------------------------*/
+
+// DeltaTProvider provides Delta T (TT - UT1) in seconds at a time, e.g. from the bulletins of the IERS
+type DeltaTProvider interface {
+	DeltaT(t time.Time) float64
+}
+
+// DeltaTFunc is a function implementing DeltaTProvider
+type DeltaTFunc func(t time.Time) float64
+
+// DeltaT calls f(t)
+func (f DeltaTFunc) DeltaT(t time.Time) float64 {
+	return f(t)
+}
+
+// DeltaTModel is the DeltaTProvider of EstimateDeltaT
+var DeltaTModel DeltaTProvider = DeltaTFunc(func(t time.Time) float64 {
+	return EstimateDeltaT(decimalYear(t))
+})
+
+func (sp *PosData) SetDeltaT(deltaT float64) {
+	sp.DeltaT = deltaT
+}
+
+func (sp *PosData) GetDeltaT() float64 {
+	return sp.DeltaT
+}
+
+func (sp *PosData) SetDeltaTSource(provider DeltaTProvider) {
+	sp.DeltaTSource = provider
+}
+
+func (sp *PosData) GetDeltaTSource() DeltaTProvider {
+	return sp.DeltaTSource
+}
+
+// deltaTTable are observed values of Delta T in seconds at the beginning of the years 1955 - 2025, every 5 years
+var deltaTTable = []float64{31.1, 33.2, 35.7, 40.2, 45.5, 50.5, 54.3, 56.9, 60.8, 63.8, 64.7, 66.1, 67.6, 69.4, 69.1}
+
+const (
+	deltaTTableStart = 1955.0
+	deltaTTableStep  = 5.0
+	deltaTTableEnd   = deltaTTableStart + deltaTTableStep*14
+)
+
+// EstimateDeltaT returns Delta T (TT - UT1) in seconds of a decimal year (2020.5 = July 2020): observed values for
+// 1955 - 2025 (linear interpolation), the polynomials of Espenak and Meeus before and projected after, the projection
+// starting at the last observed value. The uncertainty grows to minutes for ancient dates and far future.
+func EstimateDeltaT(year float64) float64 {
+	switch {
+	case year < deltaTTableStart:
+		return espenakMeeus(year)
+	case year < deltaTTableEnd:
+		f := (year - deltaTTableStart) / deltaTTableStep
+		i := int(f)
+		return deltaTTable[i] + (f-float64(i))*(deltaTTable[i+1]-deltaTTable[i])
+	case year < 2150.0:
+		/* the offset of the polynomial from the last observed value fades out until 2150 */
+		offset := deltaTTable[len(deltaTTable)-1] - espenakMeeus(deltaTTableEnd)
+		return espenakMeeus(year) + offset*(2150.0-year)/(2150.0-deltaTTableEnd)
+	}
+	return espenakMeeus(year)
+}
+
+// espenakMeeus returns Delta T in seconds of a decimal year by the polynomials of Espenak and Meeus
+func espenakMeeus(y float64) float64 {
+	/* the polynomial of an interval starts at the year of its entry */
+	type piece struct {
+		from   float64
+		origin float64
+		scale  float64
+		coeffs []float64
+	}
+	pieces := [...]piece{
+		{-500.0, 0.0, 100.0, []float64{10583.6, -1014.41, 33.78311, -5.952053, -0.1798452, 0.022174192, 0.0090316521}},
+		{500.0, 1000.0, 100.0, []float64{1574.2, -556.01, 71.23472, 0.319781, -0.8503463, -0.005050998, 0.0083572073}},
+		{1600.0, 1600.0, 1.0, []float64{120.0, -0.9808, -0.01532, 1.0 / 7129.0}},
+		{1700.0, 1700.0, 1.0, []float64{8.83, 0.1603, -0.0059285, 0.00013336, -1.0 / 1174000.0}},
+		{1800.0, 1800.0, 1.0, []float64{13.72, -0.332447, 0.0068612, 0.0041116, -0.00037436, 0.0000121272, -0.0000001699, 0.000000000875}},
+		{1860.0, 1860.0, 1.0, []float64{7.62, 0.5737, -0.251754, 0.01680668, -0.0004473624, 1.0 / 233174.0}},
+		{1900.0, 1900.0, 1.0, []float64{-2.79, 1.494119, -0.0598939, 0.0061966, -0.000197}},
+		{1920.0, 1920.0, 1.0, []float64{21.20, 0.84493, -0.076100, 0.0020936}},
+		{1941.0, 1950.0, 1.0, []float64{29.07, 0.407, -1.0 / 233.0, 1.0 / 2547.0}},
+		{1961.0, 1975.0, 1.0, []float64{45.45, 1.067, -1.0 / 260.0, -1.0 / 718.0}},
+		{1986.0, 2000.0, 1.0, []float64{63.86, 0.3345, -0.060374, 0.0017275, 0.000651814, 0.00002373599}},
+		{2005.0, 2000.0, 1.0, []float64{62.92, 0.32217, 0.005589}},
+	}
+	/* the long-term parabola of the tidal braking, before -500 and after 2050 */
+	u := (y - 1820.0) / 100.0
+	parabola := -20.0 + 32.0*u*u
+	if y < pieces[0].from || y >= 2150.0 {
+		return parabola
+	}
+	if y >= 2050.0 {
+		return parabola - 0.5628*(2150.0-y)
+	}
+	i := sort.Search(len(pieces), func(i int) bool { return pieces[i].from > y }) - 1
+	p := pieces[i]
+	t := (y - p.origin) / p.scale
+	var dt float64
+	for k := len(p.coeffs) - 1; k >= 0; k-- {
+		dt = dt*t + p.coeffs[k]
+	}
+	return dt
+}
+
+// decimalYear returns the year of t (UTC) with the fraction of the year elapsed
+func decimalYear(t time.Time) float64 {
+	t = t.UTC()
+	start := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(t.Year()) + t.Sub(start).Seconds()/end.Sub(start).Seconds()
+}
@@ -0,0 +1,138 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+/*============================================================================
+*    Photosynthetically active radiation and daily light integral
+*
+*    About 45 % of the global irradiance of sunlight falls into the PAR band
+*    (400-700 nm), 1 J of PAR sunlight carries about 4.57 umol photons.
+*       McCree, K. J.  1972.  Test of current definitions of
+*            photosynthetically active radiation against leaf photosynthesis
+*            data.  Agricultural Meteorology 10, pp. 443-453
+*    Cloud cover reduces the clear-sky irradiance following
+*       Kasten, F., Czeplak, G.  1980.  Solar and terrestrial radiation
+*            dependent on the amount and type of cloud.  Solar Energy 24 (2),
+*            pp. 177-189
+*----------------------------------------------------------------------------*/
+
+const (
+	parFraction     = 0.45 // PAR share of the global irradiance of sunlight
+	photonsPerJoule = 4.57 // umol photons per J of PAR sunlight
+)
+
+// PPFD returns the photosynthetic photon flux density in umol/sq m/s of a global irradiance in W/sq m of sunlight
+func PPFD(ghi float64) float64 {
+	return ghi * parFraction * photonsPerJoule
+}
+
+// DLIConfig configures DailyLightIntegral
+type DLIConfig struct {
+	Start              time.Time              // First day, local to the site
+	End                time.Time              // Last day (inclusive), local to the site
+	Step               time.Duration          // Integration step, DEFAULT (0) = 10 minutes
+	LinkeTurbidity     float64                // Linke turbidity of the clear sky (see ClearSkyIneichen), DEFAULT (0) = 3
+	Altitude           float64                // Altitude of the site, meters
+	CloudCover         [12]float64            // Mean cloud cover fraction (oktas/8) from January to December, 0 = clear sky
+	Transmission       float64                // Light transmission of the greenhouse cover, DEFAULT (0) = 1 (outdoors)
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos, e.g. "press"
+}
+
+// DLIDay is the daily light integral of a single day
+type DLIDay struct {
+	Date     time.Time // Local midnight of the day
+	ClearSky float64   // Daily light integral under a clear sky, mol/sq m/day
+	DLI      float64   // Daily light integral with the configured cloud cover, mol/sq m/day
+}
+
+// DLIMonth is the mean daily light integral of a (part of a) month
+type DLIMonth struct {
+	Year     int
+	Month    time.Month
+	Days     int     // Number of days of the month in the range
+	ClearSky float64 // Mean daily light integral under a clear sky, mol/sq m/day
+	DLI      float64 // Mean daily light integral with the configured cloud cover, mol/sq m/day
+}
+
+// DailyLightIntegral estimates the daily light integral (DLI) at a site for every day from start to end and the mean
+// per month, under a clear sky (Ineichen-Perez) and with the configured monthly cloud cover (Kasten-Czeplak), times the
+// transmission of a greenhouse cover. Both are estimates for planning, not a replacement for measurements.
+func DailyLightIntegral(site Site, config DLIConfig) ([]DLIDay, []DLIMonth, error) {
+	step := config.Step
+	if step == 0 {
+		step = 10 * time.Minute
+	}
+	if step < 0 {
+		return nil, nil, errors.New("Please fix step, must be positive")
+	}
+	if config.End.Before(config.Start) {
+		return nil, nil, errors.New("Please fix end, must not be before start")
+	}
+	for _, n := range config.CloudCover {
+		if n < 0.0 || n > 1.0 {
+			return nil, nil, errors.New("Please fix cloud cover [0-1]")
+		}
+	}
+	if config.Transmission < 0.0 || config.Transmission > 1.0 {
+		return nil, nil, errors.New("Please fix transmission [0-1]")
+	}
+	transmission := config.Transmission
+	if transmission == 0.0 {
+		transmission = 1.0
+	}
+	linke := config.LinkeTurbidity
+	if linke == 0.0 {
+		linke = 3.0
+	}
+	loc := site.location()
+	y, m, d := config.Start.In(loc).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	y, m, d = config.End.In(loc).Date()
+	last := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	sp, err := NewSolpos(day, site.Latitude, site.Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var days []DLIDay
+	var months []DLIMonth
+	/* mol per umol/sq m/s over one step */
+	mol := step.Seconds() / 1e6
+	for ; !day.After(last); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+		next := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+		var clear float64
+		/* midpoint rule, every step is represented by its middle */
+		for dt := day.Add(step / 2); dt.Before(next); dt = dt.Add(step) {
+			sp.SetDate(dt)
+			err = sp.Calculate()
+			if err != nil {
+				return nil, nil, err
+			}
+			r := sp.GetResult()
+			if r.Zenref >= 90.0 {
+				continue
+			}
+			clear += PPFD(ClearSkyIneichen(r, linke, config.Altitude).GHI) * mol
+		}
+		clear *= transmission
+		cloud := 1.0 - 0.75*math.Pow(config.CloudCover[day.Month()-1], 3.4)
+		days = append(days, DLIDay{Date: day, ClearSky: clear, DLI: clear * cloud})
+
+		if len(months) == 0 || months[len(months)-1].Month != day.Month() || months[len(months)-1].Year != day.Year() {
+			months = append(months, DLIMonth{Year: day.Year(), Month: day.Month()})
+		}
+		month := &months[len(months)-1]
+		month.Days++
+		month.ClearSky += clear
+		month.DLI += clear * cloud
+	}
+	for i := range months {
+		months[i].ClearSky /= float64(months[i].Days)
+		months[i].DLI /= float64(months[i].Days)
+	}
+	return days, months, nil
+}
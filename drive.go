@@ -0,0 +1,52 @@
+package solpos
+
+import "math"
+
+// TrackerDrive describes the stepper motor drive of a tracker axis
+type TrackerDrive struct {
+	GearRatio       float64 // Motor revolutions per revolution of the tracker axis
+	StepsPerRev     int     // Motor (micro)steps per motor revolution
+	InvertDirection bool    // Positive motor steps turn the axis toward negative angles
+}
+
+// DriveMove is the incremental motion to reach a target drive angle
+type DriveMove struct {
+	Steps     int64 // Number of steps to issue, never negative
+	Direction int   // Motor direction, +1 or -1, 0 if no motion is needed
+	Target    int64 // Step position (counted in positive motor direction) after the move
+}
+
+// StepsPerDegree returns the number of motor steps per degree of axis rotation
+func (d TrackerDrive) StepsPerDegree() float64 {
+	return d.GearRatio * float64(d.StepsPerRev) / 360.0
+}
+
+// Position returns the step position (counted in positive motor direction from drive angle 0) of a drive angle in degrees
+func (d TrackerDrive) Position(angle float64) int64 {
+	position := int64(math.Round(angle * d.StepsPerDegree()))
+	if d.InvertDirection {
+		return -position
+	}
+	return position
+}
+
+// Angle returns the drive angle in degrees of a step position
+func (d TrackerDrive) Angle(position int64) float64 {
+	if d.InvertDirection {
+		position = -position
+	}
+	return float64(position) / d.StepsPerDegree()
+}
+
+// Move returns the steps and direction needed to go from the current step position to the command of the tracker state
+func (d TrackerDrive) Move(current int64, s TrackerState) DriveMove {
+	m := DriveMove{Target: d.Position(s.Command)}
+	delta := m.Target - current
+	switch {
+	case delta > 0:
+		m.Steps, m.Direction = delta, 1
+	case delta < 0:
+		m.Steps, m.Direction = -delta, -1
+	}
+	return m
+}
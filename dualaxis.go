@@ -0,0 +1,60 @@
+package solpos
+
+import "math"
+
+// DualAxis describes a dual-axis tracker pointing the panel at the sun, see SetDualAxis. Tilt and azimuth of the
+// panel are limited to the ranges of the mount, outside of them the panel stops at the nearest limit.
+type DualAxis struct {
+	MinTilt     float64 // Minimum tilt of the panel from horizontal, degrees
+	MaxTilt     float64 // Maximum tilt of the panel from horizontal, degrees, DEFAULT (0) = 90
+	MinAzimuth  float64 // Start of the azimuth range of the panel (direction it faces), N=0, E=90, S=180, W=270, a range across north starts above its end
+	MaxAzimuth  float64 // End of the azimuth range of the panel, DEFAULT (MinAzimuth and MaxAzimuth 0) = unlimited
+	StowTilt    float64 // Tilt of the panel at night and while stowed, degrees
+	StowAzimuth float64 // Azimuth of the panel at night and while stowed, DEFAULT (0) = 180
+	Stow        bool    // Stow command, e.g. because of wind, the panel goes to the stow position
+}
+
+// orientation returns the tilt and aspect of the panel for a refracted sun position
+func (d *DualAxis) orientation(zenref float64, azim float64) (tilt float64, aspect float64) {
+	if d.Stow || zenref > 90.0 {
+		aspect = d.StowAzimuth
+		if aspect == 0.0 {
+			aspect = 180.0
+		}
+		return d.StowTilt, aspect
+	}
+	maxTilt := d.MaxTilt
+	if maxTilt == 0.0 {
+		maxTilt = 90.0
+	}
+	return math.Max(d.MinTilt, math.Min(maxTilt, zenref)), d.clampAzimuth(azim)
+}
+
+// clampAzimuth limits an azimuth to the range of the mount, an azimuth outside goes to the angularly nearest limit
+func (d *DualAxis) clampAzimuth(azim float64) float64 {
+	if d.MinAzimuth == 0.0 && d.MaxAzimuth == 0.0 {
+		return azim
+	}
+	inside := d.MinAzimuth <= azim && azim <= d.MaxAzimuth
+	if d.MinAzimuth > d.MaxAzimuth {
+		inside = azim >= d.MinAzimuth || azim <= d.MaxAzimuth
+	}
+	if inside {
+		return azim
+	}
+	distance := func(a float64, b float64) float64 {
+		return math.Abs(math.Mod(a-b+540.0, 360.0) - 180.0)
+	}
+	if distance(azim, d.MinAzimuth) <= distance(azim, d.MaxAzimuth) {
+		return d.MinAzimuth
+	}
+	return d.MaxAzimuth
+}
+
+func (sp *PosData) SetDualAxis(tracker *DualAxis) {
+	sp.DualAxis = tracker
+}
+
+func (sp *PosData) GetDualAxis() *DualAxis {
+	return sp.DualAxis
+}
@@ -0,0 +1,74 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNoCrossing is returned (wrapped) if the sun does not pass the requested elevation (or azimuth) on the requested day
+var ErrNoCrossing = errors.New("the sun does not cross the requested elevation or azimuth")
+
+// elevationSearchWindow is the time around the estimate from the declination of the day in which the crossing is bisected
+const elevationSearchWindow = 30 * time.Minute
+
+func (sp *PosData) TimeAtElevation(date time.Time, elevation float64, rising bool) (time.Time, error) {
+	if math.IsNaN(elevation) || elevation < -90.0 || elevation > 90.0 {
+		return time.Time{}, errors.New("Please fix elevation [-90 - 90]")
+	}
+	err := sp.calculateEvents(date, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	/* estimate the crossing with the declination of the day, like sunrise and sunset */
+	ha := sp.crossingHourAngle(sp.unrefracted(elevation))
+	switch {
+	case ha <= 0.0:
+		return time.Time{}, wrap(ErrNoCrossing, "the sun stays below")
+	case ha >= 180.0:
+		return time.Time{}, wrap(ErrNoCrossing, "the sun stays above")
+	}
+	if rising {
+		ha = -ha
+	}
+	estimate := sp.eventTime(720.0 + 4.0*ha - sp.Tstfix)
+
+	/* refine it with the full calculation, the declination changes during the day */
+	lo, hi := estimate.Add(-elevationSearchWindow), estimate.Add(elevationSearchWindow)
+	elo, err := sp.apparentElevationAt(lo)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ehi, err := sp.apparentElevationAt(hi)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := estimate
+	if (elo >= elevation) != (ehi >= elevation) {
+		t, err = bisect(lo, hi, func(mid time.Time) (bool, error) {
+			e, err := sp.apparentElevationAt(mid)
+			return (e >= elevation) == rising, err
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	/* leave the calculation at the crossing */
+	sp.SetDate(t)
+	return t, sp.Calculate()
+}
+
+// apparentElevationAt calculates dt and returns the refracted elevation of the sun, degrees. Unlike Elevref it is not
+// limited to -9 degrees at night.
+func (sp *PosData) apparentElevationAt(dt time.Time) (float64, error) {
+	sp.SetDate(dt)
+	err := sp.Calculate()
+	if err != nil {
+		return 0, err
+	}
+	sd, cd := math.Sincos(raddeg * sp.Declin)
+	sl, cl := math.Sincos(raddeg * sp.Latitude)
+	se := math.Max(-1.0, math.Min(1.0, sd*sl+cd*cl*math.Cos(raddeg*sp.Hrang)))
+	e := degrad * math.Asin(se)
+	return e + refraction(e, sp.Press, sp.Temp), nil
+}
@@ -0,0 +1,33 @@
+package solpos
+
+import (
+	"time"
+)
+
+// EquationOfTimeDay is the equation of time and the declination of one day
+type EquationOfTimeDay struct {
+	Date        time.Time // Instant of the calculation, 12:00 UTC of the day
+	Daynum      int       // Day number (day of year; Feb 1 = 32)
+	Eqntim      float64   // Equation of time (TST - LMT), minutes
+	Declination float64   // Declination of the sun, degrees north
+}
+
+// EquationOfTimeYear returns the equation of time and the declination at 12:00 UTC of every day of a year, e.g. for
+// sundial correction tables and EoT plots. Both hardly change during a day, they do not depend on the location.
+func EquationOfTimeYear(year int) ([]EquationOfTimeDay, error) {
+	start := time.Date(year, time.January, 1, 12, 0, 0, 0, time.UTC)
+	sp, err := NewSolpos(start, 0.0, 0.0, map[string]interface{}{"function": LGeom | LTst})
+	if err != nil {
+		return nil, err
+	}
+	var days []EquationOfTimeDay
+	for dt := start; dt.Year() == year; dt = dt.AddDate(0, 0, 1) {
+		sp.SetDate(dt)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, EquationOfTimeDay{Date: dt, Daynum: sp.GetDaynum(), Eqntim: sp.GetEqntim(), Declination: sp.GetDeclin()})
+	}
+	return days, nil
+}
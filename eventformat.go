@@ -0,0 +1,82 @@
+package solpos
+
+import (
+	"errors"
+	"time"
+)
+
+// EventFormat selects the representation of solar event times (sunrise, sunset)
+type EventFormat int
+
+const (
+	EventTime    EventFormat = iota // time.Time in the location of the calculation
+	EventRFC3339                    // string formatted as RFC 3339, with the offset of the calculation
+	EventUnix                       // int64 seconds since 1970-01-01 UTC
+	EventMinutes                    // float64 minutes from local midnight, like Sretr and Ssetr of NREL SOLPOS
+)
+
+func (f EventFormat) String() string {
+	switch f {
+	case EventRFC3339:
+		return "rfc3339"
+	case EventUnix:
+		return "unix"
+	case EventMinutes:
+		return "minutes"
+	default:
+		return "time"
+	}
+}
+
+// ParseEventFormat returns the event format of a name as returned by String, e.g. of a query parameter or flag
+func ParseEventFormat(name string) (EventFormat, error) {
+	for f := EventTime; f <= EventMinutes; f++ {
+		if f.String() == name {
+			return f, nil
+		}
+	}
+	return EventTime, errors.New("Please fix event format " + name + ", expected time, rfc3339, unix or minutes")
+}
+
+// FormatEventTime returns an event time in the given format, minutes from midnight of the day of t for EventMinutes
+func FormatEventTime(t time.Time, format EventFormat) interface{} {
+	switch format {
+	case EventRFC3339:
+		return t.Format(time.RFC3339)
+	case EventUnix:
+		return t.Unix()
+	case EventMinutes:
+		y, m, d := t.Date()
+		return t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location())).Minutes()
+	default:
+		return t
+	}
+}
+
+func (sp *PosData) SetEventFormat(format EventFormat) {
+	sp.EventFormat = format
+}
+
+func (sp *PosData) GetEventFormat() EventFormat {
+	return sp.EventFormat
+}
+
+func (sp *PosData) GetSunriseAs() interface{} {
+	return sp.formatEvent(sp.Sretr, sp.GetSunrise)
+}
+
+func (sp *PosData) GetSunsetAs() interface{} {
+	return sp.formatEvent(sp.Ssetr, sp.GetSunset)
+}
+
+// formatEvent formats an event of minutes from midnight in the event format. Without the event (24 hours of sun up
+// or down, the minutes are the flag value 2999 or -2999) it is nil, minutes keep the flag value of NREL SOLPOS.
+func (sp *PosData) formatEvent(minutes float64, event func() time.Time) interface{} {
+	if sp.EventFormat == EventMinutes {
+		return minutes
+	}
+	if minutes < -1440.0 || minutes > 2880.0 {
+		return nil
+	}
+	return FormatEventTime(event(), sp.EventFormat)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maltegrosse/go-solpos"
+)
+
+// Speed and accuracy of the position algorithms within 2010-2110, the errors against the SPA while the sun is up (SOLPOS
+// limits the elevation to -9 degrees, the azimuth below is not comparable)
+
+type site struct {
+	latitude, longitude float64
+}
+
+var sites = []site{{52.52, 13.40}, {39.74, -105.18}, {-33.87, 151.21}, {1.35, 103.82}, {64.15, -21.94}}
+
+func main() {
+	algorithms := []struct {
+		name      string
+		algorithm solpos.Algorithm
+	}{
+		{"SOLPOS (Michalsky)", solpos.AlgorithmSOLPOS},
+		{"Grena 1", solpos.AlgorithmGrena1},
+		{"Grena 2", solpos.AlgorithmGrena2},
+		{"Grena 3", solpos.AlgorithmGrena3},
+		{"Grena 4", solpos.AlgorithmGrena4},
+		{"Grena 5", solpos.AlgorithmGrena5},
+		{"PSA 2001", solpos.AlgorithmPSA2001},
+		{"PSA 2020", solpos.AlgorithmPSA2020},
+		{"SPA", solpos.AlgorithmSPA},
+	}
+	start := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2110, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := 97*time.Hour + 13*time.Minute
+
+	/* the SPA as reference */
+	var reference [][2]float64
+	for dt := start; dt.Before(end); dt = dt.Add(step) {
+		for _, s := range sites {
+			zenith, azimuth, _, err := position(solpos.AlgorithmSPA, dt, s)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			reference = append(reference, [2]float64{zenith, azimuth})
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "algorithm\tns/calculation\tmax error (deg)\tmean error (deg)\t")
+	for _, a := range algorithms {
+		var max, sum, elapsed float64
+		i, n := 0, 0
+		for dt := start; dt.Before(end); dt = dt.Add(step) {
+			for _, s := range sites {
+				zenith, azimuth, ns, err := position(a.algorithm, dt, s)
+				if err != nil {
+					fmt.Println(err)
+					return
+				}
+				elapsed += ns
+				if reference[i][0] < 90.0 {
+					e := separation(zenith, azimuth, reference[i][0], reference[i][1])
+					max = math.Max(max, e)
+					sum += e
+					n++
+				}
+				i++
+			}
+		}
+		fmt.Fprintf(w, "%s\t%.0f\t%.5f\t%.5f\t\n", a.name, elapsed/float64(i), max, sum/float64(n))
+	}
+	w.Flush()
+}
+
+// position returns the unrefracted zenith and azimuth of the sun and the average duration of a calculation in ns
+func position(algorithm solpos.Algorithm, dt time.Time, s site) (float64, float64, float64, error) {
+	const repeat = 20
+	sp, err := solpos.New(dt, s.latitude, s.longitude, solpos.WithAlgorithm(algorithm), solpos.WithYearPolicy(solpos.YearWarn),
+		solpos.WithFunction(solpos.SSolazm))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	t := time.Now()
+	for i := 0; i < repeat; i++ {
+		err = sp.Calculate()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	ns := float64(time.Since(t).Nanoseconds()) / repeat
+	return sp.GetZenetr(), sp.GetAzim(), ns, nil
+}
+
+// separation returns the angle between two positions of the sun, degrees
+func separation(zenith1 float64, azimuth1 float64, zenith2 float64, azimuth2 float64) float64 {
+	const rad = math.Pi / 180.0
+	c := math.Cos(zenith1*rad)*math.Cos(zenith2*rad) + math.Sin(zenith1*rad)*math.Sin(zenith2*rad)*math.Cos((azimuth1-azimuth2)*rad)
+	return math.Acos(math.Max(-1.0, math.Min(1.0, c))) / rad
+}
@@ -0,0 +1,84 @@
+package solpos
+
+import (
+	"errors"
+	"time"
+)
+
+// FleetSite is a site of a fleet, e.g. a PV plant, with the orientation of its surface
+type FleetSite struct {
+	Site
+	Tilt   float64 // Tilt of the surface from horizontal, degrees
+	Aspect float64 // Direction the surface faces, N=0, E=90, S=180, W=270
+}
+
+// sharedGeometry holds the outputs of ecliptic for one instant, they are the same for all sites
+type sharedGeometry struct {
+	julday, deltaT                                               float64
+	ectime, mnlong, mnanom, eclong, ecobli, declin, rascen, gmst float64
+	precise                                                      bool
+}
+
+func (g *sharedGeometry) store(sp *PosData) {
+	*g = sharedGeometry{sp.Julday, sp.DeltaT, sp.Ectime, sp.Mnlong, sp.Mnanom, sp.Eclong, sp.Ecobli, sp.Declin, sp.Rascen, sp.Gmst, sp.Precise}
+}
+
+func (g *sharedGeometry) restore(sp *PosData) {
+	sp.Ectime, sp.Mnlong, sp.Mnanom, sp.Eclong, sp.Ecobli, sp.Declin, sp.Rascen, sp.Gmst = g.ectime, g.mnlong, g.mnanom, g.eclong, g.ecobli, g.declin, g.rascen, g.gmst
+}
+
+// FleetPositions calculates all sites of a fleet at one instant and returns their results in the order of sites, in the
+// local time of every site. The optional parameters apply to all sites, except tilt and aspect, which are those of the site.
+// The ecliptic geometry of the instant is calculated once and shared by the sites.
+func FleetPositions(sites []FleetSite, dt time.Time, options *Options) ([]Result, error) {
+	series, err := FleetSeries(sites, dt, dt, time.Second, options)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(sites))
+	for i := range series {
+		results[i] = series[i][0]
+	}
+	return results, nil
+}
+
+// FleetSeries calculates all sites of a fleet from start to end (inclusive) at a fixed step like FleetPositions, the results
+// are indexed by site and step
+func FleetSeries(sites []FleetSite, start time.Time, end time.Time, step time.Duration, options *Options) ([][]Result, error) {
+	if step <= 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	if end.Before(start) {
+		return nil, errors.New("Please fix end, must not be before start")
+	}
+	o := DefaultOptions()
+	if options != nil {
+		o = *options
+	}
+	shared := &sharedGeometry{}
+	calculators := make([]*PosData, len(sites))
+	results := make([][]Result, len(sites))
+	for i, site := range sites {
+		aspect := Degrees(site.Aspect)
+		o.Tilt, o.Aspect = Degrees(site.Tilt), &aspect
+		sp, err := NewSolposWithOptions(start.In(site.location()), site.Latitude, site.Longitude, o)
+		if err != nil {
+			return nil, wrap(err, "site "+site.Name)
+		}
+		calculators[i] = sp.(*PosData)
+		calculators[i].shared = shared
+		results[i] = make([]Result, 0, end.Sub(start)/step+1)
+	}
+	/* all sites at one instant before the next, so the shared geometry is reused */
+	for dt := start; !dt.After(end); dt = dt.Add(step) {
+		for i, sp := range calculators {
+			sp.SetDate(dt.In(sites[i].location()))
+			err := sp.Calculate()
+			if err != nil {
+				return nil, wrap(err, "site "+sites[i].Name)
+			}
+			results[i] = append(results[i], sp.GetResult())
+		}
+	}
+	return results, nil
+}
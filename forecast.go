@@ -0,0 +1,73 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// PersistenceConfig configures PersistenceForecast
+type PersistenceConfig struct {
+	Horizon  time.Duration       // Forecast horizon from the time of the last measurement
+	Step     time.Duration       // Interval of the forecast values
+	Window   time.Duration       // The persisted clear-sky index is the mean over this time before the last measurement, DEFAULT (0) = last sample only
+	ClearSky ClearSkyIndexConfig // Clear-sky model and MinElevation of the clear-sky index
+}
+
+// ForecastSample is a value of an irradiance forecast
+type ForecastSample struct {
+	Time     time.Time // Time the forecast is valid for
+	GHI      float64   // Forecast global horizontal irradiance, W/sq m
+	ClearSky float64   // Modeled clear-sky GHI, W/sq m
+	Index    float64   // Persisted clear-sky index
+}
+
+// PersistenceForecast returns the smart persistence forecast of a site, the reference model for forecast benchmarking:
+// the clear-sky index of the last measurements (times not decreasing) is persisted over the horizon and multiplied by
+// the future clear-sky GHI. Without a defined index in the window (night, low sun) a clear sky (index 1) is assumed.
+func PersistenceForecast(site Site, measured []IrradianceSample, config PersistenceConfig) ([]ForecastSample, error) {
+	if config.Horizon <= 0 || config.Step <= 0 {
+		return nil, errors.New("Please fix horizon and step, must be positive")
+	}
+	if config.Window < 0 {
+		return nil, errors.New("Please fix window, must not be negative")
+	}
+	if len(measured) == 0 {
+		return nil, errors.New("Please fix measured, at least one sample is required")
+	}
+	last := measured[len(measured)-1].Time
+	first := len(measured) - 1
+	for first > 0 && !measured[first-1].Time.Before(last.Add(-config.Window)) {
+		first--
+	}
+	index, err := ComputeClearSkyIndex(site, measured[first:], config.ClearSky)
+	if err != nil {
+		return nil, err
+	}
+	kc, n := 0.0, 0
+	for _, k := range index {
+		if !math.IsNaN(k.Index) && !math.IsNaN(k.GHI) {
+			kc += k.Index
+			n++
+		}
+	}
+	if n == 0 {
+		kc = 1.0
+	} else {
+		kc /= float64(n)
+	}
+
+	var future []IrradianceSample
+	for t := last.Add(config.Step); !t.After(last.Add(config.Horizon)); t = t.Add(config.Step) {
+		future = append(future, IrradianceSample{Time: t})
+	}
+	clear, err := ComputeClearSkyIndex(site, future, config.ClearSky)
+	if err != nil {
+		return nil, err
+	}
+	forecast := make([]ForecastSample, len(clear))
+	for i, c := range clear {
+		forecast[i] = ForecastSample{Time: c.Time, GHI: kc * c.ClearSky, ClearSky: c.ClearSky, Index: kc}
+	}
+	return forecast, nil
+}
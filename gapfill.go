@@ -0,0 +1,120 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// FillQuality tells where a value of a gap-filled series comes from
+type FillQuality int
+
+const (
+	QualityMeasured     FillQuality = iota // measured value
+	QualityFilled                          // filled, clear-sky curve scaled by the clear-sky index of the neighbors
+	QualityInterpolated                    // filled, interpolated linearly between the neighbors (clear-sky index undefined at low sun)
+	QualityUnfilled                        // missing, the gap is longer than MaxGap or at the edge of the series (NaN)
+)
+
+func (q FillQuality) String() string {
+	switch q {
+	case QualityFilled:
+		return "filled"
+	case QualityInterpolated:
+		return "interpolated"
+	case QualityUnfilled:
+		return "unfilled"
+	default:
+		return "measured"
+	}
+}
+
+// GapFillConfig configures FillGaps
+type GapFillConfig struct {
+	Step     time.Duration       // Nominal interval of the samples, missing samples are inserted where it is exceeded
+	MaxGap   time.Duration       // Longest gap filled, DEFAULT (0) = 1 hour
+	ClearSky ClearSkyIndexConfig // Clear-sky model and MinElevation of the clear-sky index
+}
+
+// FilledSample is a sample of a gap-filled series
+type FilledSample struct {
+	Time    time.Time   // Time of the sample
+	Value   float64     // Measured or filled GHI, W/sq m, NaN if unfilled
+	Quality FillQuality // Where Value comes from
+}
+
+// FillGaps reconstructs short missing stretches of a measured global horizontal irradiance series of a site: samples
+// with a NaN value and samples missing from the nominal step are filled with the clear-sky curve scaled by the clear-sky
+// index interpolated between the measured samples before and after the gap. Gaps longer than MaxGap stay unfilled.
+func FillGaps(site Site, series []IrradianceSample, config GapFillConfig) ([]FilledSample, error) {
+	if config.Step <= 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	maxGap := config.MaxGap
+	if maxGap == 0 {
+		maxGap = time.Hour
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+	/* the regular series, with the missing samples as NaN */
+	var regular []IrradianceSample
+	for i, s := range series {
+		if i > 0 {
+			if !s.Time.After(series[i-1].Time) {
+				return nil, errors.New("Please fix series, times must increase")
+			}
+			/* tolerate jitter of half a step */
+			for t := series[i-1].Time.Add(config.Step); s.Time.Sub(t) > config.Step/2; t = t.Add(config.Step) {
+				regular = append(regular, IrradianceSample{Time: t, Value: math.NaN()})
+			}
+		}
+		regular = append(regular, s)
+	}
+	/* NaN measurements give a NaN index like night, the clear-sky value is what is needed */
+	index, err := ComputeClearSkyIndex(site, regular, config.ClearSky)
+	if err != nil {
+		return nil, err
+	}
+
+	filled := make([]FilledSample, len(regular))
+	for i := 0; i < len(regular); {
+		if !math.IsNaN(regular[i].Value) {
+			filled[i] = FilledSample{Time: regular[i].Time, Value: regular[i].Value, Quality: QualityMeasured}
+			i++
+			continue
+		}
+		/* gap from i to j (exclusive) */
+		j := i
+		for j < len(regular) && math.IsNaN(regular[j].Value) {
+			j++
+		}
+		edge := i == 0 || j == len(regular)
+		long := !edge && regular[j].Time.Sub(regular[i-1].Time) > maxGap+config.Step
+		for k := i; k < j; k++ {
+			f := FilledSample{Time: regular[k].Time, Value: math.NaN(), Quality: QualityUnfilled}
+			if !edge && !long {
+				f.Value, f.Quality = fill(index, i-1, j, k)
+			}
+			filled[k] = f
+		}
+		i = j
+	}
+	return filled, nil
+}
+
+// fill returns the value of sample k of the gap between the measured samples before and after
+func fill(index []ClearSkyIndexSample, before int, after int, k int) (float64, FillQuality) {
+	b, a := index[before], index[after]
+	w := float64(index[k].Time.Sub(b.Time)) / float64(a.Time.Sub(b.Time))
+	switch {
+	case !math.IsNaN(b.Index) && !math.IsNaN(a.Index):
+		return (b.Index + w*(a.Index-b.Index)) * index[k].ClearSky, QualityFilled
+	case !math.IsNaN(b.Index):
+		return b.Index * index[k].ClearSky, QualityFilled
+	case !math.IsNaN(a.Index):
+		return a.Index * index[k].ClearSky, QualityFilled
+	default:
+		return b.GHI + w*(a.GHI-b.GHI), QualityInterpolated
+	}
+}
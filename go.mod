@@ -1,5 +1,3 @@
 module github.com/maltegrosse/go-solpos
 
 go 1.13
-
-require github.com/pkg/errors v0.9.1
@@ -0,0 +1,60 @@
+package solpos
+
+import (
+	"errors"
+	"time"
+)
+
+// ElevationBand is a range of refracted solar elevations, e.g. the light of the golden and the blue hour
+type ElevationBand struct {
+	Lower float64 // Lower refracted elevation, degrees
+	Upper float64 // Upper refracted elevation, degrees
+}
+
+// Common photography bands, the bounds vary between sources
+var (
+	GoldenHour = ElevationBand{Lower: -4.0, Upper: 6.0}
+	BlueHour   = ElevationBand{Lower: -6.0, Upper: -4.0}
+)
+
+// ElevationWindow is a time span with the sun within an ElevationBand
+type ElevationWindow struct {
+	Start   time.Time
+	End     time.Time
+	Morning bool // true for the window before solar noon (or the one spanning noon), false after
+}
+
+func (sp *PosData) GetElevationWindows(band ElevationBand) ([]ElevationWindow, error) {
+	if band.Lower >= band.Upper {
+		return nil, errors.New("Please fix the band, lower must be below upper")
+	}
+	err := sp.Computed(LGeom | LTst)
+	if err != nil {
+		return nil, err
+	}
+	/* the sun is within the band while the absolute hour angle is between those of the upper and the lower bound */
+	upper := sp.crossingHourAngle(sp.unrefracted(band.Upper))
+	lower := sp.crossingHourAngle(sp.unrefracted(band.Lower))
+	if upper >= lower {
+		return nil, nil
+	}
+	at := func(ha float64) time.Time {
+		return sp.eventTime(720.0 + 4.0*ha - sp.Tstfix)
+	}
+	if upper == 0.0 {
+		/* the sun stays below the upper bound, one window around noon */
+		return []ElevationWindow{{Start: at(-lower), End: at(lower), Morning: true}}, nil
+	}
+	return []ElevationWindow{{Start: at(-lower), End: at(-upper), Morning: true}, {Start: at(upper), End: at(lower)}}, nil
+}
+
+// unrefracted returns the unrefracted elevation at which the refracted elevation is elevref with the pressure and
+// temperature of the calculation, the inverse of refrac
+func (sp *PosData) unrefracted(elevref float64) float64 {
+	/* the refraction changes slowly with the elevation, a fixed point iteration converges in a few steps */
+	e := elevref
+	for i := 0; i < 10; i++ {
+		e = elevref - refraction(e, sp.Press, sp.Temp)
+	}
+	return e
+}
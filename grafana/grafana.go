@@ -0,0 +1,247 @@
+// Package grafana serves sun position and clear-sky series of configured sites to Grafana.
+//
+// The handler implements the conventions of the Simple JSON (JSON API) datasource: GET / for the connection test,
+// POST /search (and /metrics) listing the targets and POST /query returning time series. The targets are
+// "<site>:elevation" (refracted, degrees), "<site>:azimuth" (degrees) and "<site>:clearsky_ghi" (Haurwitz, W/sq m).
+// For the Infinity datasource GET /series?site=<site>&from=<RFC3339>&to=<RFC3339>&step=<duration> returns a JSON
+// array of rows with all three values. Values which are not finite, e.g. outputs not calculated in strict mode, are null.
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/maltegrosse/go-solpos"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxPoints limits the points of a single series, to keep a mistyped range from computing for minutes
+const maxPoints = 100000
+
+// defaultPoints are the points of a query without interval and maxDataPoints
+const defaultPoints = 1000
+
+// values are the target suffixes, in the order of the search response
+var values = []string{"elevation", "azimuth", "clearsky_ghi"}
+
+// Handler is an http.Handler serving the series of its sites
+type Handler struct {
+	sites              map[string]solpos.Site
+	names              []string
+	optionalParameters map[string]interface{}
+}
+
+// NewHandler creates new instance of Handler for the given sites, which are identified by their name.
+// The optional parameters are the same as for solpos.NewSolpos.
+func NewHandler(sites []solpos.Site, optionalParameters map[string]interface{}) (*Handler, error) {
+	h := &Handler{sites: make(map[string]solpos.Site), optionalParameters: optionalParameters}
+	for _, s := range sites {
+		if s.Name == "" || strings.Contains(s.Name, ":") {
+			return nil, errors.New("Please fix site name, must not be empty or contain ':'")
+		}
+		if _, ok := h.sites[s.Name]; ok {
+			return nil, errors.New("Please fix site name, " + s.Name + " is not unique")
+		}
+		h.sites[s.Name] = s
+		h.names = append(h.names, s.Name)
+	}
+	sort.Strings(h.names)
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "":
+		w.WriteHeader(http.StatusOK)
+	case "/search", "/metrics":
+		h.search(w)
+	case "/query":
+		h.query(w, r)
+	case "/series":
+		h.series(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) search(w http.ResponseWriter) {
+	targets := make([]string, 0, len(h.names)*len(values))
+	for _, name := range h.names {
+		for _, v := range values {
+			targets = append(targets, name+":"+v)
+		}
+	}
+	writeJSON(w, targets)
+}
+
+type queryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	IntervalMs    int64 `json:"intervalMs"`
+	MaxDataPoints int   `json:"maxDataPoints"`
+	Targets       []struct {
+		Target string `json:"target"`
+	} `json:"targets"`
+}
+
+type timeSeries struct {
+	Target     string      `json:"target"`
+	Datapoints [][2]number `json:"datapoints"` // value, unix milliseconds
+}
+
+// number is a value of a response, null if it is not finite (e.g. NaN of an output not calculated in strict mode)
+type number float64
+
+func (n number) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(n)) || math.IsInf(float64(n), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(n))
+}
+
+func (h *Handler) query(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var q queryRequest
+	err := json.NewDecoder(r.Body).Decode(&q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	step := time.Duration(q.IntervalMs) * time.Millisecond
+	if step <= 0 && q.MaxDataPoints <= 0 {
+		/* neither interval nor maxDataPoints, e.g. from clients other than Grafana */
+		step = q.Range.To.Sub(q.Range.From) / defaultPoints
+		if step < time.Second {
+			step = time.Second
+		}
+	}
+	if q.MaxDataPoints > 0 {
+		/* never more points than the panel can show */
+		if minStep := q.Range.To.Sub(q.Range.From) / time.Duration(q.MaxDataPoints); step < minStep {
+			step = minStep
+		}
+	}
+	response := make([]timeSeries, 0, len(q.Targets))
+	for _, t := range q.Targets {
+		i := strings.LastIndex(t.Target, ":")
+		if i < 0 {
+			http.Error(w, "unknown target "+t.Target, http.StatusBadRequest)
+			return
+		}
+		value := valueFunc(t.Target[i+1:])
+		site, ok := h.sites[t.Target[:i]]
+		if !ok || value == nil {
+			http.Error(w, "unknown target "+t.Target, http.StatusBadRequest)
+			return
+		}
+		ts := timeSeries{Target: t.Target, Datapoints: [][2]number{}}
+		err = h.compute(site, q.Range.From, q.Range.To, step, func(r solpos.Result) {
+			ts.Datapoints = append(ts.Datapoints, [2]number{number(value(r)), number(r.Time.UnixNano() / int64(time.Millisecond))})
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response = append(response, ts)
+	}
+	writeJSON(w, response)
+}
+
+type row struct {
+	Time        time.Time `json:"time"`
+	Elevation   number    `json:"elevation"`
+	Azimuth     number    `json:"azimuth"`
+	ClearSkyGHI number    `json:"clearsky_ghi"`
+}
+
+func (h *Handler) series(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	site, ok := h.sites[q.Get("site")]
+	if !ok {
+		http.Error(w, "unknown site "+q.Get("site"), http.StatusBadRequest)
+		return
+	}
+	from, err := time.Parse(time.RFC3339, q.Get("from"))
+	if err != nil {
+		http.Error(w, "Please fix from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := time.Parse(time.RFC3339, q.Get("to"))
+	if err != nil {
+		http.Error(w, "Please fix to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	step := time.Hour
+	if q.Get("step") != "" {
+		step, err = time.ParseDuration(q.Get("step"))
+		if err != nil {
+			http.Error(w, "Please fix step: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	rows := []row{}
+	err = h.compute(site, from, to, step, func(r solpos.Result) {
+		rows = append(rows, row{r.Time, number(r.Elevref), number(r.Azim), number(solpos.ClearSkyHaurwitz(r))})
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, rows)
+}
+
+// compute calls fn for the results of the site from from to to (inclusive), in the site's time zone
+func (h *Handler) compute(site solpos.Site, from time.Time, to time.Time, step time.Duration, fn func(r solpos.Result)) error {
+	if step <= 0 {
+		return errors.New("Please fix step, must be positive")
+	}
+	if to.Before(from) {
+		return errors.New("Please fix range, to must not be before from")
+	}
+	if to.Sub(from)/step >= maxPoints {
+		return errors.New("Please fix range or step, too many points")
+	}
+	loc := site.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	sp, err := solpos.NewSolpos(from.In(loc), site.Latitude, site.Longitude, h.optionalParameters)
+	if err != nil {
+		return err
+	}
+	for dt := from; !dt.After(to); dt = dt.Add(step) {
+		sp.SetDate(dt.In(loc))
+		err = sp.Calculate()
+		if err != nil {
+			return err
+		}
+		fn(sp.GetResult())
+	}
+	return nil
+}
+
+// valueFunc returns the function extracting a target value from a result, nil for unknown values
+func valueFunc(value string) func(r solpos.Result) float64 {
+	switch value {
+	case "elevation":
+		return func(r solpos.Result) float64 { return r.Elevref }
+	case "azimuth":
+		return func(r solpos.Result) float64 { return r.Azim }
+	case "clearsky_ghi":
+		return solpos.ClearSkyHaurwitz
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,226 @@
+package grafana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maltegrosse/go-solpos"
+)
+
+func newHandler(t *testing.T, optionalParameters map[string]interface{}) *Handler {
+	t.Helper()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		berlin = time.FixedZone("CET", 3600)
+	}
+	h, err := NewHandler([]solpos.Site{
+		{Name: "golden", Latitude: 39.74, Longitude: -105.18},
+		{Name: "berlin", Latitude: 52.52, Longitude: 13.40, Location: berlin},
+	}, optionalParameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func serve(h http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func query(from string, to string, intervalMs int64, maxDataPoints int, targets ...string) string {
+	q := map[string]interface{}{
+		"range":         map[string]string{"from": from, "to": to},
+		"intervalMs":    intervalMs,
+		"maxDataPoints": maxDataPoints,
+	}
+	var ts []map[string]string
+	for _, t := range targets {
+		ts = append(ts, map[string]string{"target": t})
+	}
+	q["targets"] = ts
+	b, _ := json.Marshal(q)
+	return string(b)
+}
+
+func TestNewHandler(t *testing.T) {
+	for _, sites := range [][]solpos.Site{{{Name: ""}}, {{Name: "a:b"}}, {{Name: "a"}, {Name: "a"}}} {
+		if _, err := NewHandler(sites, nil); err == nil {
+			t.Errorf("NewHandler(%v) without error", sites)
+		}
+	}
+}
+
+func TestConnection(t *testing.T) {
+	h := newHandler(t, nil)
+	if w := serve(h, http.MethodGet, "/", ""); w.Code != http.StatusOK {
+		t.Errorf("GET / = %d, want 200", w.Code)
+	}
+	if w := serve(h, http.MethodGet, "/nonsense", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /nonsense = %d, want 404", w.Code)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	h := newHandler(t, nil)
+	for _, path := range []string{"/search", "/metrics"} {
+		w := serve(h, http.MethodPost, path, "{}")
+		var targets []string
+		if err := json.Unmarshal(w.Body.Bytes(), &targets); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"berlin:elevation", "berlin:azimuth", "berlin:clearsky_ghi", "golden:elevation", "golden:azimuth", "golden:clearsky_ghi"}
+		if strings.Join(targets, ",") != strings.Join(want, ",") {
+			t.Errorf("POST %s = %v, want %v", path, targets, want)
+		}
+	}
+}
+
+type series struct {
+	Target     string        `json:"target"`
+	Datapoints [][2]*float64 `json:"datapoints"`
+}
+
+func TestQuery(t *testing.T) {
+	h := newHandler(t, nil)
+	w := serve(h, http.MethodPost, "/query", query("2021-06-21T00:00:00Z", "2021-06-21T23:00:00Z", 3600000, 0, "golden:elevation", "berlin:clearsky_ghi"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /query = %d: %s", w.Code, w.Body)
+	}
+	var response []series
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != 2 || response[0].Target != "golden:elevation" || len(response[0].Datapoints) != 24 || len(response[1].Datapoints) != 24 {
+		t.Fatalf("POST /query = %s", w.Body)
+	}
+	if ms := *response[0].Datapoints[1][1]; ms != float64(time.Date(2021, 6, 21, 1, 0, 0, 0, time.UTC).Unix()*1000) {
+		t.Errorf("time of the second point = %v", ms)
+	}
+	/* noon in Golden is 19:00 UTC */
+	if elevation := *response[0].Datapoints[19][0]; elevation < 70.0 || elevation > 75.0 {
+		t.Errorf("elevation at noon = %v", elevation)
+	}
+	if w := serve(h, http.MethodGet, "/query", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /query = %d, want 405", w.Code)
+	}
+}
+
+func TestQueryMaxDataPoints(t *testing.T) {
+	h := newHandler(t, nil)
+	w := serve(h, http.MethodPost, "/query", query("2021-06-21T00:00:00Z", "2021-06-22T00:00:00Z", 1000, 48, "golden:azimuth"))
+	var response []series
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != 1 || len(response[0].Datapoints) != 49 {
+		t.Errorf("POST /query with maxDataPoints 48 = %s", w.Body)
+	}
+}
+
+func TestQueryZeroInterval(t *testing.T) {
+	h := newHandler(t, nil)
+	w := serve(h, http.MethodPost, "/query", query("2021-06-21T00:00:00Z", "2021-06-22T00:00:00Z", 0, 0, "golden:azimuth"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /query without interval = %d: %s", w.Code, w.Body)
+	}
+	var response []series
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != 1 || len(response[0].Datapoints) != defaultPoints+1 {
+		t.Errorf("%d points without interval, want %d", len(response[0].Datapoints), defaultPoints+1)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	h := newHandler(t, nil)
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown site", query("2021-06-21T00:00:00Z", "2021-06-22T00:00:00Z", 3600000, 0, "denver:elevation")},
+		{"unknown value", query("2021-06-21T00:00:00Z", "2021-06-22T00:00:00Z", 3600000, 0, "golden:ghi")},
+		{"target without value", query("2021-06-21T00:00:00Z", "2021-06-22T00:00:00Z", 3600000, 0, "golden")},
+		{"to before from", query("2021-06-22T00:00:00Z", "2021-06-21T00:00:00Z", 3600000, 0, "golden:elevation")},
+		{"too many points", query("2000-01-01T00:00:00Z", "2021-01-01T00:00:00Z", 60000, 0, "golden:elevation")},
+		{"malformed body", "{"},
+	}
+	for _, tt := range tests {
+		if w := serve(h, http.MethodPost, "/query", tt.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: POST /query = %d, want 400", tt.name, w.Code)
+		}
+	}
+}
+
+func TestSeries(t *testing.T) {
+	h := newHandler(t, nil)
+	v := url.Values{"site": {"berlin"}, "from": {"2021-06-21T00:00:00+02:00"}, "to": {"2021-06-21T23:00:00+02:00"}}
+	w := serve(h, http.MethodGet, "/series?"+v.Encode(), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /series = %d: %s", w.Code, w.Body)
+	}
+	var rows []struct {
+		Time        time.Time `json:"time"`
+		Elevation   *float64  `json:"elevation"`
+		Azimuth     *float64  `json:"azimuth"`
+		ClearSkyGHI *float64  `json:"clearsky_ghi"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 24 {
+		t.Fatalf("%d rows with the default step, want 24", len(rows))
+	}
+	if _, offset := rows[0].Time.Zone(); offset != 7200 {
+		t.Errorf("UTC offset %d, want the summer time of the site", offset)
+	}
+	if *rows[13].ClearSkyGHI < 800.0 || *rows[1].ClearSkyGHI != 0.0 {
+		t.Errorf("clear-sky GHI at 13:00 and 01:00 = %v, %v", *rows[13].ClearSkyGHI, *rows[1].ClearSkyGHI)
+	}
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"unknown site", url.Values{"site": {"denver"}, "from": {"2021-06-21T00:00:00Z"}, "to": {"2021-06-22T00:00:00Z"}}},
+		{"malformed from", url.Values{"site": {"golden"}, "from": {"yesterday"}, "to": {"2021-06-22T00:00:00Z"}}},
+		{"malformed to", url.Values{"site": {"golden"}, "from": {"2021-06-21T00:00:00Z"}, "to": {""}}},
+		{"malformed step", url.Values{"site": {"golden"}, "from": {"2021-06-21T00:00:00Z"}, "to": {"2021-06-22T00:00:00Z"}, "step": {"1 hour"}}},
+		{"zero step", url.Values{"site": {"golden"}, "from": {"2021-06-21T00:00:00Z"}, "to": {"2021-06-22T00:00:00Z"}, "step": {"0s"}}},
+		{"to before from", url.Values{"site": {"golden"}, "from": {"2021-06-22T00:00:00Z"}, "to": {"2021-06-21T00:00:00Z"}}},
+		{"too many points", url.Values{"site": {"golden"}, "from": {"2021-06-21T00:00:00Z"}, "to": {"2021-06-22T00:00:00Z"}, "step": {"100ms"}}},
+	}
+	for _, tt := range tests {
+		if w := serve(h, http.MethodGet, "/series?"+tt.query.Encode(), ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: GET /series = %d, want 400", tt.name, w.Code)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	/* without the refraction the outputs of the azimuth and refracted elevation are NaN, they must encode as null */
+	h := newHandler(t, map[string]interface{}{"strict": true, "function": solpos.SZenetr})
+	w := serve(h, http.MethodPost, "/query", query("2021-06-21T00:00:00Z", "2021-06-21T02:00:00Z", 3600000, 0, "golden:azimuth"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /query = %d: %s", w.Code, w.Body)
+	}
+	var response []series
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != 1 || len(response[0].Datapoints) != 3 || response[0].Datapoints[0][0] != nil || response[0].Datapoints[0][1] == nil {
+		t.Errorf("POST /query in strict mode = %s", w.Body)
+	}
+	v := url.Values{"site": {"golden"}, "from": {"2021-06-21T00:00:00Z"}, "to": {"2021-06-21T02:00:00Z"}}
+	w = serve(h, http.MethodGet, "/series?"+v.Encode(), "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"azimuth":null`) {
+		t.Errorf("GET /series in strict mode = %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,100 @@
+package solpos
+
+import "math"
+
+/*============================================================================
+*    Grena algorithms
+*
+*    Five algorithms of increasing accuracy and cost for the years 2010 to
+*    2110, maximum errors of 0.19, 0.034, 0.0093, 0.0091 and 0.0027 degrees.
+*    Algorithms 1 and 2 fit right ascension and declination directly,
+*    3 to 5 the ecliptic longitude (4 and 5 with nutation).
+*       Grena, R.  2012.  Five new algorithms for the computation of sun
+*            position from 2010 to 2110.  Solar Energy 86 (5), pp. 1323-1337
+*----------------------------------------------------------------------------*/
+
+// grena calculates the basic geometry of Julday with one of the Grena algorithms: declination, right ascension, sidereal
+// time and hour angle, topocentric like the SPA. The ecliptic longitude of algorithms 1 and 2 follows from right
+// ascension and declination, mean longitude and mean anomaly are those of Michalsky.
+func (sp *PosData) grena() {
+	/* days since 2060-01-01 0:00 UT, the middle of the range */
+	t := sp.Julday - 73459.5
+	te := t + sp.DeltaT/86400.0
+	wte := 0.0172019715 * te
+
+	var ra, dec, lambda, dlam float64
+	epsilon := 4.089567e-1 - 6.19e-9*te
+	gmst := 1.7528311 + 6.300388099*t
+	switch sp.Algorithm {
+	case AlgorithmGrena1:
+		/* the fits of right ascension and declination run with the tropical year */
+		s1, c1 := math.Sincos(0.017202786 * te)
+		s2, c2 := 2.0*s1*c1, (c1+s1)*(c1-s1)
+		ra = -1.38880 + 1.72027920e-2*te + 3.199e-2*s1 - 2.65e-3*c1 + 4.050e-2*s2 + 1.525e-2*c2
+		dec = 6.57e-3 + 7.347e-2*s1 - 3.9919e-1*c1 + 7.3e-4*s2 - 6.60e-3*c2
+		gmst = 1.75283 + 6.3003881*t
+	case AlgorithmGrena2:
+		s1, c1 := math.Sincos(0.017202786 * te)
+		s2, c2 := 2.0*s1*c1, (c1+s1)*(c1-s1)
+		s3, c3 := s2*c1+c2*s1, c2*c1-s2*s1
+		s4, c4 := 2.0*s2*c2, (c2+s2)*(c2-s2)
+		ra = -1.38880 + 1.72027920e-2*te + 3.199e-2*s1 - 2.65e-3*c1 + 4.050e-2*s2 + 1.525e-2*c2 + 1.33e-3*s3 + 3.8e-4*c3 +
+			7.3e-4*s4 + 6.2e-4*c4
+		dec = 6.57e-3 + 7.347e-2*s1 - 3.9919e-1*c1 + 7.3e-4*s2 - 6.60e-3*c2 + 1.50e-3*s3 - 2.58e-3*c3 + 6e-5*s4 - 1.3e-4*c4
+		gmst = 1.75283 + 6.3003881*t
+	case AlgorithmGrena3:
+		lambda = -1.388803 + 1.720279216e-2*te + 3.3366e-2*math.Sin(wte-0.06172) + 3.53e-4*math.Sin(2.0*wte-0.1163)
+	case AlgorithmGrena4:
+		l := 1.752790 + 1.720279216e-2*te + 3.3366e-2*math.Sin(wte-0.06172) + 3.53e-4*math.Sin(2.0*wte-0.1163)
+		/* nutation */
+		nu := 9.282e-4*te - 0.8
+		dlam = 8.34e-5 * math.Sin(nu)
+		lambda = l + math.Pi + dlam
+		epsilon += 4.46e-5 * math.Cos(nu)
+	default:
+		s1, c1 := math.Sincos(wte)
+		s2, c2 := 2.0*s1*c1, (c1+s1)*(c1-s1)
+		s3, c3 := s2*c1+c2*s1, c2*c1-s2*s1
+		l := 1.7527901 + 1.7202792159e-2*te + 3.33024e-2*s1 - 2.0582e-3*c1 + 3.512e-4*s2 - 4.07e-5*c2 + 5.2e-6*s3 - 9e-7*c3 -
+			8.23e-6*s1*math.Sin(2.92e-5*te) + 1.27e-5*math.Sin(1.49e-3*te-2.337) + 1.21e-5*math.Sin(4.31e-3*te+3.065) +
+			2.33e-5*math.Sin(1.076e-2*te-1.533) + 3.49e-5*math.Sin(1.575e-2*te-2.358) + 2.67e-5*math.Sin(2.152e-2*te+0.074) +
+			1.28e-5*math.Sin(3.152e-2*te+1.547) + 3.14e-5*math.Sin(2.1277e-1*te-0.488)
+		nu := 9.282e-4*te - 0.8
+		dlam = 8.34e-5 * math.Sin(nu)
+		lambda = l + math.Pi + dlam
+		epsilon += 4.46e-5 * math.Cos(nu)
+	}
+	se, ce := math.Sincos(epsilon)
+	if sp.Algorithm == AlgorithmGrena1 || sp.Algorithm == AlgorithmGrena2 {
+		sa, ca := math.Sincos(ra)
+		lambda = math.Atan2(sa*ce+math.Tan(dec)*se, ca)
+	} else {
+		sl, cl := math.Sincos(lambda)
+		ra = math.Atan2(sl*ce, cl)
+		dec = math.Asin(sl * se)
+	}
+	/* the nutation in right ascension, the equation of the equinoxes */
+	gmst += 0.92 * dlam
+
+	sp.Ectime = sp.Julday - 51545.0 + sp.DeltaT/86400.0
+	sp.Mnlong = limitDegrees(280.460 + 0.9856474*sp.Ectime)
+	sp.Mnanom = limitDegrees(357.528 + 0.9856003*sp.Ectime)
+	sp.Eclong = limitDegrees(degrad * lambda)
+	sp.Ecobli = degrad * epsilon
+	sp.Gmst = limitDegrees(degrad*gmst) / 15.0
+	sp.Lmst = limitDegrees(sp.Gmst*15.0 + sp.Longitude)
+	h := limitDegrees(sp.Lmst - degrad*ra)
+
+	/* parallax of the observer at the mean distance of the sun */
+	dalpha, deltaPrime := sp.parallax(degrad*dec, h, 1.0)
+	sp.Declin = deltaPrime
+	sp.Rascen = limitDegrees(degrad*ra + dalpha)
+	sp.Hrang = h - dalpha
+	/* (force it between -180 and 180 degrees) */
+	if sp.Hrang > 180.0 {
+		sp.Hrang -= 360.0
+	}
+	if sp.Hrang < -180.0 {
+		sp.Hrang += 360.0
+	}
+}
@@ -0,0 +1,125 @@
+package solpos
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// GridConfig configures GridPositions
+type GridConfig struct {
+	South      float64  // Southern edge of the bounding box, degrees north
+	West       float64  // Western edge of the bounding box, degrees east
+	North      float64  // Northern edge of the bounding box, degrees north
+	East       float64  // Eastern edge of the bounding box, degrees east (may exceed 180 across the antimeridian)
+	Resolution float64  // Edge length of a cell, degrees
+	Options    *Options // Optional parameters, DEFAULT (nil) = DefaultOptions()
+}
+
+// Grid is the sun on a regular latitude/longitude grid at one instant. Row 0 is the northern edge and column 0 the western
+// edge, values are row-major like most raster formats and are calculated at the cell centers.
+type Grid struct {
+	Rows       int
+	Cols       int
+	North      float64   // Northern edge of the grid, degrees north
+	West       float64   // Western edge of the grid, degrees east
+	Resolution float64   // Edge length of a cell, degrees
+	Zenith     []float64 // Solar zenith angle, refracted (Zenref), degrees
+	Azimuth    []float64 // Solar azimuth angle, N=0, E=90, S=180, W=270
+	Etr        []float64 // Extraterrestrial global horizontal irradiance, W/sq m
+}
+
+// GridPositions calculates zenith, azimuth and extraterrestrial irradiance for every cell of a bounding box at dt, e.g. for
+// solar resource maps. The ecliptic geometry of the instant is calculated once and shared by the cells. The bounding box is
+// covered by whole cells, the last row and column may extend beyond the southern and eastern edge.
+func GridPositions(dt time.Time, config GridConfig) (*Grid, error) {
+	if config.Resolution <= 0.0 {
+		return nil, errors.New("Please fix resolution, must be positive")
+	}
+	if config.South >= config.North || config.South < -90.0 || config.North > 90.0 {
+		return nil, errors.New("Please fix south and north, must be within -90 and 90 with south below north")
+	}
+	if config.West >= config.East || config.West < -180.0 || config.East-config.West > 360.0 {
+		return nil, errors.New("Please fix west and east, west must be within -180 and 180 and below east, the box at most 360 degrees wide")
+	}
+	g := &Grid{
+		Rows:       int(math.Ceil((config.North-config.South)/config.Resolution - 1e-9)),
+		Cols:       int(math.Ceil((config.East-config.West)/config.Resolution - 1e-9)),
+		North:      config.North,
+		West:       config.West,
+		Resolution: config.Resolution,
+	}
+	n := g.Rows * g.Cols
+	g.Zenith, g.Azimuth, g.Etr = make([]float64, n), make([]float64, n), make([]float64, n)
+	options := DefaultOptions()
+	if config.Options != nil {
+		options = *config.Options
+	}
+	s, err := NewSolposWithOptions(dt, g.latitude(0), g.longitude(0), options)
+	if err != nil {
+		return nil, err
+	}
+	sp := s.(*PosData)
+	sp.shared = &sharedGeometry{}
+	for row := 0; row < g.Rows; row++ {
+		for col := 0; col < g.Cols; col++ {
+			sp.Latitude, sp.Longitude = g.latitude(row), g.longitude(col)
+			err = sp.Calculate()
+			if err != nil {
+				return nil, wrap(err, fmt.Sprintf("cell %d,%d", row, col))
+			}
+			i := row*g.Cols + col
+			g.Zenith[i], g.Azimuth[i], g.Etr[i] = sp.Zenref, sp.Azim, sp.Etr
+		}
+	}
+	return g, nil
+}
+
+// latitude returns the latitude of the center of a row, limited to the poles
+func (g *Grid) latitude(row int) float64 {
+	return math.Max(g.North-(float64(row)+0.5)*g.Resolution, -90.0)
+}
+
+// longitude returns the longitude of the center of a column, wrapped to -180 to 180
+func (g *Grid) longitude(col int) float64 {
+	lon := g.West + (float64(col)+0.5)*g.Resolution
+	if lon > 180.0 {
+		lon -= 360.0
+	}
+	return lon
+}
+
+// GeoTransform returns the affine transform of the grid in the order of GDAL (x origin, x resolution, row rotation,
+// y origin, column rotation, y resolution) in degrees of WGS 84 (EPSG:4326), for writing the values e.g. as GeoTIFF
+func (g *Grid) GeoTransform() [6]float64 {
+	return [6]float64{g.West, g.Resolution, 0.0, g.North, 0.0, -g.Resolution}
+}
+
+// WriteASCIIGrid writes values of the grid (e.g. Zenith) as Esri ASCII grid, readable by GDAL and most GIS software
+func (g *Grid) WriteASCIIGrid(w io.Writer, values []float64) error {
+	if len(values) != g.Rows*g.Cols {
+		return errors.New("Please fix values, must have rows * cols values")
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "ncols %d\nnrows %d\nxllcorner %s\nyllcorner %s\ncellsize %s\nNODATA_value -9999\n", g.Cols, g.Rows,
+		strconv.FormatFloat(g.West, 'g', -1, 64), strconv.FormatFloat(g.North-float64(g.Rows)*g.Resolution, 'g', -1, 64),
+		strconv.FormatFloat(g.Resolution, 'g', -1, 64))
+	for row := 0; row < g.Rows; row++ {
+		for col := 0; col < g.Cols; col++ {
+			if col > 0 {
+				bw.WriteByte(' ')
+			}
+			v := values[row*g.Cols+col]
+			if math.IsNaN(v) {
+				v = -9999
+			}
+			bw.WriteString(strconv.FormatFloat(v, 'g', 8, 64))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,145 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// horizonSearchStep is the sampling interval of the effective sunrise and sunset search
+const horizonSearchStep = 5 * time.Minute
+
+// HorizonPoint is the elevation of the local horizon (terrain, buildings) toward an azimuth
+type HorizonPoint struct {
+	Azimuth   float64 // Azimuth, N=0, E=90, S=180, W=270
+	Elevation float64 // Elevation of the horizon line, degrees
+}
+
+// HorizonProfile is the local horizon line of a site, linearly interpolated between its points (across north as well).
+// The nil profile is the flat horizon at 0 degrees.
+type HorizonProfile []HorizonPoint
+
+// NewHorizonProfile creates new instance of HorizonProfile from points in any order, e.g. measured with a horizon scanner
+// or calculated from a DEM
+func NewHorizonProfile(points []HorizonPoint) (HorizonProfile, error) {
+	if len(points) == 0 {
+		return nil, errors.New("Please fix points, at least one is required")
+	}
+	h := append(HorizonProfile(nil), points...)
+	for _, p := range h {
+		if !(p.Azimuth >= 0.0 && p.Azimuth < 360.0) {
+			return nil, errors.New("Please fix azimuth [0 - 360)")
+		}
+		if !(p.Elevation >= -90.0 && p.Elevation <= 90.0) {
+			return nil, errors.New("Please fix elevation [-90 - 90]")
+		}
+	}
+	sort.Slice(h, func(i, j int) bool { return h[i].Azimuth < h[j].Azimuth })
+	for i := 1; i < len(h); i++ {
+		if h[i].Azimuth == h[i-1].Azimuth {
+			return nil, errors.New("Please fix points, an azimuth is given twice")
+		}
+	}
+	return h, nil
+}
+
+// ElevationAt returns the elevation of the horizon line toward an azimuth, degrees
+func (h HorizonProfile) ElevationAt(azimuth float64) float64 {
+	if len(h) == 0 {
+		return 0.0
+	}
+	azimuth = math.Mod(math.Mod(azimuth, 360.0)+360.0, 360.0)
+	/* the first point at or after the azimuth, the segment before it may wrap across north */
+	i := sort.Search(len(h), func(i int) bool { return h[i].Azimuth >= azimuth })
+	if i < len(h) && h[i].Azimuth == azimuth {
+		return h[i].Elevation
+	}
+	lo, hi := h[(i+len(h)-1)%len(h)], h[i%len(h)]
+	span := math.Mod(hi.Azimuth-lo.Azimuth+360.0, 360.0)
+	if span == 0.0 {
+		/* a single point */
+		return lo.Elevation
+	}
+	f := math.Mod(azimuth-lo.Azimuth+360.0, 360.0) / span
+	return lo.Elevation + f*(hi.Elevation-lo.Elevation)
+}
+
+func (sp *PosData) SetHorizon(profile HorizonProfile) {
+	sp.Horizon = profile
+}
+
+func (sp *PosData) GetHorizon() HorizonProfile {
+	return sp.Horizon
+}
+
+func (sp *PosData) GetSunObstructed() bool {
+	return sp.SunObstructed
+}
+
+func (sp *PosData) EffectiveSunrise() (time.Time, error) {
+	rise, _, err := sp.effectiveRiseSet()
+	if err == nil && rise.IsZero() {
+		err = wrap(ErrNoCrossing, "the sun does not rise above the horizon profile")
+	}
+	return rise, err
+}
+
+func (sp *PosData) EffectiveSunset() (time.Time, error) {
+	_, set, err := sp.effectiveRiseSet()
+	if err == nil && set.IsZero() {
+		err = wrap(ErrNoCrossing, "the sun does not set behind the horizon profile")
+	}
+	return set, err
+}
+
+// effectiveRiseSet returns the first time the sun clears the horizon profile and the last time it goes behind it on the
+// (local) day of the last calculation, sampled and bisected like elevationCrossings, zero times if there are
+// none. The search runs on a copy, the calculation is not changed.
+func (sp *PosData) effectiveRiseSet() (rise time.Time, set time.Time, err error) {
+	err = sp.Computed(LSolazm | LRefrac)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	c := *sp
+	dt := sp.Getdate()
+	start := time.Date(dt.Year(), dt.Month(), dt.Day(), 0, 0, 0, 0, dt.Location())
+	end := start.AddDate(0, 0, 1)
+	/* refracted elevation above the horizon line, not limited to -9 degrees like Elevref */
+	clearance := func(t time.Time) (float64, error) {
+		e, err := c.apparentElevationAt(t)
+		return e - c.Horizon.ElevationAt(c.Azim), err
+	}
+	prev, err := clearance(start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	for t0 := start; t0.Before(end); {
+		t1 := t0.Add(horizonSearchStep)
+		if t1.After(end) {
+			t1 = end
+		}
+		d1, err := clearance(t1)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if (prev >= 0.0) != (d1 >= 0.0) {
+			rising := d1 >= 0.0
+			t, err := bisect(t0, t1, func(mid time.Time) (bool, error) {
+				d, err := clearance(mid)
+				return (d >= 0.0) == rising, err
+			})
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			if rising && rise.IsZero() {
+				rise = t
+			}
+			if !rising {
+				set = t
+			}
+		}
+		t0, prev = t1, d1
+	}
+	return rise, set, nil
+}
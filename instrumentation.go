@@ -0,0 +1,29 @@
+package solpos
+
+// Instrumentation observes long computations and caches, e.g. to export traces and metrics (see contrib/otelsolpos)
+type Instrumentation interface {
+	// Start is called when a computation (e.g. "StreamSeries") starts, the returned function is called when it ends
+	// with the number of points computed and the resulting error
+	Start(operation string) func(points int, err error)
+	// CacheLookup is called for every lookup of a ResultCache
+	CacheLookup(hit bool)
+}
+
+type noInstrumentation struct{}
+
+func (noInstrumentation) Start(operation string) func(points int, err error) {
+	return func(points int, err error) {}
+}
+
+func (noInstrumentation) CacheLookup(hit bool) {}
+
+var instrumentation Instrumentation = noInstrumentation{}
+
+// SetInstrumentation installs the instrumentation of all computations, nil removes it.
+// It is meant to be called once at startup, before any computation.
+func SetInstrumentation(i Instrumentation) {
+	if i == nil {
+		i = noInstrumentation{}
+	}
+	instrumentation = i
+}
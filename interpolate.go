@@ -0,0 +1,123 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Interpolator provides sun positions at arbitrary times between exact calculations done at a coarse cadence.
+// The unrefracted sun direction is interpolated as unit vector with cubic (Catmull-Rom) splines and refracted
+// afterwards, which keeps the error far below an arcminute for cadences up to a few minutes, also around the zenith
+// and when the azimuth wraps. It is meant for high-rate loops, e.g. a tracker controller running at 10 Hz.
+type Interpolator struct {
+	start   time.Time
+	end     time.Time
+	cadence time.Duration
+	tilt    float64
+	aspect  float64
+	press   float64
+	temp    float64
+	samples []interpolationSample
+}
+
+type interpolationSample struct {
+	etr    [3]float64 // unrefracted sun unit vector: east, north, up
+	result Result
+}
+
+func (sp *PosData) Interpolate(start time.Time, end time.Time, cadence time.Duration) (_ *Interpolator, err error) {
+	points := 0
+	done := instrumentation.Start("Interpolate")
+	defer func() { done(points, err) }()
+	if cadence <= 0 {
+		return nil, errors.New("Please fix cadence, must be positive")
+	}
+	if end.Before(start) {
+		return nil, errors.New("Please fix end, must not be before start")
+	}
+	ip := &Interpolator{start: start, end: end, cadence: cadence, tilt: sp.Tilt, aspect: sp.Aspect, press: sp.Press, temp: sp.Temp}
+	/* one extra sample before and after the range, the splines need four points */
+	n := int(end.Sub(start)/cadence) + 4
+	ip.samples = make([]interpolationSample, 0, n)
+	for i := -1; i < n-1; i++ {
+		sp.SetDate(start.Add(time.Duration(i) * cadence))
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		ip.samples = append(ip.samples, interpolationSample{
+			etr:    sunVector(sp.Zenetr, sp.Azim),
+			result: sp.GetResult(),
+		})
+		points++
+	}
+	return ip, nil
+}
+
+// At returns the interpolated sun position at dt, which must be within the range of the interpolator.
+// Zenref, Elevref, Coszen, Zenetr, Elevetr, Azim, Etr, Cosinc and Etrtilt are interpolated,
+// all other fields are those of the preceding exact calculation.
+func (ip *Interpolator) At(dt time.Time) (Result, error) {
+	if dt.Before(ip.start) || dt.After(ip.end) {
+		return Result{}, errors.New("Please fix time, outside of the interpolation range")
+	}
+	offset := dt.Sub(ip.start)
+	i := int(offset / ip.cadence)
+	f := float64(offset-time.Duration(i)*ip.cadence) / float64(ip.cadence)
+	/* samples are shifted by one, i+1 is the sample at or before dt */
+	p0, p1, p2, p3 := &ip.samples[i], &ip.samples[i+1], &ip.samples[i+2], &ip.samples[i+3]
+
+	r := p1.result
+	r.Time = dt
+	zenetr, azim := vectorAngles(catmullRom(p0.etr, p1.etr, p2.etr, p3.etr, f))
+	/* same limits as zenNoRef and refrac */
+	if zenetr > 99.0 {
+		zenetr = 99.0
+	}
+	r.Zenetr, r.Elevetr = zenetr, 90.0-zenetr
+	r.Elevref = math.Max(-9.0, r.Elevetr+refraction(r.Elevetr, ip.press, ip.temp))
+	r.Zenref, r.Coszen = 90.0-r.Elevref, math.Cos(raddeg*(90.0-r.Elevref))
+	r.Azim = azim
+
+	/* the irradiances follow from the angles like in etr and tilt */
+	r.Etr = 0.0
+	if r.Coszen > 0.0 {
+		r.Etr = r.Etrn * r.Coszen
+	}
+	r.Cosinc = incidence(ip.tilt, ip.aspect, r.Zenref, r.Azim)
+	r.Etrtilt = 0.0
+	if r.Cosinc > 0.0 {
+		r.Etrtilt = r.Etrn * r.Cosinc
+	}
+	return r, nil
+}
+
+// sunVector returns the unit vector (east, north, up) toward the sun
+func sunVector(zenith float64, azimuth float64) [3]float64 {
+	sz := math.Sin(raddeg * zenith)
+	return [3]float64{sz * math.Sin(raddeg*azimuth), sz * math.Cos(raddeg*azimuth), math.Cos(raddeg * zenith)}
+}
+
+// vectorAngles returns zenith and azimuth angle of a (not necessarily unit) vector (east, north, up)
+func vectorAngles(v [3]float64) (zenith float64, azimuth float64) {
+	norm := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	zenith = degrad * math.Acos(math.Max(-1.0, math.Min(1.0, v[2]/norm)))
+	azimuth = degrad * math.Atan2(v[0], v[1])
+	if azimuth < 0.0 {
+		azimuth += 360.0
+	}
+	return zenith, azimuth
+}
+
+// catmullRom interpolates between p1 (f = 0) and p2 (f = 1) on a uniform grid
+func catmullRom(p0 [3]float64, p1 [3]float64, p2 [3]float64, p3 [3]float64, f float64) [3]float64 {
+	var v [3]float64
+	f2, f3 := f*f, f*f*f
+	for k := range v {
+		v[k] = 0.5 * (2.0*p1[k] + (p2[k]-p0[k])*f +
+			(2.0*p0[k]-5.0*p1[k]+4.0*p2[k]-p3[k])*f2 +
+			(3.0*p1[k]-p0[k]-3.0*p2[k]+p3[k])*f3)
+	}
+	return v
+}
@@ -0,0 +1,52 @@
+package solpos
+
+import (
+	"math"
+	"time"
+)
+
+/*============================================================================
+*    Julian Date
+*
+*    The date inputs as continuous count of days since -4712-01-01 12:00 UT
+*    (Julian calendar), e.g. 2451545.0 = 2000-01-01 12:00 UTC, and the
+*    Modified Julian Date (JD - 2400000.5). Both count UTC days, the
+*    calculation applies DeltaT for terrestrial time.
+*----------------------------------------------------------------------------*/
+
+const (
+	julianDateUnix = 2440587.5 /* Julian Date of 1970-01-01 0:00 UTC */
+	mjdOffset      = 2400000.5 /* Julian Date of the Modified Julian Date 0 */
+)
+
+// SetJulianDate sets the date inputs to the instant of the Julian Date jd (UTC), to the second, in the Location or the
+// Timezone of the date inputs
+func (sp *PosData) SetJulianDate(jd float64) {
+	loc := sp.Location
+	if loc == nil {
+		loc = time.FixedZone("ManualTimeZone", int(math.Round(sp.Timezone*3600.0)))
+	}
+	days := jd - julianDateUnix
+	seconds := math.Floor(days * 86400.0)
+	t := time.Unix(int64(seconds), int64((days*86400.0-seconds)*1.0e9)).Round(time.Second)
+	location := sp.Location
+	sp.SetDate(t.In(loc))
+	/* keep a fixed Timezone without location */
+	sp.Location = location
+}
+
+// GetJulianDate returns the Julian Date (UTC) of the date inputs, the full JD unlike Julday
+func (sp *PosData) GetJulianDate() float64 {
+	t := sp.Getdate()
+	return julianDateUnix + (float64(t.Unix())+float64(t.Nanosecond())/1.0e9)/86400.0
+}
+
+// SetModifiedJulianDate sets the date inputs to the instant of the Modified Julian Date mjd (UTC), see SetJulianDate
+func (sp *PosData) SetModifiedJulianDate(mjd float64) {
+	sp.SetJulianDate(mjd + mjdOffset)
+}
+
+// GetModifiedJulianDate returns the Modified Julian Date (UTC) of the date inputs
+func (sp *PosData) GetModifiedJulianDate() float64 {
+	return sp.GetJulianDate() - mjdOffset
+}
@@ -0,0 +1,154 @@
+package solpos
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LightingConfig configures LightingSchedule
+type LightingConfig struct {
+	Start     time.Time     // First day of the schedule, local day of the site
+	End       time.Time     // Last day of the schedule (inclusive), local day of the site
+	Elevation float64       // Unrefracted sun elevation at which the lights switch, degrees (-9 to 90), DEFAULT (0) = -6 (civil dusk and dawn)
+	OnOffset  time.Duration // Shift of the switch-on time from dusk, negative = earlier
+	OffOffset time.Duration // Shift of the switch-off time from dawn, negative = earlier
+	MinimumOn time.Duration // Shortest time the lights stay on, shorter periods are extended by switching off later
+	Step      time.Duration // Sampling step of the dusk and dawn search, DEFAULT (0) = 10 minutes
+}
+
+// LightingPeriod is a time span with the lights switched on
+type LightingPeriod struct {
+	On  time.Time // Switch-on time, local time of the site
+	Off time.Time // Switch-off time, local time of the site
+}
+
+// LightingSchedule returns the periods with lights switched on at a site from dusk (the sun setting below the configured
+// elevation) to dawn (the sun rising above it), shifted by the offsets. Lights which are on at the beginning of the first day or
+// at the end of the last day (e.g. in the polar night) are switched on at the beginning and off at the end of the range, these
+// periods are not extended to the minimum on-time. No period is returned for nights in which the sun stays above the elevation.
+func LightingSchedule(site Site, config LightingConfig) ([]LightingPeriod, error) {
+	elevation := config.Elevation
+	if elevation == 0.0 {
+		elevation = -6.0
+	}
+	if elevation <= -9.0 || elevation > 90.0 {
+		return nil, errors.New("Please fix elevation, must be between -9 and 90 degrees")
+	}
+	step := config.Step
+	if step == 0 {
+		step = 10 * time.Minute
+	}
+	if step < 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	if config.MinimumOn < 0 {
+		return nil, errors.New("Please fix minimum on-time, must not be negative")
+	}
+	loc := site.location()
+	y, m, d := config.Start.In(loc).Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	y, m, d = config.End.In(loc).Date()
+	end := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	if !end.After(start) {
+		return nil, errors.New("Please fix end, must not be before start")
+	}
+	sp, err := NewSolpos(start, site.Latitude, site.Longitude, map[string]interface{}{"function": LGeom | LZenetr})
+	if err != nil {
+		return nil, err
+	}
+	e, err := elevationAt(sp, start)
+	if err != nil {
+		return nil, err
+	}
+	crossings, err := elevationCrossings(sp, start, end, step, elevation)
+	if err != nil {
+		return nil, err
+	}
+
+	var periods []LightingPeriod
+	var on time.Time
+	dark := e < elevation
+	clipped := dark
+	if dark {
+		on = start
+	}
+	for _, c := range crossings {
+		switch {
+		case !c.Rising && !dark:
+			on, dark, clipped = c.Time.Add(config.OnOffset), true, false
+		case c.Rising && dark:
+			off := c.Time.Add(config.OffOffset)
+			if !clipped && off.Sub(on) < config.MinimumOn {
+				off = on.Add(config.MinimumOn)
+			}
+			periods = appendLighting(periods, LightingPeriod{On: on, Off: off})
+			dark = false
+		}
+	}
+	if dark {
+		periods = appendLighting(periods, LightingPeriod{On: on, Off: end})
+	}
+	return periods, nil
+}
+
+// appendLighting appends a period, merging it with the previous one if they overlap after offsets and the minimum on-time
+func appendLighting(periods []LightingPeriod, p LightingPeriod) []LightingPeriod {
+	if n := len(periods); n > 0 && !p.On.After(periods[n-1].Off) {
+		if p.Off.After(periods[n-1].Off) {
+			periods[n-1].Off = p.Off
+		}
+		return periods
+	}
+	return append(periods, p)
+}
+
+// WriteLightingCSV writes a lighting schedule as comma separated rows (on, off, hours), preceded by a header row
+func WriteLightingCSV(w io.Writer, periods []LightingPeriod) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"on", "off", "hours"})
+	if err != nil {
+		return err
+	}
+	for _, p := range periods {
+		err = cw.Write([]string{p.On.Format(time.RFC3339), p.Off.Format(time.RFC3339), strconv.FormatFloat(p.Off.Sub(p.On).Hours(), 'f', 4, 64)})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteLightingICS writes a lighting schedule as iCalendar (RFC 5545) with one event per period, e.g. for import into a
+// building management system or calendar. The summary names the events, the times are written in UTC.
+func WriteLightingICS(w io.Writer, periods []LightingPeriod, summary string) error {
+	const layout = "20060102T150405Z"
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		bw.WriteString(s)
+		bw.WriteString("\r\n")
+	}
+	stamp := time.Now().UTC().Format(layout)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-solpos//lighting schedule//EN")
+	for _, p := range periods {
+		line("BEGIN:VEVENT")
+		line("UID:" + p.On.UTC().Format(layout) + "-lighting@go-solpos")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + p.On.UTC().Format(layout))
+		line("DTEND:" + p.Off.UTC().Format(layout))
+		line("SUMMARY:" + icsEscaper.Replace(summary))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// icsEscaper escapes iCalendar TEXT values
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
@@ -0,0 +1,132 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// MonthlyConfig configures MonthlySummaries
+type MonthlyConfig struct {
+	Year               int                    // Year to summarize, DEFAULT (0) = current year
+	Tilt               float64                // Tilt of the planned surface from horizontal, degrees
+	Aspect             float64                // Direction the planned surface faces, N=0, E=90, S=180, W=270, DEFAULT (0) = 180
+	Step               time.Duration          // Integration step, DEFAULT (0) = 15 minutes
+	LinkeTurbidity     float64                // Linke turbidity of the clear sky (see ClearSkyIneichen), DEFAULT (0) = 3
+	Altitude           float64                // Altitude of the site, meters
+	Albedo             float64                // Ground reflectance, 0 = no reflected irradiance
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos, e.g. "press"
+}
+
+// MonthlySummary is the sun at a site during one month
+type MonthlySummary struct {
+	Month                time.Month
+	Days                 int       // Number of days of the month
+	DayLength            float64   // Mean time of the sun above the horizon (without refraction) per day, hours
+	EarliestSunrise      time.Time // Sunrise earliest in the day (local clock time), zero if the sun does not rise
+	LatestSunrise        time.Time // Sunrise latest in the day, zero if the sun does not rise
+	EarliestSunset       time.Time // Sunset earliest in the day, zero if the sun does not set
+	LatestSunset         time.Time // Sunset latest in the day, zero if the sun does not set
+	PolarDays            int       // Days with 24 hours of sun up
+	PolarNights          int       // Days with 24 hours of sun down
+	InsolationHorizontal float64   // Clear-sky global horizontal insolation of the month, kWh/sq m
+	InsolationTilt       float64   // Clear-sky plane-of-array insolation on the configured surface, kWh/sq m
+}
+
+// MonthlySummaries returns for every month of the year the mean day length, the extremes of sunrise and sunset and the
+// clear-sky insolation (Ineichen-Perez, isotropic sky) on a horizontal and on the configured surface at a site, the
+// table of a site feasibility report. Sunrise and sunset are without refraction like Sretr and Ssetr.
+func MonthlySummaries(site Site, config MonthlyConfig) ([]MonthlySummary, error) {
+	step := config.Step
+	if step == 0 {
+		step = 15 * time.Minute
+	}
+	if step < 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	aspect := config.Aspect
+	if aspect == 0.0 {
+		aspect = 180.0
+	}
+	linke := config.LinkeTurbidity
+	if linke == 0.0 {
+		linke = 3.0
+	}
+	loc := site.location()
+	year := config.Year
+	if year == 0 {
+		year = time.Now().In(loc).Year()
+	}
+	sp, err := NewSolpos(time.Date(year, 1, 1, 0, 0, 0, 0, loc), site.Latitude, site.Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+	normal := SurfaceNormal(config.Tilt, aspect)
+	costilt := math.Cos(raddeg * config.Tilt)
+	hours := step.Hours()
+
+	summaries := make([]MonthlySummary, 12)
+	/* earliest and latest sunrise and sunset of every month, minutes from midnight */
+	extremes := make([][4]float64, 12)
+	for m := range summaries {
+		summaries[m].Month = time.Month(m + 1)
+	}
+	for day := time.Date(year, 1, 1, 0, 0, 0, 0, loc); day.Year() == year; day = day.AddDate(0, 0, 1) {
+		s := &summaries[day.Month()-1]
+		s.Days++
+		/* sunrise and sunset of the day */
+		sp.SetDate(day.Add(12 * time.Hour))
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case sp.GetSsha() >= 180.0:
+			s.PolarDays++
+			s.DayLength += 24.0
+		case sp.GetSsha() <= 0.0:
+			s.PolarNights++
+		default:
+			s.DayLength += (sp.GetSsetr() - sp.GetSretr()) / 60.0
+			/* compared in minutes from midnight of their day, a sunset after midnight is late, not early */
+			rise, set := sp.GetSretr(), sp.GetSsetr()
+			e := &extremes[day.Month()-1]
+			if s.EarliestSunrise.IsZero() || rise < e[0] {
+				s.EarliestSunrise, e[0] = sp.GetSunrise(), rise
+			}
+			if s.LatestSunrise.IsZero() || rise > e[1] {
+				s.LatestSunrise, e[1] = sp.GetSunrise(), rise
+			}
+			if s.EarliestSunset.IsZero() || set < e[2] {
+				s.EarliestSunset, e[2] = sp.GetSunset(), set
+			}
+			if s.LatestSunset.IsZero() || set > e[3] {
+				s.LatestSunset, e[3] = sp.GetSunset(), set
+			}
+		}
+		/* insolation, midpoint rule, every step is represented by its middle */
+		next := day.AddDate(0, 0, 1)
+		for dt := day.Add(step / 2); dt.Before(next); dt = dt.Add(step) {
+			sp.SetDate(dt)
+			err = sp.Calculate()
+			if err != nil {
+				return nil, err
+			}
+			r := sp.GetResult()
+			if r.Zenref >= 90.0 {
+				continue
+			}
+			cs := ClearSkyIneichen(r, linke, config.Altitude)
+			inc, err := IncidenceOnSurface(r, normal)
+			if err != nil {
+				return nil, err
+			}
+			s.InsolationHorizontal += cs.GHI * hours / 1000.0
+			s.InsolationTilt += isotropicPOA(cs, inc.Cosinc, costilt, config.Albedo) * hours / 1000.0
+		}
+	}
+	for m := range summaries {
+		summaries[m].DayLength /= float64(summaries[m].Days)
+	}
+	return summaries, nil
+}
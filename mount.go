@@ -0,0 +1,51 @@
+package solpos
+
+import "math"
+
+// MountKinematics selects the axes arrangement of a two-axis mount (tracker, heliostat, telescope)
+type MountKinematics int
+
+const (
+	// MountAzEl has a vertical primary (azimuth) axis carrying a horizontal secondary (elevation) axis.
+	// Primary: azimuth, N=0, E=90, S=180, W=270; secondary: elevation above the horizon.
+	MountAzEl MountKinematics = iota
+	// MountTipTilt has a horizontal north-south primary axis carrying a perpendicular secondary axis.
+	// Primary: rotation around the north-south axis, positive toward west; secondary: tilt toward north.
+	MountTipTilt
+	// MountPolar (equatorial) has a primary axis parallel to the Earth's axis carrying a declination axis.
+	// Primary: hour angle, degrees WEST of the local meridian; secondary: declination, degrees NORTH.
+	MountPolar
+)
+
+// MountAngles are the drive angles of a two-axis mount, degrees
+type MountAngles struct {
+	Primary   float64 // Angle of the primary (fixed) axis
+	Secondary float64 // Angle of the secondary axis, carried by the primary one
+}
+
+// DriveAngles returns the drive angles pointing the mount to the calculated (refracted) sun position of r
+func (k MountKinematics) DriveAngles(r Result) MountAngles {
+	return k.DriveAnglesFor(r.Azim, r.Elevref, r.Latitude)
+}
+
+// DriveAnglesFor returns the drive angles pointing the mount at latitude (degrees north) to a direction given by azimuth and elevation
+func (k MountKinematics) DriveAnglesFor(azimuth float64, elevation float64, latitude float64) MountAngles {
+	if k == MountAzEl {
+		return MountAngles{Primary: azimuth, Secondary: elevation}
+	}
+	/* direction vector: e east, n north, u up */
+	e := math.Cos(raddeg*elevation) * math.Sin(raddeg*azimuth)
+	n := math.Cos(raddeg*elevation) * math.Cos(raddeg*azimuth)
+	u := math.Sin(raddeg * elevation)
+	if k == MountPolar {
+		sl, cl := math.Sin(raddeg*latitude), math.Cos(raddeg*latitude)
+		return MountAngles{
+			Primary:   degrad * math.Atan2(-e, cl*u-sl*n),
+			Secondary: degrad * math.Asin(math.Max(-1.0, math.Min(1.0, sl*u+cl*n))),
+		}
+	}
+	return MountAngles{
+		Primary:   degrad * math.Atan2(-e, u),
+		Secondary: degrad * math.Asin(math.Max(-1.0, math.Min(1.0, n))),
+	}
+}
@@ -0,0 +1,92 @@
+package solpos
+
+import (
+	"math"
+	"time"
+)
+
+/* MessagePack, see https://github.com/msgpack/msgpack/blob/master/spec.md */
+
+type msgpackEncoder struct{}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+func (msgpackEncoder) mapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return appendUint16(append(b, 0xde), uint16(n))
+	default:
+		return appendUint32(append(b, 0xdf), uint32(n))
+	}
+}
+
+func (msgpackEncoder) arrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return appendUint16(append(b, 0xdc), uint16(n))
+	default:
+		return appendUint32(append(b, 0xdd), uint32(n))
+	}
+}
+
+func (msgpackEncoder) float32(b []byte, v float32) []byte {
+	return appendUint32(append(b, 0xca), math.Float32bits(v))
+}
+
+func (msgpackEncoder) bool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+	return append(b, 0xc2)
+}
+
+// int appends v in the shortest form, like the reference implementations
+func (msgpackEncoder) int(b []byte, v int64) []byte {
+	switch {
+	case v >= -32 && v <= math.MaxInt8:
+		return append(b, byte(v))
+	case v > math.MaxInt8 && v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v > math.MaxInt8 && v <= math.MaxUint16:
+		return appendUint16(append(b, 0xcd), uint16(v))
+	case v > math.MaxInt8 && v <= math.MaxUint32:
+		return appendUint32(append(b, 0xce), uint32(v))
+	case v > math.MaxInt8:
+		return appendUint64(append(b, 0xcf), uint64(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return appendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return appendUint32(append(b, 0xd2), uint32(v))
+	default:
+		return appendUint64(append(b, 0xd3), uint64(v))
+	}
+}
+
+// time appends the timestamp extension type (-1) in the smallest of its forms, as the serializer of the specification
+func (msgpackEncoder) time(b []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	if sec >= 0 && sec < 1<<34 {
+		data := nsec<<34 | uint64(sec)
+		if data <= math.MaxUint32 {
+			return appendUint32(append(b, 0xd6, 0xff), uint32(data))
+		}
+		return appendUint64(append(b, 0xd7, 0xff), data)
+	}
+	return appendUint64(appendUint32(append(b, 0xc7, 12, 0xff), uint32(nsec)), uint64(sec))
+}
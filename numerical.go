@@ -0,0 +1,116 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNumericalDomain is returned (wrapped) if an input or an intermediate value is outside the numerical domain of a
+// calculation, e.g. a division by zero, instead of propagating Inf or NaN into the outputs
+var ErrNumericalDomain = errors.New("numerical domain error")
+
+// output is a named output variable of a calculation
+type output struct {
+	name  string
+	value *float64
+}
+
+// outputs returns the output variables calculated by the given functions
+func (sp *PosData) outputs(functions SPFunctions) []output {
+	var o []output
+	if functions.HasFlag(LGeom) {
+		o = append(o, output{"dayang", &sp.Dayang}, output{"erv", &sp.Erv}, output{"utime", &sp.Utime}, output{"julday", &sp.Julday},
+			output{"ectime", &sp.Ectime}, output{"mnlong", &sp.Mnlong}, output{"mnanom", &sp.Mnanom}, output{"eclong", &sp.Eclong},
+			output{"ecobli", &sp.Ecobli}, output{"declin", &sp.Declin}, output{"rascen", &sp.Rascen}, output{"gmst", &sp.Gmst},
+			output{"lmst", &sp.Lmst}, output{"hrang", &sp.Hrang})
+	}
+	if functions.HasFlag(LZenetr) {
+		o = append(o, output{"zenetr", &sp.Zenetr}, output{"elevetr", &sp.Elevetr})
+	}
+	if functions.HasFlag(LSsha) {
+		o = append(o, output{"ssha", &sp.Ssha})
+	}
+	if functions.HasFlag(LSbcf) {
+		o = append(o, output{"sbcf", &sp.Sbcf})
+	}
+	if functions.HasFlag(LTst) {
+		o = append(o, output{"tst", &sp.Tst}, output{"tstfix", &sp.Tstfix}, output{"eqntim", &sp.Eqntim})
+	}
+	if functions.HasFlag(LSrss) {
+		o = append(o, output{"sretr", &sp.Sretr}, output{"ssetr", &sp.Ssetr})
+	}
+	if functions.HasFlag(LSolazm) {
+		o = append(o, output{"azim", &sp.Azim})
+	}
+	if functions.HasFlag(LRefrac) {
+		/* zenref is left out, it is an input of the airmass calculation without refraction */
+		o = append(o, output{"elevref", &sp.Elevref}, output{"coszen", &sp.Coszen})
+	}
+	if functions.HasFlag(LAmass) {
+		o = append(o, output{"amass", &sp.Amass}, output{"ampress", &sp.Ampress})
+	}
+	if functions.HasFlag(LPrime) {
+		o = append(o, output{"prime", &sp.Prime}, output{"unprime", &sp.Unprime})
+	}
+	if functions.HasFlag(LEtr) {
+		o = append(o, output{"etr", &sp.Etr}, output{"etrn", &sp.Etrn})
+	}
+	if functions.HasFlag(LTilt) {
+		o = append(o, output{"cosinc", &sp.Cosinc}, output{"etrtilt", &sp.Etrtilt})
+	}
+	return o
+}
+
+// finite returns an error wrapping ErrNumericalDomain if value is NaN or infinite
+func finite(name string, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return wrap(ErrNumericalDomain, "Please fix "+name+", not a finite number")
+	}
+	return nil
+}
+
+// checkInputs rejects NaN and infinite inputs, which pass all range checks of validate
+func (sp *PosData) checkInputs() error {
+	inputs := []output{{"latitude", &sp.Latitude}, {"longitude", &sp.Longitude}, {"timezone", &sp.Timezone}, {"press", &sp.Press},
+		{"temp", &sp.Temp}, {"tilt", &sp.Tilt}, {"aspect", &sp.Aspect}, {"solcon", &sp.Solcon}, {"sbwid", &sp.Sbwid},
+		{"sbrad", &sp.Sbrad}, {"sbsky", &sp.Sbsky}, {"deltat", &sp.DeltaT}}
+	if !sp.Function.HasFlag(LRefrac) {
+		inputs = append(inputs, output{"zenref", &sp.Zenref})
+	}
+	for _, i := range inputs {
+		err := finite(i.name, *i.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkOutputs returns an error wrapping ErrNumericalDomain naming the first calculated output which is NaN or infinite
+func (sp *PosData) checkOutputs() error {
+	for _, o := range sp.outputs(sp.Function & outputFunctions) {
+		if math.IsNaN(*o.value) || math.IsInf(*o.value, 0) {
+			return wrap(ErrNumericalDomain, o.name+" is not a finite number")
+		}
+	}
+	return nil
+}
+
+// wrappedError adds a message to an error, errors.Is and errors.As see the wrapped error
+type wrappedError struct {
+	msg string
+	err error
+}
+
+func (w *wrappedError) Error() string {
+	return w.msg + ": " + w.err.Error()
+}
+
+func (w *wrappedError) Unwrap() error {
+	return w.err
+}
+
+// wrap annotates err with msg, like fmt.Errorf with %w but without fmt
+func wrap(err error, msg string) error {
+	return &wrappedError{msg: msg, err: err}
+}
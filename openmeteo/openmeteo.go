@@ -0,0 +1,150 @@
+// Package openmeteo fetches surface pressure and temperature from the Open-Meteo weather API
+// (https://open-meteo.com) as a solpos.AtmosphereProvider, improving the refraction correction and the
+// pressure-corrected airmass for sites without local instrumentation.
+//
+// The hourly values of a time range are fetched once and interpolated for every calculation:
+//
+//	atm, err := openmeteo.Fetch(ctx, latitude, longitude, start, end)
+//	if err == nil {
+//		sp.SetAtmosphere(atm)
+//	}
+//
+// Please respect the terms of use of Open-Meteo, the free API is for non-commercial use.
+package openmeteo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/maltegrosse/go-solpos"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// ForecastURL is the endpoint of recent (about the last 3 months) and forecast values
+	ForecastURL = "https://api.open-meteo.com/v1/forecast"
+	// ArchiveURL is the endpoint of historical (reanalysis) values
+	ArchiveURL = "https://archive-api.open-meteo.com/v1/archive"
+)
+
+// archiveAge is the age of the start of a range from which the archive is queried instead of the forecast endpoint
+const archiveAge = 80 * 24 * time.Hour
+
+// Client queries the Open-Meteo API
+type Client struct {
+	HTTPClient  *http.Client // HTTP client, DEFAULT (nil) = http.DefaultClient
+	ForecastURL string       // Forecast endpoint, DEFAULT ("") = ForecastURL, e.g. a self-hosted instance
+	ArchiveURL  string       // Archive endpoint, DEFAULT ("") = ArchiveURL
+}
+
+// Atmosphere are hourly surface pressure and temperature of a site, interpolated linearly between the hours and
+// constant beyond the fetched range. It implements solpos.AtmosphereProvider.
+type Atmosphere struct {
+	times []time.Time
+	press []float64
+	temp  []float64
+}
+
+var _ solpos.AtmosphereProvider = (*Atmosphere)(nil)
+
+// Fetch fetches the hourly surface pressure and 2 m temperature of a location from start to end with the default client
+func Fetch(ctx context.Context, latitude float64, longitude float64, start time.Time, end time.Time) (*Atmosphere, error) {
+	var c Client
+	return c.Fetch(ctx, latitude, longitude, start, end)
+}
+
+// Fetch fetches the hourly surface pressure and 2 m temperature of a location from start to end, from the archive
+// if the range begins more than about 80 days ago and from the forecast endpoint otherwise
+func (c *Client) Fetch(ctx context.Context, latitude float64, longitude float64, start time.Time, end time.Time) (*Atmosphere, error) {
+	if end.Before(start) {
+		return nil, errors.New("Please fix end, must not be before start")
+	}
+	endpoint := c.ForecastURL
+	if endpoint == "" {
+		endpoint = ForecastURL
+	}
+	if time.Since(start) > archiveAge {
+		endpoint = c.ArchiveURL
+		if endpoint == "" {
+			endpoint = ArchiveURL
+		}
+	}
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(latitude, 'f', -1, 64))
+	query.Set("longitude", strconv.FormatFloat(longitude, 'f', -1, 64))
+	query.Set("hourly", "surface_pressure,temperature_2m")
+	/* whole UTC days, the hours around the range are needed for the interpolation */
+	query.Set("start_date", start.UTC().Add(-time.Hour).Format("2006-01-02"))
+	query.Set("end_date", end.UTC().Add(time.Hour).Format("2006-01-02"))
+	query.Set("timeformat", "unixtime")
+	query.Set("timezone", "GMT")
+
+	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Reason string `json:"reason"`
+		Hourly struct {
+			Time            []int64    `json:"time"`
+			SurfacePressure []*float64 `json:"surface_pressure"`
+			Temperature     []*float64 `json:"temperature_2m"`
+		} `json:"hourly"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusOK {
+		if err == nil && body.Reason != "" {
+			return nil, errors.New("open-meteo: " + body.Reason)
+		}
+		return nil, errors.New("open-meteo: " + resp.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+	h := body.Hourly
+	if len(h.SurfacePressure) != len(h.Time) || len(h.Temperature) != len(h.Time) {
+		return nil, errors.New("open-meteo: inconsistent hourly series")
+	}
+	a := &Atmosphere{}
+	for i, t := range h.Time {
+		/* missing values (null) are skipped, the neighbors are interpolated */
+		if h.SurfacePressure[i] == nil || h.Temperature[i] == nil {
+			continue
+		}
+		a.times = append(a.times, time.Unix(t, 0).UTC())
+		a.press = append(a.press, *h.SurfacePressure[i])
+		a.temp = append(a.temp, *h.Temperature[i])
+	}
+	if len(a.times) == 0 {
+		return nil, errors.New("open-meteo: no values for the range")
+	}
+	return a, nil
+}
+
+// Atmosphere returns the surface pressure (millibars = hPa) and temperature (degrees C) at t
+func (a *Atmosphere) Atmosphere(t time.Time) (press float64, temp float64) {
+	i := sort.Search(len(a.times), func(i int) bool { return a.times[i].After(t) })
+	if i == 0 {
+		return a.press[0], a.temp[0]
+	}
+	if i == len(a.times) {
+		return a.press[i-1], a.temp[i-1]
+	}
+	w := float64(t.Sub(a.times[i-1])) / float64(a.times[i].Sub(a.times[i-1]))
+	return a.press[i-1] + w*(a.press[i]-a.press[i-1]), a.temp[i-1] + w*(a.temp[i]-a.temp[i-1])
+}
@@ -0,0 +1,202 @@
+package solpos
+
+import "time"
+
+// Options are the optional parameters of NewSolposWithOptions, the typed counterpart of the map of NewSolpos. Fields
+// left at their zero value (nil for the pointers) take the defaults of NewSolpos, so Options{Tilt: 30} only changes the tilt.
+type Options struct {
+	Press           *Millibars         // Surface pressure, nil = 1013, or the barometric pressure of Altitude
+	Temp            *Celsius           // Ambient dry-bulb temperature, nil = 15
+	Tilt            Degrees            // Tilt of the surface from horizontal, DEFAULT = 0
+	Aspect          *Degrees           // Direction the surface faces, N=0, E=90, S=180, W=270, nil = 180
+	ShadowBand      *ShadowBand        // Shadow band dimensions (Sbwid, Sbrad) and sky factor (Sbsky), nil = ShadowBandEppley
+	Month           int                // Month overriding the month of dt, 0 = month of dt
+	Day             int                // Day of month overriding the day of dt, 0 = day of dt
+	Function        SPFunctions        // Functions to calculate, 0 = SAll
+	ShadowBandModel ShadowBandModel    // Shadow-band correction model, nil = DrummondModel
+	TSI             TSI                // Source of the solar constant by date, nil = 1367 W/sq m
+	Atmosphere      AtmosphereProvider // Source of Press and Temp by date, nil = Press and Temp
+	EventFormat     EventFormat        // Representation of event times of GetSunriseAs and GetSunsetAs, DEFAULT = EventTime
+	YearPolicy      YearPolicy         // Handling of years outside 1950-2050, DEFAULT = YearError
+	Calendar        Calendar           // Calendar of the date inputs, DEFAULT = CalendarGregorian
+	DualAxis        *DualAxis          // Dual-axis tracker setting Tilt and Aspect in every calculation, nil = Tilt and Aspect
+	Horizon         HorizonProfile     // Local horizon line (terrain, buildings), nil = the flat horizon
+	Altitude        float64            // Altitude of the observer above sea level, meters, with the default Press its barometric pressure, DEFAULT = 0
+	Algorithm       Algorithm          // Solar position algorithm, DEFAULT = AlgorithmSOLPOS
+	DeltaT          float64            // TT - UT1, seconds, DEFAULT = 0
+	DeltaTSource    DeltaTProvider     // Source of DeltaT by date, nil = DeltaT
+	Precise         bool               // Nutation, annual aberration and parallax of AlgorithmSOLPOS, DEFAULT = false
+	Strict          bool               // Outputs of functions not enabled are NaN instead of stale values, DEFAULT = false
+}
+
+// DefaultOptions returns the options of NewSolpos without optional parameters, all functions and the defaults of the
+// unset fields
+func DefaultOptions() Options {
+	return Options{Function: SAll}
+}
+
+// NewSolposWithOptions creates new instance of Solpos like NewSolpos, with typed optional parameters
+func NewSolposWithOptions(dt time.Time, latitude float64, longitude float64, options Options) (Solpos, error) {
+	var sp PosData
+	sp.setTrigdata(trigdata{1.0, 1.0, 1.0, -999.0, 1.0})
+	sp.Init()
+	sp.Latitude = latitude
+	sp.Longitude = longitude
+	/* the date fields of dt are set in the calendar */
+	sp.Calendar = options.Calendar
+	sp.SetDate(dt)
+	if options.Press != nil {
+		sp.Press = float64(*options.Press)
+	}
+	if options.Temp != nil {
+		sp.Temp = float64(*options.Temp)
+	}
+	sp.Tilt = float64(options.Tilt)
+	if options.Aspect != nil {
+		sp.Aspect = float64(*options.Aspect)
+	}
+	if options.ShadowBand != nil {
+		sp.SetShadowBand(*options.ShadowBand)
+	}
+	if options.Month != 0 {
+		sp.Month = options.Month
+	}
+	if options.Day != 0 {
+		sp.Day = options.Day
+	}
+	if options.Function != 0 {
+		sp.Function = options.Function
+	}
+	sp.ShadowBandModel = options.ShadowBandModel
+	sp.TSI = options.TSI
+	sp.Atmosphere = options.Atmosphere
+	sp.EventFormat = options.EventFormat
+	sp.YearPolicy = options.YearPolicy
+	sp.DualAxis = options.DualAxis
+	sp.Horizon = options.Horizon
+	sp.Algorithm = options.Algorithm
+	sp.DeltaT = options.DeltaT
+	sp.DeltaTSource = options.DeltaTSource
+	sp.Precise = options.Precise
+	sp.Strict = options.Strict
+	sp.Altitude = options.Altitude
+	if options.Altitude != 0.0 && options.Press == nil {
+		/* the barometric pressure of the altitude, unless the pressure is set as well */
+		sp.SetAltitude(options.Altitude)
+	}
+	return &sp, sp.Calculate()
+}
+
+// Option sets an optional parameter of New
+type Option func(*Options)
+
+// New creates new instance of Solpos like NewSolpos, with functional options applied to DefaultOptions, e.g.
+// New(dt, latitude, longitude, WithPressure(1006), WithTilt(33.65), WithFunction(SAmass))
+func New(dt time.Time, latitude float64, longitude float64, options ...Option) (Solpos, error) {
+	o := DefaultOptions()
+	for _, option := range options {
+		option(&o)
+	}
+	return NewSolposWithOptions(dt, latitude, longitude, o)
+}
+
+// WithPressure sets the surface pressure
+func WithPressure(press Millibars) Option {
+	return func(o *Options) { o.Press = &press }
+}
+
+// WithTemperature sets the ambient dry-bulb temperature
+func WithTemperature(temp Celsius) Option {
+	return func(o *Options) { o.Temp = &temp }
+}
+
+// WithTilt sets the tilt of the surface from horizontal
+func WithTilt(tilt Degrees) Option {
+	return func(o *Options) { o.Tilt = tilt }
+}
+
+// WithAspect sets the direction the surface faces, N=0, E=90, S=180, W=270
+func WithAspect(aspect Degrees) Option {
+	return func(o *Options) { o.Aspect = &aspect }
+}
+
+// WithShadowBand sets the shadow band dimensions and sky factor, e.g. a preset
+func WithShadowBand(band ShadowBand) Option {
+	return func(o *Options) { o.ShadowBand = &band }
+}
+
+// WithFunction sets the functions to calculate
+func WithFunction(function SPFunctions) Option {
+	return func(o *Options) { o.Function = function }
+}
+
+// WithShadowBandModel sets the shadow-band correction model
+func WithShadowBandModel(model ShadowBandModel) Option {
+	return func(o *Options) { o.ShadowBandModel = model }
+}
+
+// WithTSI sets the source of the solar constant by date
+func WithTSI(tsi TSI) Option {
+	return func(o *Options) { o.TSI = tsi }
+}
+
+// WithAtmosphere sets the source of pressure and temperature by date
+func WithAtmosphere(atmosphere AtmosphereProvider) Option {
+	return func(o *Options) { o.Atmosphere = atmosphere }
+}
+
+// WithEventFormat sets the representation of event times of GetSunriseAs and GetSunsetAs
+func WithEventFormat(format EventFormat) Option {
+	return func(o *Options) { o.EventFormat = format }
+}
+
+// WithYearPolicy sets the handling of years outside 1950-2050
+func WithYearPolicy(policy YearPolicy) Option {
+	return func(o *Options) { o.YearPolicy = policy }
+}
+
+// WithCalendar sets the calendar of the date inputs
+func WithCalendar(calendar Calendar) Option {
+	return func(o *Options) { o.Calendar = calendar }
+}
+
+// WithDualAxis sets a dual-axis tracker setting Tilt and Aspect in every calculation
+func WithDualAxis(tracker *DualAxis) Option {
+	return func(o *Options) { o.DualAxis = tracker }
+}
+
+// WithHorizon sets the local horizon line of the site
+func WithHorizon(profile HorizonProfile) Option {
+	return func(o *Options) { o.Horizon = profile }
+}
+
+// WithAltitude sets the altitude of the observer above sea level in meters, and the pressure to its barometric pressure
+// unless WithPressure is given as well
+func WithAltitude(altitude float64) Option {
+	return func(o *Options) { o.Altitude = altitude }
+}
+
+// WithAlgorithm sets the solar position algorithm, e.g. AlgorithmSPA
+func WithAlgorithm(algorithm Algorithm) Option {
+	return func(o *Options) { o.Algorithm = algorithm }
+}
+
+// WithDeltaT sets Delta T (TT - UT1) in seconds
+func WithDeltaT(deltaT float64) Option {
+	return func(o *Options) { o.DeltaT = deltaT }
+}
+
+// WithDeltaTSource sets the source of Delta T by date, e.g. DeltaTModel
+func WithDeltaTSource(provider DeltaTProvider) Option {
+	return func(o *Options) { o.DeltaTSource = provider }
+}
+
+// WithPrecise sets the apparent coordinates with nutation, annual aberration and parallax of AlgorithmSOLPOS
+func WithPrecise(precise bool) Option {
+	return func(o *Options) { o.Precise = precise }
+}
+
+// WithStrict sets the strict mode, outputs of functions not enabled are NaN instead of stale values
+func WithStrict(strict bool) Option {
+	return func(o *Options) { o.Strict = strict }
+}
@@ -0,0 +1,61 @@
+package solpos
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptionsDefaults(t *testing.T) {
+	dt := time.Date(2021, 6, 1, 7, 30, 0, 0, time.UTC)
+	want, err := NewSolpos(dt, 52.52, 13.40, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, options := range []Options{{}, {Function: SAll}, DefaultOptions()} {
+		sp, err := NewSolposWithOptions(dt, 52.52, 13.40, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sp.GetResult(), want.GetResult()) {
+			t.Errorf("%+v: result = %+v, want %+v", options, sp.GetResult(), want.GetResult())
+		}
+	}
+}
+
+func TestOptionsPartial(t *testing.T) {
+	dt := time.Date(2021, 6, 1, 7, 30, 0, 0, time.UTC)
+	want, err := NewSolpos(dt, 52.52, 13.40, map[string]interface{}{"tilt": 30.0, "temp": 0.0, "aspect": 0.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	temp, aspect := Celsius(0), Degrees(0)
+	sp, err := NewSolposWithOptions(dt, 52.52, 13.40, Options{Function: SAll, Tilt: 30, Temp: &temp, Aspect: &aspect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sp.GetResult(), want.GetResult()) {
+		t.Errorf("result = %+v, want %+v", sp.GetResult(), want.GetResult())
+	}
+	if sp.GetPress() != 1013.0 || sp.GetSbwid() != ShadowBandEppley.Width {
+		t.Errorf("press, sbwid = %v, %v, want the defaults", sp.GetPress(), sp.GetSbwid())
+	}
+}
+
+func TestOptionsAltitude(t *testing.T) {
+	dt := time.Date(2021, 6, 1, 7, 30, 0, 0, time.UTC)
+	sp, err := New(dt, 39.74, -105.18, WithAltitude(1829))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.GetPress() >= 1013.0 {
+		t.Errorf("press = %v, want the barometric pressure of the altitude", sp.GetPress())
+	}
+	sp, err = New(dt, 39.74, -105.18, WithAltitude(1829), WithPressure(1006))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.GetPress() != 1006.0 {
+		t.Errorf("press = %v, want 1006", sp.GetPress())
+	}
+}
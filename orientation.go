@@ -0,0 +1,102 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// OrientationConfig configures OrientationSunHours
+type OrientationConfig struct {
+	Tilt               float64                // Tilt of the surfaces from horizontal, degrees
+	Orientations       int                    // Number of aspects, equally spaced starting at north (N=0, E=90, S=180, W=270), DEFAULT (0) = 8
+	Year               int                    // Year to summarize, DEFAULT (0) = current year
+	Step               time.Duration          // Integration step, DEFAULT (0) = 15 minutes
+	LinkeTurbidity     float64                // Linke turbidity of the clear sky (see ClearSkyIneichen), DEFAULT (0) = 3
+	Altitude           float64                // Altitude of the site, meters
+	Albedo             float64                // Ground reflectance, 0 = no reflected irradiance
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos, e.g. "press"
+}
+
+// OrientationSummary is the sun exposure of a surface with one of the aspects of OrientationSunHours
+type OrientationSummary struct {
+	Aspect           float64     // Direction the surface faces, N=0, E=90, S=180, W=270
+	SunHours         [12]float64 // Hours of direct sun on the surface (sun above the horizon and in front of the surface), January to December
+	Insolation       [12]float64 // Clear-sky plane-of-array insolation, kWh/sq m, January to December
+	AnnualSunHours   float64     // Hours of direct sun on the surface in the year
+	AnnualInsolation float64     // Clear-sky plane-of-array insolation in the year, kWh/sq m
+}
+
+// OrientationSunHours reports the monthly and annual hours of direct sun and clear-sky insolation (Ineichen-Perez,
+// isotropic sky) at a site for surfaces with the configured tilt facing each of the configured aspects, e.g. to compare
+// roof faces when planning an installation. Obstructions of the horizon are not accounted for.
+func OrientationSunHours(site Site, config OrientationConfig) ([]OrientationSummary, error) {
+	n := config.Orientations
+	if n == 0 {
+		n = 8
+	}
+	if n < 0 {
+		return nil, errors.New("Please fix orientations, must not be negative")
+	}
+	step := config.Step
+	if step == 0 {
+		step = 15 * time.Minute
+	}
+	if step < 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	linke := config.LinkeTurbidity
+	if linke == 0.0 {
+		linke = 3.0
+	}
+	year := config.Year
+	if year == 0 {
+		year = time.Now().In(site.location()).Year()
+	}
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, site.location())
+	end := time.Date(year+1, 1, 1, 0, 0, 0, 0, site.location())
+	sp, err := NewSolpos(start, site.Latitude, site.Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]OrientationSummary, n)
+	normals := make([][3]float64, n)
+	for i := range summaries {
+		summaries[i].Aspect = 360.0 * float64(i) / float64(n)
+		normals[i] = SurfaceNormal(config.Tilt, summaries[i].Aspect)
+	}
+	costilt := math.Cos(raddeg * config.Tilt)
+	hours := step.Hours()
+	/* midpoint rule, every step is represented by its middle */
+	for dt := start.Add(step / 2); dt.Before(end); dt = dt.Add(step) {
+		sp.SetDate(dt)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		r := sp.GetResult()
+		if r.Zenref >= 90.0 {
+			continue
+		}
+		cs := ClearSkyIneichen(r, linke, config.Altitude)
+		month := dt.Month() - 1
+		for i := range summaries {
+			s, err := IncidenceOnSurface(r, normals[i])
+			if err != nil {
+				return nil, err
+			}
+			if s.Cosinc > 0.0 {
+				summaries[i].SunHours[month] += hours
+			}
+			summaries[i].Insolation[month] += isotropicPOA(cs, s.Cosinc, costilt, config.Albedo) * hours / 1000.0
+		}
+	}
+	for i := range summaries {
+		for month := 0; month < 12; month++ {
+			summaries[i].AnnualSunHours += summaries[i].SunHours[month]
+			summaries[i].AnnualInsolation += summaries[i].Insolation[month]
+		}
+	}
+	return summaries, nil
+}
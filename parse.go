@@ -0,0 +1,63 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localLayouts are the layouts without offset accepted by ParseTime, interpreted in the given location
+var localLayouts = []string{"2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02T15:04:05",
+	"2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"}
+
+// ParseTime parses a time of a command line or request in one of the accepted formats:
+//
+//	RFC 3339 with offset            2021-06-21T12:30:00+02:00 (loc is ignored)
+//	date and time in loc            2021-06-21 12:30, 2021-06-21 12:30:00 (also with T)
+//	Unix seconds or milliseconds    1624271400, 1624271400.5, 1624271400000 (from 10^11 on as milliseconds)
+//	date only                       2021-06-21, at solar noon of the longitude (degrees east) on that day in loc
+//
+// Leap seconds are clamped like in ParseInLocation, loc nil is UTC.
+func ParseTime(value string, loc *time.Location, longitude float64) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("Please fix time, must not be empty")
+	}
+	if t, err := ParseInLocation(time.RFC3339Nano, value, loc); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return solarNoon(t, longitude)
+	}
+	if epoch, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "eEnNiI") && finite("time", epoch) == nil {
+		if math.Abs(epoch) >= 1e11 {
+			/* 10^11 seconds are in the year 5138, 10^11 milliseconds in 1973 */
+			epoch /= 1000.0
+		}
+		sec, frac := math.Modf(epoch)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))).In(loc), nil
+	}
+	return time.Time{}, errors.New("Please fix time " + value + ", expected RFC 3339, YYYY-MM-DD HH:MM[:SS], YYYY-MM-DD or Unix seconds/milliseconds")
+}
+
+// solarNoon returns the time of solar noon (true solar time 12:00) on the day of date at a longitude, in the location of date
+func solarNoon(date time.Time, longitude float64) (time.Time, error) {
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, date.Location())
+	/* solar noon does not depend on the latitude */
+	sp, err := NewSolpos(noon, 0.0, longitude, map[string]interface{}{"function": LGeom | LTst})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sp.GetSolarNoon()
+}
@@ -0,0 +1,49 @@
+//go:build go1.23
+
+package solpos
+
+import (
+	"errors"
+	"iter"
+	"time"
+)
+
+// Positions returns an iterator over the results from start to end (inclusive) at a fixed step, calculated one at a time
+// while ranging, e.g. for year-long simulations at a one minute step without materializing the series, and a function
+// returning the error of that sequence:
+//
+//	seq, seqErr := sp.Positions(start, end, time.Minute)
+//	for t, r := range seq { ... }
+//	if err := seqErr(); err != nil { ... }
+//
+// An invalid range or a failed calculation ends the sequence early, the error function returns the reason of the last
+// run of the sequence (nil if it ran to the end or was stopped by the caller).
+func (sp *PosData) Positions(start time.Time, end time.Time, step time.Duration) (iter.Seq2[time.Time, Result], func() error) {
+	var err error
+	seq := func(yield func(time.Time, Result) bool) {
+		points := 0
+		done := instrumentation.Start("Positions")
+		err = nil
+		defer func() { done(points, err) }()
+		if step <= 0 {
+			err = errors.New("Please fix step, must be positive")
+			return
+		}
+		if end.Before(start) {
+			err = errors.New("Please fix end, must not be before start")
+			return
+		}
+		for dt := start; !dt.After(end); dt = dt.Add(step) {
+			sp.SetDate(dt)
+			err = sp.Calculate()
+			if err != nil {
+				return
+			}
+			points++
+			if !yield(dt, sp.GetResult()) {
+				return
+			}
+		}
+	}
+	return seq, func() error { return err }
+}
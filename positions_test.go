@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package solpos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPositionsErrScoped(t *testing.T) {
+	s, err := New(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), 52.52, 13.40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp := s.(*PosData)
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	bad, badErr := sp.Positions(start, start.Add(-time.Hour), time.Minute)
+	good, goodErr := sp.Positions(start, start.Add(time.Hour), 10*time.Minute)
+	for range bad {
+		t.Fatal("invalid range yields results")
+	}
+	n := 0
+	for range good {
+		n++
+	}
+	if n != 7 {
+		t.Errorf("%d results, want 7", n)
+	}
+	if badErr() == nil {
+		t.Error("no error for an end before start")
+	}
+	if err := goodErr(); err != nil {
+		t.Errorf("error %v of a valid sequence", err)
+	}
+}
@@ -0,0 +1,37 @@
+package solpos
+
+import "math"
+
+/*============================================================================
+*    Precise mode
+*
+*    Apparent coordinates of the sun for AlgorithmSOLPOS: the coordinates of
+*    the Almanac with nutation in longitude and obliquity (IAU 1980, the 63
+*    terms of the SPA), annual aberration at the distance of the sun instead
+*    of the mean one, apparent sidereal time and the parallax of the
+*    observer. The mean longitude of the Almanac keeps its accuracy of about
+*    0.01 degrees, see AlgorithmSPA for better.
+*       Meeus, J.  1998.  Astronomical Algorithms, 2nd ed.  Willmann-Bell,
+*            Richmond, VA., chapters 22 and 23
+*----------------------------------------------------------------------------*/
+
+func (sp *PosData) SetPrecise(precise bool) {
+	sp.Precise = precise
+}
+
+func (sp *PosData) GetPrecise() bool {
+	return sp.Precise
+}
+
+// apparent turns the ecliptic coordinates of the Michalsky formulae into apparent ones and returns the equation of the
+// equinoxes (nutation in right ascension), hours, the difference of apparent and mean sidereal time
+func (sp *PosData) apparent() float64 {
+	dpsi, deps := spaNutation(sp.Ectime / 36525.0)
+	/* earth radius vector, AU (Astronomical Almanac) */
+	g := raddeg * sp.Mnanom
+	r := 1.00014 - 0.01671*math.Cos(g) - 0.00014*math.Cos(2.0*g)
+	/* the mean longitude of the Almanac includes the aberration at the mean distance */
+	sp.Eclong = limitDegrees(sp.Eclong + (20.4898-20.4898/r)/3600.0 + dpsi)
+	sp.Ecobli += deps
+	return dpsi * math.Cos(raddeg*sp.Ecobli) / 15.0
+}
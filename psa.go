@@ -0,0 +1,113 @@
+package solpos
+
+import "math"
+
+/*============================================================================
+*    PSA algorithm
+*
+*    Sun position algorithm of the Plataforma Solar de Almeria, widely used
+*    in solar thermal codes, with the coefficients of 2001 (valid 1999-2015)
+*    or of the 2020 update (valid 2020-2050), about 0.008 degrees.
+*       Blanco-Muriel, M., Alarcon-Padilla, D. C., Lopez-Moratalla, T.,
+*            Lara-Coira, M.  2001.  Computing the solar vector.  Solar
+*            Energy 70 (5), pp. 431-441
+*       Blanco, M., Milidonis, K., Bonanos, A.  2020.  Updating the PSA sun
+*            position algorithm.  Solar Energy 212, pp. 339-341
+*----------------------------------------------------------------------------*/
+
+// psaCoefficients are the coefficients of the PSA algorithm: longitude of the ascending node of the moon, mean longitude,
+// mean anomaly, ecliptic longitude, obliquity of the ecliptic (radians and radians per day) and sidereal time (hours)
+type psaCoefficients struct {
+	omega, omegaRate        float64
+	mnlong, mnlongRate      float64
+	mnanom, mnanomRate      float64
+	center, center2, offset float64
+	node                    float64
+	ecobli, ecobliRate      float64
+	ecobliNode              float64
+	gmst, gmstRate          float64
+}
+
+var (
+	psa2001 = psaCoefficients{
+		omega:      2.1429,
+		omegaRate:  -0.0010394594,
+		mnlong:     4.8950630,
+		mnlongRate: 0.017202791698,
+		mnanom:     6.2400600,
+		mnanomRate: 0.0172019699,
+		center:     0.03341607,
+		center2:    0.00034894,
+		offset:     -0.0001134,
+		node:       -0.0000203,
+		ecobli:     0.4090928,
+		ecobliRate: -6.2140e-9,
+		ecobliNode: 0.0000396,
+		gmst:       6.6974243242,
+		gmstRate:   0.0657098283,
+	}
+	psa2020 = psaCoefficients{
+		omega:      2.267127827,
+		omegaRate:  -9.300339267e-4,
+		mnlong:     4.895036035,
+		mnlongRate: 1.720279602e-2,
+		mnanom:     6.239468336,
+		mnanomRate: 1.720200135e-2,
+		center:     3.338320972e-2,
+		center2:    3.497596876e-4,
+		offset:     -1.544353226e-4,
+		node:       -8.689729360e-6,
+		ecobli:     4.090904909e-1,
+		ecobliRate: -6.213605399e-9,
+		ecobliNode: 4.418094944e-5,
+		gmst:       6.697096103,
+		gmstRate:   6.570984737e-2,
+	}
+)
+
+// psa calculates the basic geometry of Julday with the PSA algorithm: the ecliptic coordinates, declination, right
+// ascension and sidereal time of the PSA code, and the topocentric declination and hour angle like the SPA. The parallax
+// of the SPA differs from the zenith correction of the PSA code by less than 0.00001 degrees.
+func (sp *PosData) psa() {
+	k := psa2001
+	if sp.Algorithm == AlgorithmPSA2020 {
+		k = psa2020
+	}
+	/* days since 2000-01-01 12:00 UT, in terrestrial time for the ecliptic coordinates (DeltaT is 0 in the PSA code) */
+	n := sp.Julday - 51545.0
+	nt := n + sp.DeltaT/86400.0
+	omega := k.omega + k.omegaRate*nt
+	mnlong := k.mnlong + k.mnlongRate*nt
+	mnanom := k.mnanom + k.mnanomRate*nt
+	eclong := mnlong + k.center*math.Sin(mnanom) + k.center2*math.Sin(2.0*mnanom) + k.offset + k.node*math.Sin(omega)
+	ecobli := k.ecobli + k.ecobliRate*nt + k.ecobliNode*math.Cos(omega)
+
+	sl, cl := math.Sincos(eclong)
+	ra := math.Atan2(math.Cos(ecobli)*sl, cl)
+	if ra < 0.0 {
+		ra += 2.0 * math.Pi
+	}
+	dec := math.Asin(math.Sin(ecobli) * sl)
+
+	sp.Ectime = nt
+	sp.Mnlong = limitDegrees(degrad * mnlong)
+	sp.Mnanom = limitDegrees(degrad * mnanom)
+	sp.Eclong = limitDegrees(degrad * eclong)
+	sp.Ecobli = degrad * ecobli
+	sp.Gmst = limitDegrees(15.0*(k.gmst+k.gmstRate*n+sp.Utime)) / 15.0
+	sp.Lmst = limitDegrees(sp.Gmst*15.0 + sp.Longitude)
+	h := limitDegrees(sp.Lmst - degrad*ra)
+
+	/* parallax of the observer at the mean distance of the sun */
+	dalpha, deltaPrime := sp.parallax(degrad*dec, h, 1.0)
+	sp.Declin = deltaPrime
+	sp.Rascen = limitDegrees(degrad*ra + dalpha)
+	sp.Hrang = h - dalpha
+	/* (force it between -180 and 180 degrees) */
+	if sp.Hrang > 180.0 {
+		sp.Hrang -= 360.0
+	}
+	if sp.Hrang < -180.0 {
+		sp.Hrang += 360.0
+	}
+}
@@ -0,0 +1,50 @@
+package pv
+
+import (
+	"errors"
+	"github.com/maltegrosse/go-solpos"
+	"strconv"
+)
+
+// moduleCapacity is the DC capacity per module area of planes without a capacity (20 % module efficiency), kW/sq m
+const moduleCapacity = 0.2
+
+// Plane is one roof plane of a system with modules in several orientations
+type Plane struct {
+	Name     string  // Name of the plane, e.g. "south roof"
+	Tilt     float64 // Tilt of the plane from horizontal, degrees
+	Aspect   float64 // Direction the plane faces, N=0, E=90, S=180, W=270, DEFAULT (0) = 180
+	Area     float64 // Module area on the plane, sq m
+	Capacity float64 // DC capacity of the modules on the plane, kW, DEFAULT (0) = Area times 0.2 kW/sq m
+}
+
+// SimulatePlanes returns the output of every plane and the total of a system with modules on several roof planes,
+// calculating the sun position of every weather interval only once. Tilt, Aspect and Capacity of the system are
+// replaced by those of the planes, the other settings apply to all planes. The planes share the inverter, its
+// capacity limits the total AC power, clipping is distributed proportionally to the planes. POA and cell temperature
+// of the total are means weighted by the capacity of the planes.
+func SimulatePlanes(site solpos.Site, system System, planes []Plane, weather []Weather, optionalParameters map[string]interface{}) ([]Yield, Yield, error) {
+	if len(planes) == 0 {
+		return nil, Yield{}, errors.New("Please fix planes, at least one is required")
+	}
+	systems := make([]System, len(planes))
+	for k, p := range planes {
+		if p.Area < 0.0 || p.Capacity < 0.0 {
+			return nil, Yield{}, errors.New("Please fix area and capacity of plane " + strconv.Itoa(k) + ", must not be negative")
+		}
+		s := system
+		s.Tilt = p.Tilt
+		s.Aspect = p.Aspect
+		s.Capacity = p.Capacity
+		if s.Capacity == 0.0 {
+			s.Capacity = p.Area * moduleCapacity
+		}
+		s = s.defaults()
+		err := s.validate()
+		if err != nil {
+			return nil, Yield{}, err
+		}
+		systems[k] = s
+	}
+	return simulate(site, systems, system.InverterCapacity, weather, optionalParameters)
+}
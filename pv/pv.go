@@ -0,0 +1,252 @@
+// Package pv estimates the energy yield of a photovoltaic system in the spirit of PVWatts: irradiance (clear-sky or
+// supplied) is transposed to the plane of array, reduced by the incidence angle modifier, converted to DC power with
+// a temperature corrected linear model and to AC power with a constant inverter efficiency.
+//
+// The model is meant for quick estimates, e.g. by small installers, not for bankable yield assessments.
+package pv
+
+import (
+	"errors"
+	"github.com/maltegrosse/go-solpos"
+	"math"
+	"time"
+)
+
+/*============================================================================
+*    Models
+*
+*    Cell temperature from the nominal operating cell temperature (NOCT),
+*    incidence angle modifier (ASHRAE) and derate factors following
+*       Dobos, A. P.  2014.  PVWatts Version 5 Manual.  NREL/TP-6A20-62641
+*       Souka, A. F., Safwat, H. H.  1966.  Determination of the optimum
+*            orientations for the double exposure flat-plate collector and its
+*            reflections.  Solar Energy 10 (4), pp. 170-174
+*----------------------------------------------------------------------------*/
+
+const raddeg = math.Pi / 180.0 /* converts from degrees to radians */
+
+// System describes a PV system, zero values select the defaults
+type System struct {
+	Capacity           float64 // DC capacity at standard test conditions (1000 W/sq m, 25 C), kW
+	Tilt               float64 // Tilt of the modules from horizontal, degrees
+	Aspect             float64 // Direction the modules face, N=0, E=90, S=180, W=270, DEFAULT (0) = 180
+	Losses             float64 // DC losses (soiling, wiring, mismatch, availability) as a fraction, DEFAULT (0) = 0.14
+	TempCoefficient    float64 // Power temperature coefficient per degree C, DEFAULT (0) = -0.0037
+	NOCT               float64 // Nominal operating cell temperature, degrees C, DEFAULT (0) = 45
+	IAMCoefficient     float64 // ASHRAE incidence angle modifier coefficient b0, DEFAULT (0) = 0.05
+	InverterEfficiency float64 // Inverter efficiency, DEFAULT (0) = 0.96
+	InverterCapacity   float64 // AC capacity of the inverter, kW, DEFAULT (0) = unlimited (no clipping)
+	Albedo             float64 // Ground reflectance, DEFAULT (0) = 0.2
+}
+
+// defaults returns the system with the defaults applied
+func (s System) defaults() System {
+	if s.Aspect == 0.0 {
+		s.Aspect = 180.0
+	}
+	if s.Losses == 0.0 {
+		s.Losses = 0.14
+	}
+	if s.TempCoefficient == 0.0 {
+		s.TempCoefficient = -0.0037
+	}
+	if s.NOCT == 0.0 {
+		s.NOCT = 45.0
+	}
+	if s.IAMCoefficient == 0.0 {
+		s.IAMCoefficient = 0.05
+	}
+	if s.InverterEfficiency == 0.0 {
+		s.InverterEfficiency = 0.96
+	}
+	if s.Albedo == 0.0 {
+		s.Albedo = 0.2
+	}
+	return s
+}
+
+func (s System) validate() error {
+	if s.Capacity <= 0.0 {
+		return errors.New("Please fix capacity, must be positive")
+	}
+	if s.Tilt < 0.0 || s.Tilt > 180.0 {
+		return errors.New("Please fix tilt [0-180]")
+	}
+	if s.Aspect < 0.0 || s.Aspect > 360.0 {
+		return errors.New("Please fix aspect [0-360]")
+	}
+	if s.Losses < 0.0 || s.Losses >= 1.0 {
+		return errors.New("Please fix losses [0-1)")
+	}
+	if s.InverterEfficiency <= 0.0 || s.InverterEfficiency > 1.0 {
+		return errors.New("Please fix inverter efficiency (0-1]")
+	}
+	if s.InverterCapacity < 0.0 {
+		return errors.New("Please fix inverter capacity, must not be negative")
+	}
+	return nil
+}
+
+// Weather is the irradiance and temperature of an interval
+type Weather struct {
+	Time        time.Time     // Middle of the interval, the sun position is calculated for it
+	Duration    time.Duration // Length of the interval, DEFAULT (0) = 1 hour
+	GHI         float64       // Global horizontal irradiance, W/sq m
+	DNI         float64       // Direct normal irradiance, W/sq m
+	DHI         float64       // Diffuse horizontal irradiance, W/sq m
+	Temperature float64       // Ambient dry-bulb temperature, degrees C
+}
+
+// Interval is the output of the system for one weather interval
+type Interval struct {
+	Time            time.Time // Middle of the interval
+	POA             float64   // Plane-of-array irradiance after the incidence angle modifier, W/sq m
+	CellTemperature float64   // Cell temperature, degrees C
+	DC              float64   // DC power, kW
+	AC              float64   // AC power, kW
+	Energy          float64   // AC energy of the interval, kWh
+}
+
+// Yield is the output of the system over a simulation
+type Yield struct {
+	Intervals []Interval // Output of every weather interval
+	DCEnergy  float64    // DC energy, kWh
+	Energy    float64    // AC energy, kWh
+}
+
+// ClearSkyWeather returns weather intervals of the given step from start to end with the clear-sky irradiance
+// (Ineichen-Perez, see solpos.ClearSkyIneichen) at the site and a constant ambient temperature. The optional
+// parameters are the same as for solpos.NewSolpos.
+func ClearSkyWeather(site solpos.Site, start time.Time, end time.Time, step time.Duration, linkeTurbidity float64, altitude float64,
+	temperature float64, optionalParameters map[string]interface{}) ([]Weather, error) {
+	if step <= 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	if end.Before(start) {
+		return nil, errors.New("Please fix end, must not be before start")
+	}
+	sp, err := solpos.NewSolpos(start, site.Latitude, site.Longitude, optionalParameters)
+	if err != nil {
+		return nil, err
+	}
+	var weather []Weather
+	/* midpoint rule, every step is represented by its middle */
+	for dt := start.Add(step / 2); dt.Before(end); dt = dt.Add(step) {
+		sp.SetDate(dt)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		cs := solpos.ClearSkyIneichen(sp.GetResult(), linkeTurbidity, altitude)
+		weather = append(weather, Weather{Time: dt, Duration: step, GHI: cs.GHI, DNI: cs.DNI, DHI: cs.DHI, Temperature: temperature})
+	}
+	return weather, nil
+}
+
+// Simulate returns the output of the system at the site for the weather intervals. The optional parameters
+// are the same as for solpos.NewSolpos.
+func Simulate(site solpos.Site, system System, weather []Weather, optionalParameters map[string]interface{}) (Yield, error) {
+	system = system.defaults()
+	err := system.validate()
+	if err != nil {
+		return Yield{}, err
+	}
+	_, total, err := simulate(site, []System{system}, system.InverterCapacity, weather, optionalParameters)
+	return total, err
+}
+
+// simulate calculates the sun position of every weather interval once for all systems, which share an inverter
+// clipping their summed AC power at inverterCapacity (0 = unlimited). It returns the yield of every system and the total.
+func simulate(site solpos.Site, systems []System, inverterCapacity float64, weather []Weather, optionalParameters map[string]interface{}) ([]Yield, Yield, error) {
+	yields := make([]Yield, len(systems))
+	var total Yield
+	if len(weather) == 0 {
+		return yields, total, nil
+	}
+	sp, err := solpos.NewSolpos(weather[0].Time, site.Latitude, site.Longitude, optionalParameters)
+	if err != nil {
+		return nil, Yield{}, err
+	}
+	normals := make([][3]float64, len(systems))
+	capacity := 0.0
+	for k, system := range systems {
+		capacity += system.Capacity
+		normals[k] = solpos.SurfaceNormal(system.Tilt, system.Aspect)
+		yields[k].Intervals = make([]Interval, 0, len(weather))
+	}
+	total.Intervals = make([]Interval, 0, len(weather))
+	intervals := make([]Interval, len(systems))
+	for _, w := range weather {
+		sp.SetDate(w.Time)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, Yield{}, err
+		}
+		r := sp.GetResult()
+		sum := Interval{Time: w.Time}
+		for k, system := range systems {
+			s, err := solpos.IncidenceOnSurface(r, normals[k])
+			if err != nil {
+				return nil, Yield{}, err
+			}
+			intervals[k] = system.interval(w, r, s)
+			/* POA and cell temperature of the total are weighted by capacity */
+			sum.POA += intervals[k].POA * system.Capacity / capacity
+			sum.CellTemperature += intervals[k].CellTemperature * system.Capacity / capacity
+			sum.DC += intervals[k].DC
+			sum.AC += intervals[k].AC
+		}
+		/* clipping of the shared inverter, distributed proportionally to the systems */
+		clip := 1.0
+		if inverterCapacity > 0.0 && sum.AC > inverterCapacity {
+			clip = inverterCapacity / sum.AC
+			sum.AC = inverterCapacity
+		}
+		hours := w.Duration.Hours()
+		if w.Duration == 0 {
+			hours = 1.0
+		}
+		for k := range systems {
+			i := intervals[k]
+			i.AC *= clip
+			i.Energy = i.AC * hours
+			yields[k].DCEnergy += i.DC * hours
+			yields[k].Energy += i.Energy
+			yields[k].Intervals = append(yields[k].Intervals, i)
+		}
+		sum.Energy = sum.AC * hours
+		total.DCEnergy += sum.DC * hours
+		total.Energy += sum.Energy
+		total.Intervals = append(total.Intervals, sum)
+	}
+	return yields, total, nil
+}
+
+// interval models the system for a single weather interval
+func (s System) interval(w Weather, r solpos.Result, inc solpos.SurfaceIncidence) Interval {
+	i := Interval{Time: w.Time, CellTemperature: w.Temperature}
+	if r.Zenref >= 90.0 && w.GHI <= 0.0 {
+		return i
+	}
+	/* transposition, isotropic sky */
+	costilt := math.Cos(raddeg * s.Tilt)
+	beam := 0.0
+	if inc.Cosinc > 0.0 && r.Zenref < 90.0 {
+		beam = w.DNI * inc.Cosinc * iam(inc.Cosinc, s.IAMCoefficient)
+	}
+	poa := beam + w.DHI*(1.0+costilt)/2.0 + w.GHI*s.Albedo*(1.0-costilt)/2.0
+	i.POA = poa
+	/* cell temperature, rises linearly with the irradiance (NOCT at 800 W/sq m) */
+	i.CellTemperature = w.Temperature + poa/800.0*(s.NOCT-20.0)
+	i.DC = s.Capacity * poa / 1000.0 * (1.0 + s.TempCoefficient*(i.CellTemperature-25.0)) * (1.0 - s.Losses)
+	if i.DC < 0.0 {
+		i.DC = 0.0
+	}
+	i.AC = i.DC * s.InverterEfficiency
+	return i
+}
+
+// iam returns the ASHRAE incidence angle modifier for the cosine of the incidence angle
+func iam(cosinc float64, b0 float64) float64 {
+	return math.Max(0.0, 1.0-b0*(1.0/cosinc-1.0))
+}
@@ -0,0 +1,152 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Aggregation selects how Resample combines the results of a period
+type Aggregation int
+
+const (
+	AggMean     Aggregation = iota // arithmetic mean, circular mean for azimuth and hour angle
+	AggSum                         // sum of the values
+	AggMin                         // minimum
+	AggMax                         // maximum
+	AggIntegral                    // sum of value times the time to the next result in hours, e.g. Wh/sq m from W/sq m
+)
+
+// circularColumns are the angles of Values averaged on the circle, with the upper limit of their range
+var circularColumns = map[string]float64{"azim": 360.0, "hrang": 180.0}
+
+// Resample rolls a series up to periods of the given length, aligned to local midnight of the results (the period must
+// divide a day, e.g. 15 minutes or an hour, or be a day). Each output has the time of the beginning of its period and
+// every numeric field is aggregated over the results in the period, empty periods are skipped.
+func Resample(series []Result, period time.Duration, agg Aggregation) ([]Result, error) {
+	if period <= 0 || (24*time.Hour)%period != 0 {
+		return nil, errors.New("Please fix period, must divide a day")
+	}
+	return resample(series, agg, func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		midnight := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+		return midnight.Add(t.Sub(midnight) / period * period)
+	})
+}
+
+// ResampleCalendar rolls a series up to calendar periods (local days, weeks, months or years) like Resample
+func ResampleCalendar(series []Result, chunk SeriesChunk, agg Aggregation) ([]Result, error) {
+	return resample(series, agg, func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		switch chunk {
+		case ChunkWeek:
+			/* weeks begin on Monday */
+			return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+		case ChunkMonth:
+			return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+		case ChunkYear:
+			return time.Date(y, 1, 1, 0, 0, 0, 0, t.Location())
+		default:
+			return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+		}
+	})
+}
+
+// resample aggregates consecutive results with the same bucket
+func resample(series []Result, agg Aggregation, bucket func(t time.Time) time.Time) ([]Result, error) {
+	if agg < AggMean || agg > AggIntegral {
+		return nil, errors.New("Please fix aggregation, unknown")
+	}
+	for i := 1; i < len(series); i++ {
+		if series[i].Time.Before(series[i-1].Time) {
+			return nil, errors.New("Please fix series, times must not decrease")
+		}
+	}
+	columns := resultColumns
+	rows := make([][]float64, len(series))
+	for k, r := range series {
+		rows[k] = r.Values()
+	}
+	var out []Result
+	for i := 0; i < len(series); {
+		start := bucket(series[i].Time)
+		j := i + 1
+		for j < len(series) && bucket(series[j].Time).Equal(start) {
+			j++
+		}
+		values := make([]float64, len(columns))
+		for c := range columns {
+			values[c] = aggregate(series, rows, i, j, c, agg, circularColumns[columns[c]])
+		}
+		r := Result{Time: start}
+		r.setValues(values)
+		/* the location does not change within a series of one site */
+		r.Latitude, r.Longitude = series[i].Latitude, series[i].Longitude
+		out = append(out, r)
+		i = j
+	}
+	return out, nil
+}
+
+// aggregate combines column c of the results i to j (exclusive), circular is the upper limit of the range of an angle
+// averaged on the circle, 0 for other values
+func aggregate(series []Result, rows [][]float64, i int, j int, c int, agg Aggregation, circular float64) float64 {
+	value := func(k int) float64 {
+		return rows[k][c]
+	}
+	switch agg {
+	case AggMin:
+		v := math.Inf(1)
+		for k := i; k < j; k++ {
+			v = math.Min(v, value(k))
+		}
+		return v
+	case AggMax:
+		v := math.Inf(-1)
+		for k := i; k < j; k++ {
+			v = math.Max(v, value(k))
+		}
+		return v
+	case AggSum:
+		v := 0.0
+		for k := i; k < j; k++ {
+			v += value(k)
+		}
+		return v
+	case AggIntegral:
+		v := 0.0
+		for k := i; k < j; k++ {
+			v += value(k) * step(series, k).Hours()
+		}
+		return v
+	default:
+		if circular != 0.0 {
+			var s, co float64
+			for k := i; k < j; k++ {
+				s += math.Sin(raddeg * value(k))
+				co += math.Cos(raddeg * value(k))
+			}
+			v := degrad * math.Atan2(s, co)
+			if v <= circular-360.0 {
+				v += 360.0
+			}
+			return v
+		}
+		v := 0.0
+		for k := i; k < j; k++ {
+			v += value(k)
+		}
+		return v / float64(j-i)
+	}
+}
+
+// step returns the time from result k to the next one, the previous step for the last result
+func step(series []Result, k int) time.Duration {
+	if k+1 < len(series) {
+		return series[k+1].Time.Sub(series[k].Time)
+	}
+	if k > 0 {
+		return series[k].Time.Sub(series[k-1].Time)
+	}
+	return 0
+}
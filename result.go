@@ -0,0 +1,89 @@
+package solpos
+
+import "time"
+
+// Result is a snapshot of the output (and the most useful transitional) variables of a single calculation
+type Result struct {
+	Time      time.Time `json:"time"`      // Date and time of the calculation, local
+	Latitude  float64   `json:"latitude"`  // Latitude, degrees north (south negative)
+	Longitude float64   `json:"longitude"` // Longitude, degrees east (west negative)
+	Amass     float64   `json:"amass"`     // Relative optical airmass
+	Ampress   float64   `json:"ampress"`   // Pressure-corrected airmass
+	Azim      float64   `json:"azim"`      // Solar azimuth angle:  N=0, E=90, S=180, W=270
+	Cosinc    float64   `json:"cosinc"`    // Cosine of solar incidence angle on panel
+	Coszen    float64   `json:"coszen"`    // Cosine of refraction corrected solar zenith angle
+	Declin    float64   `json:"declin"`    // Declination--zenith angle of solar noon at equator, degrees NORTH
+	Elevetr   float64   `json:"elevetr"`   // Solar elevation, no atmospheric correction (= ETR)
+	Elevref   float64   `json:"elevref"`   // Solar elevation angle, deg. from horizon, refracted
+	Eqntim    float64   `json:"eqntim"`    // Equation of time (TST - LMT), minutes
+	Etr       float64   `json:"etr"`       // Extraterrestrial (top-of-atmosphere) W/sq m global horizontal solar irradiance
+	Etrn      float64   `json:"etrn"`      // Extraterrestrial (top-of-atmosphere) W/sq m direct normal solar irradiance
+	Etrtilt   float64   `json:"etrtilt"`   // Extraterrestrial (top-of-atmosphere) W/sq m global irradiance on a tilted surface
+	Hrang     float64   `json:"hrang"`     // Hour angle--hour of sun from solar noon, degrees WEST
+	Prime     float64   `json:"prime"`     // Factor that normalizes Kt, Kn, etc.
+	Sbcf      float64   `json:"sbcf"`      // Shadow-band correction factor
+	Sretr     float64   `json:"sretr"`     // Sunrise time, minutes from midnight, local, WITHOUT refraction
+	Ssetr     float64   `json:"ssetr"`     // Sunset time, minutes from midnight, local, WITHOUT refraction
+	Ssha      float64   `json:"ssha"`      // Sunset(/rise) hour angle, degrees
+	Tstfix    float64   `json:"tstfix"`    // True solar time - local standard time
+	Unprime   float64   `json:"unprime"`   // Factor that denormalizes Kt', Kn', etc.
+	Zenetr    float64   `json:"zenetr"`    // Solar zenith angle, no atmospheric correction (= ETR)
+	Zenref    float64   `json:"zenref"`    // Solar zenith angle, deg. from zenith, refracted
+
+	SunObstructed bool      `json:"sunObstructed,omitempty"` // Refracted sun below the local horizon line (see SetHorizon)
+	Warnings      []Warning `json:"warnings,omitempty"`      // Near-degenerate conditions of the calculation
+}
+
+func (sp *PosData) GetResult() Result {
+	return Result{
+		Time:      sp.Getdate(),
+		Latitude:  sp.Latitude,
+		Longitude: sp.Longitude,
+		Amass:     sp.Amass,
+		Ampress:   sp.Ampress,
+		Azim:      sp.Azim,
+		Cosinc:    sp.Cosinc,
+		Coszen:    sp.Coszen,
+		Declin:    sp.Declin,
+		Elevetr:   sp.Elevetr,
+		Elevref:   sp.Elevref,
+		Eqntim:    sp.Eqntim,
+		Etr:       sp.Etr,
+		Etrn:      sp.Etrn,
+		Etrtilt:   sp.Etrtilt,
+		Hrang:     sp.Hrang,
+		Prime:     sp.Prime,
+		Sbcf:      sp.Sbcf,
+		Sretr:     sp.Sretr,
+		Ssetr:     sp.Ssetr,
+		Ssha:      sp.Ssha,
+		Tstfix:    sp.Tstfix,
+		Unprime:   sp.Unprime,
+		Zenetr:    sp.Zenetr,
+		Zenref:    sp.Zenref,
+		Warnings:  sp.warnings,
+
+		SunObstructed: sp.SunObstructed,
+	}
+}
+
+// resultColumns are the names of the numeric Result fields, in the order returned by Values
+var resultColumns = []string{"latitude", "longitude", "amass", "ampress", "azim", "cosinc", "coszen", "declin", "elevetr", "elevref", "eqntim",
+	"etr", "etrn", "etrtilt", "hrang", "prime", "sbcf", "sretr", "ssetr", "ssha", "tstfix", "unprime", "zenetr", "zenref"}
+
+// ResultColumns returns the names of the numeric Result fields (their JSON names), in the order of Values
+func ResultColumns() []string {
+	return append([]string(nil), resultColumns...)
+}
+
+// Values returns the numeric fields of the result, in the order of ResultColumns
+func (r Result) Values() []float64 {
+	return []float64{r.Latitude, r.Longitude, r.Amass, r.Ampress, r.Azim, r.Cosinc, r.Coszen, r.Declin, r.Elevetr, r.Elevref, r.Eqntim,
+		r.Etr, r.Etrn, r.Etrtilt, r.Hrang, r.Prime, r.Sbcf, r.Sretr, r.Ssetr, r.Ssha, r.Tstfix, r.Unprime, r.Zenetr, r.Zenref}
+}
+
+// setValues sets the numeric fields of the result, in the order of Values
+func (r *Result) setValues(v []float64) {
+	r.Latitude, r.Longitude, r.Amass, r.Ampress, r.Azim, r.Cosinc, r.Coszen, r.Declin, r.Elevetr, r.Elevref, r.Eqntim = v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7], v[8], v[9], v[10]
+	r.Etr, r.Etrn, r.Etrtilt, r.Hrang, r.Prime, r.Sbcf, r.Sretr, r.Ssetr, r.Ssha, r.Tstfix, r.Unprime, r.Zenetr, r.Zenref = v[11], v[12], v[13], v[14], v[15], v[16], v[17], v[18], v[19], v[20], v[21], v[22], v[23]
+}
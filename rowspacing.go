@@ -0,0 +1,160 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+/*============================================================================
+*    Inter-row shading
+*
+*    Shadow of a row of fixed tilted panels on the next row behind it on flat
+*    ground, in the plane perpendicular to the rows with the profile angle of
+*    the sun (the same 2D geometry as the backtracking of Tracker).
+*----------------------------------------------------------------------------*/
+
+// rowSpacingStep is the sampling interval of MinimumPitch
+const rowSpacingStep = time.Minute
+
+// RowLayout describes rows of fixed tilted panels on flat ground
+type RowLayout struct {
+	Tilt   float64 // Tilt of the panels from horizontal, degrees
+	Aspect float64 // Direction the rows face, N=0, E=90, S=180, W=270, DEFAULT (0) = 180
+	Width  float64 // Collector width, the slant length of a row from the lower to the upper edge, m
+}
+
+// RowShading is the shading of a row at one time
+type RowShading struct {
+	Time     time.Time
+	Fraction float64 // Fraction of the collector width in the shadow of the row in front
+}
+
+func (l RowLayout) aspect() float64 {
+	if l.Aspect == 0.0 {
+		return 180.0
+	}
+	return l.Aspect
+}
+
+func (l RowLayout) validate() error {
+	if !(l.Width > 0.0) {
+		return errors.New("Please fix width, must be positive")
+	}
+	if !(l.Tilt >= 0.0 && l.Tilt < 90.0) {
+		return errors.New("Please fix tilt [0 - 90)")
+	}
+	return nil
+}
+
+// profile returns the tangent of the profile angle of the sun of r in the plane perpendicular to the rows, ok is false
+// while the sun is down or behind the rows (their fronts are not lit then)
+func (l RowLayout) profile(r Result) (tan float64, ok bool) {
+	front := math.Cos(raddeg * (r.Azim - l.aspect()))
+	if r.Elevref <= 0.0 || front <= 0.0 {
+		return 0.0, false
+	}
+	return math.Tan(raddeg*r.Elevref) / front, true
+}
+
+// ShadedFraction returns the fraction of the collector width of a row shaded by the row in front for the sun position of
+// r (Elevref and Azim are used) and the given row pitch (horizontal distance from row to row, m). It is 0 while the sun
+// is down or behind the rows.
+func (l RowLayout) ShadedFraction(r Result, pitch float64) float64 {
+	tan, ok := l.profile(r)
+	if !ok {
+		return 0.0
+	}
+	st, ct := math.Sincos(raddeg * l.Tilt)
+	/* the ray over the upper edge of the front row hits the next row at the slant distance s from its lower edge */
+	s := (l.Width*st - (pitch-l.Width*ct)*tan) / (st + ct*tan)
+	return math.Max(0.0, math.Min(1.0, s/l.Width))
+}
+
+// pitch returns the row pitch at which the shadow of a row just reaches the lower edge of the next row for the sun of r
+func (l RowLayout) pitch(r Result) float64 {
+	tan, ok := l.profile(r)
+	if !ok {
+		return 0.0
+	}
+	st, ct := math.Sincos(raddeg * l.Tilt)
+	return l.Width * (ct + st/tan)
+}
+
+// MinimumPitch returns the minimum row pitch (horizontal distance from row to row, m) without shading from start to end
+// at a site, e.g. 9:00 to 15:00 of the winter solstice, sampled every minute. The sun has to be up during the period.
+func (l RowLayout) MinimumPitch(site Site, start time.Time, end time.Time, options *Options) (float64, error) {
+	err := l.validate()
+	if err != nil {
+		return 0, err
+	}
+	if end.Before(start) {
+		return 0, errors.New("Please fix end, must not be before start")
+	}
+	results, err := l.series(site, start, end, rowSpacingStep, options)
+	if err != nil {
+		return 0, err
+	}
+	pitch := l.Width * math.Cos(raddeg*l.Tilt)
+	for _, r := range results {
+		if r.Elevref <= 0.0 {
+			return 0, errors.New("Please fix start and end, the sun is down at " + r.Time.Format(time.RFC3339))
+		}
+		pitch = math.Max(pitch, l.pitch(r))
+	}
+	return pitch, nil
+}
+
+// DailyShading returns the shading of a row with the given pitch on the day of date at a site from sunrise to sunset,
+// sampled every step (DEFAULT (0) = 10 minutes), and the shaded fraction of the day weighted by the extraterrestrial beam
+// irradiance on the panels
+func (l RowLayout) DailyShading(site Site, date time.Time, pitch float64, step time.Duration, options *Options) ([]RowShading, float64, error) {
+	err := l.validate()
+	if err != nil {
+		return nil, 0, err
+	}
+	if !(pitch > 0.0) {
+		return nil, 0, errors.New("Please fix pitch, must be positive")
+	}
+	if step == 0 {
+		step = 10 * time.Minute
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, site.location())
+	results, err := l.series(site, start, start.AddDate(0, 0, 1), step, options)
+	if err != nil {
+		return nil, 0, err
+	}
+	normal := SurfaceNormal(l.Tilt, l.aspect())
+	var shading []RowShading
+	var shaded, total float64
+	for _, r := range results {
+		if r.Elevref <= 0.0 {
+			continue
+		}
+		fraction := l.ShadedFraction(r, pitch)
+		shading = append(shading, RowShading{Time: r.Time, Fraction: fraction})
+		s, err := IncidenceOnSurface(r, normal)
+		if err != nil {
+			return nil, 0, err
+		}
+		shaded += fraction * s.Etrtilt
+		total += s.Etrtilt
+	}
+	if total > 0.0 {
+		shaded /= total
+	}
+	return shading, shaded, nil
+}
+
+// series calculates the sun at a site from start to end
+func (l RowLayout) series(site Site, start time.Time, end time.Time, step time.Duration, options *Options) ([]Result, error) {
+	o := DefaultOptions()
+	if options != nil {
+		o = *options
+	}
+	sp, err := NewSolposWithOptions(start.In(site.location()), site.Latitude, site.Longitude, o)
+	if err != nil {
+		return nil, err
+	}
+	return sp.CalculateSeries(start, end, step)
+}
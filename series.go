@@ -0,0 +1,173 @@
+package solpos
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SeriesChunk defines the calendar period processed at once by chunked series calculations
+type SeriesChunk int
+
+const (
+	ChunkDay   SeriesChunk = iota // one local day
+	ChunkWeek                     // seven local days
+	ChunkMonth                    // one calendar month
+	ChunkYear                     // one calendar year
+)
+
+// next returns the beginning of the chunk following the one containing dt
+func (c SeriesChunk) next(dt time.Time) time.Time {
+	y, m, d := dt.Date()
+	switch c {
+	case ChunkWeek:
+		return time.Date(y, m, d+7, 0, 0, 0, 0, dt.Location())
+	case ChunkMonth:
+		return time.Date(y, m+1, 1, 0, 0, 0, 0, dt.Location())
+	case ChunkYear:
+		return time.Date(y+1, 1, 1, 0, 0, 0, 0, dt.Location())
+	default:
+		return time.Date(y, m, d+1, 0, 0, 0, 0, dt.Location())
+	}
+}
+
+func (sp *PosData) CalculateSeries(start time.Time, end time.Time, step time.Duration) ([]Result, error) {
+	var sink sliceSink
+	if step > 0 && !end.Before(start) {
+		sink.results = make([]Result, 0, end.Sub(start)/step+1)
+	}
+	err := sp.StreamSeries(start, end, step, &sink)
+	if err != nil {
+		return nil, err
+	}
+	return sink.results, nil
+}
+
+// sliceSink collects results in memory
+type sliceSink struct {
+	results []Result
+}
+
+func (s *sliceSink) Write(r Result) error {
+	s.results = append(s.results, r)
+	return nil
+}
+
+func (s *sliceSink) Close() error {
+	return nil
+}
+
+func (sp *PosData) StreamSeries(start time.Time, end time.Time, step time.Duration, sink ResultsSink) (err error) {
+	points := 0
+	done := instrumentation.Start("StreamSeries")
+	defer func() { done(points, err) }()
+	if step <= 0 {
+		return errors.New("Please fix step, must be positive")
+	}
+	if end.Before(start) {
+		return errors.New("Please fix end, must not be before start")
+	}
+	for dt := start; !dt.After(end); dt = dt.Add(step) {
+		sp.SetDate(dt)
+		err = sp.Calculate()
+		if err != nil {
+			return err
+		}
+		err = sink.Write(sp.GetResult())
+		if err != nil {
+			return err
+		}
+		points++
+	}
+	return nil
+}
+
+func (sp *PosData) StreamPositions(ctx context.Context, start time.Time, end time.Time, step time.Duration) (<-chan Result, <-chan error) {
+	results := make(chan Result)
+	errc := make(chan error, 1)
+	/* the copy keeps the caller's calculator untouched while the stream runs */
+	c := *sp
+	go func() {
+		err := c.StreamSeries(start, end, step, &chanSink{ctx: ctx, results: results})
+		/* receivers ranging over the results see the error as soon as the loop ends */
+		close(results)
+		errc <- err
+		close(errc)
+	}()
+	return results, errc
+}
+
+// chanSink sends results to a channel, blocking until they are received or ctx is done
+type chanSink struct {
+	ctx     context.Context
+	results chan<- Result
+}
+
+func (s *chanSink) Write(r Result) error {
+	select {
+	case s.results <- r:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *chanSink) Close() error {
+	return nil
+}
+
+func (sp *PosData) StreamSeriesChunked(start time.Time, end time.Time, step time.Duration, chunk SeriesChunk, sink ResultsSink) (err error) {
+	points := 0
+	done := instrumentation.Start("StreamSeriesChunked")
+	defer func() { done(points, err) }()
+	if step <= 0 {
+		return errors.New("Please fix step, must be positive")
+	}
+	if end.Before(start) {
+		return errors.New("Please fix end, must not be before start")
+	}
+	// the chunk is calculated before any of it is written, the buffer is reused, memory is bounded by the number of steps per chunk
+	var buf []Result
+	dt := start
+	for !dt.After(end) {
+		boundary := chunk.next(dt)
+		buf = buf[:0]
+		for ; !dt.After(end) && dt.Before(boundary); dt = dt.Add(step) {
+			sp.SetDate(dt)
+			err = sp.Calculate()
+			if err != nil {
+				return err
+			}
+			buf = append(buf, sp.GetResult())
+		}
+		for _, r := range buf {
+			err = sink.Write(r)
+			if err != nil {
+				return err
+			}
+		}
+		points += len(buf)
+		if f, ok := sink.(Flusher); ok {
+			err = f.Flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Backfill computes a series from start to end (inclusive) at a fixed step for a location and persists every result to the sink,
+// closing the sink when done. The optional parameters are the same as for NewSolpos.
+func Backfill(start time.Time, end time.Time, step time.Duration, latitude float64, longitude float64, optionalParameters map[string]interface{}, sink ResultsSink) error {
+	sp, err := NewSolpos(start, latitude, longitude, optionalParameters)
+	if err != nil {
+		return err
+	}
+	err = sp.StreamSeries(start, end, step, sink)
+	if err != nil {
+		_ = sink.Close()
+		return err
+	}
+	return sink.Close()
+}
@@ -0,0 +1,51 @@
+package solpos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStreamPositions(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), 52.52, 13.40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := sp.Getdate()
+	results, errc := sp.StreamPositions(context.Background(), start, start.Add(time.Hour), time.Minute)
+	n := 0
+	for range results {
+		n++
+	}
+	if err := <-errc; err != nil || n != 61 {
+		t.Errorf("%d results, error %v, want 61, nil", n, err)
+	}
+}
+
+func TestStreamPositionsCancel(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), 52.52, 13.40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := sp.Getdate()
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		results, errc := sp.StreamPositions(ctx, start, start.AddDate(1, 0, 0), time.Minute)
+		<-results
+		cancel()
+		err := <-errc
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("error %v, want context.Canceled", err)
+		}
+		/* the results are closed before the error is sent */
+		select {
+		case _, ok := <-results:
+			if ok {
+				t.Fatal("result after the error")
+			}
+		default:
+			t.Fatal("results not closed when the error is received")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package solpos
+
+import "math"
+
+/*============================================================================
+*    Shadow bands
+*
+*    Dimensions of common shadow bands and the models correcting the diffuse
+*    irradiance measured under them for the part of the sky they block.
+*       Drummond, A. J.  1956.  On the measurement of sky radiation.
+*            Archiv fur Meteorologie, Geophysik und Bioklimatologie, Serie B,
+*            7, pp. 413-436
+*----------------------------------------------------------------------------*/
+
+// ShadowBand describes the dimensions of a shadow band, the inputs Sbwid and Sbrad and the sky factor Sbsky
+type ShadowBand struct {
+	Name   string  // Name of the band
+	Width  float64 // Width of the band, cm
+	Radius float64 // Radius of the band, cm
+	Sky    float64 // Sky factor of the correction model (Drummond: 0.04 for partly cloudy skies)
+}
+
+// Shadow band presets, nominal dimensions, check them against the datasheet of the installed band
+var (
+	ShadowBandEppley    = ShadowBand{Name: "Eppley SBS", Width: 7.6, Radius: 31.7, Sky: 0.04} // the SOLPOS default
+	ShadowBandKippZonen = ShadowBand{Name: "Kipp & Zonen CM 121", Width: 5.5, Radius: 31.0, Sky: 0.04}
+	ShadowBandSchenk    = ShadowBand{Name: "Schenk shadow ring", Width: 5.0, Radius: 25.0, Sky: 0.04}
+)
+
+// SetShadowBand sets the shadow band inputs Sbwid, Sbrad and Sbsky to the dimensions of a band, e.g. a preset
+func (sp *PosData) SetShadowBand(band ShadowBand) {
+	sp.Sbwid = band.Width
+	sp.Sbrad = band.Radius
+	sp.Sbsky = band.Sky
+}
+
+// ShadowBandInput are the inputs of a shadow-band correction model
+type ShadowBandInput struct {
+	Latitude    float64 // Latitude, degrees north (south negative)
+	Declination float64 // Declination of the sun, degrees north
+	Ssha        float64 // Sunset hour angle, degrees
+	Width       float64 // Width of the band, cm
+	Radius      float64 // Radius of the band, cm
+	Sky         float64 // Sky factor
+}
+
+// ShadowBandModel calculates the shadow-band correction factor (S_SBCF), implement it for hardware or corrections
+// not covered by the included models
+type ShadowBandModel interface {
+	Correction(in ShadowBandInput) (float64, error)
+}
+
+// ShadowBandModelFunc is a function implementing ShadowBandModel
+type ShadowBandModelFunc func(in ShadowBandInput) (float64, error)
+
+// Correction calls f(in)
+func (f ShadowBandModelFunc) Correction(in ShadowBandInput) (float64, error) {
+	return f(in)
+}
+
+// blocked returns the fraction of the isotropic diffuse sky blocked by the band during the day
+func (in ShadowBandInput) blocked() float64 {
+	cd := math.Cos(raddeg * in.Declination)
+	p := 0.6366198 * in.Width / in.Radius * math.Pow(cd, 3)
+	t1 := math.Sin(raddeg*in.Latitude) * math.Sin(raddeg*in.Declination) * in.Ssha * raddeg
+	t2 := math.Cos(raddeg*in.Latitude) * cd * math.Sin(in.Ssha*raddeg)
+	return p * (t1 + t2)
+}
+
+var (
+	// DrummondModel is the correction of SOLPOS: the isotropic geometric correction plus the sky factor, the default
+	DrummondModel ShadowBandModel = ShadowBandModelFunc(func(in ShadowBandInput) (float64, error) {
+		x := in.blocked()
+		/* a band shading the whole sky blows the correction up */
+		if math.Abs(1.0-x) < 1.0e-6 {
+			return 0.0, wrap(ErrNumericalDomain, "sbcf: shadow band blocks the whole diffuse sky")
+		}
+		return in.Sky + 1.0/(1.0-x), nil
+	})
+	// DrummondScaledModel scales the isotropic geometric correction by 1 + sky factor instead of adding it, the form
+	// of the sky anisotropy correction used by several band manufacturers
+	DrummondScaledModel ShadowBandModel = ShadowBandModelFunc(func(in ShadowBandInput) (float64, error) {
+		x := in.blocked()
+		if math.Abs(1.0-x) < 1.0e-6 {
+			return 0.0, wrap(ErrNumericalDomain, "sbcf: shadow band blocks the whole diffuse sky")
+		}
+		return (1.0 + in.Sky) / (1.0 - x), nil
+	})
+	// IsotropicModel is the geometric correction for an isotropic sky only, ignoring the sky factor
+	IsotropicModel ShadowBandModel = ShadowBandModelFunc(func(in ShadowBandInput) (float64, error) {
+		x := in.blocked()
+		if math.Abs(1.0-x) < 1.0e-6 {
+			return 0.0, wrap(ErrNumericalDomain, "sbcf: shadow band blocks the whole diffuse sky")
+		}
+		return 1.0 / (1.0 - x), nil
+	})
+)
+
+func (sp *PosData) SetShadowBandModel(model ShadowBandModel) {
+	sp.ShadowBandModel = model
+}
+
+func (sp *PosData) GetShadowBandModel() ShadowBandModel {
+	if sp.ShadowBandModel == nil {
+		return DrummondModel
+	}
+	return sp.ShadowBandModel
+}
@@ -0,0 +1,136 @@
+package solpos
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SimulationConfig configures RunSimulation
+type SimulationConfig struct {
+	Start time.Time     // Beginning of the simulated range
+	End   time.Time     // End of the simulated range (inclusive)
+	Step  time.Duration // Simulated time between two positions
+	Speed float64       // Simulated time per wall clock time, e.g. 1440 replays a day per minute, 0 = as fast as possible
+}
+
+// SimulationEventKind tells what a SimulationEvent reports
+type SimulationEventKind int
+
+const (
+	SimulationPosition SimulationEventKind = iota // sun position at a step
+	SimulationSunrise                             // sunrise (without refraction, like GetSunrise)
+	SimulationSunset                              // sunset (without refraction, like GetSunset)
+)
+
+func (k SimulationEventKind) String() string {
+	switch k {
+	case SimulationSunrise:
+		return "sunrise"
+	case SimulationSunset:
+		return "sunset"
+	default:
+		return "position"
+	}
+}
+
+// SimulationEvent is emitted by RunSimulation for every step and every sunrise and sunset in between
+type SimulationEvent struct {
+	Kind   SimulationEventKind
+	Time   time.Time // Simulated time, local time of the site
+	Result Result    // Sun position at Time
+}
+
+// RunSimulation replays the configured range for the site at the configured speed and sends the positions of every step,
+// and sunrises and sunsets in time order, to sink. It is meant to test tracker firmware or automation rules without
+// waiting for the real sun. The sink is closed when RunSimulation returns, after the range or when ctx is done (ctx.Err()).
+func RunSimulation(ctx context.Context, site Site, config SimulationConfig, sink chan<- SimulationEvent) error {
+	defer close(sink)
+	if config.Step <= 0 {
+		return errors.New("Please fix step, must be positive")
+	}
+	if config.End.Before(config.Start) {
+		return errors.New("Please fix end, must not be before start")
+	}
+	if config.Speed < 0 {
+		return errors.New("Please fix speed, must not be negative")
+	}
+	loc := site.location()
+	sp, err := NewSolpos(config.Start.In(loc), site.Latitude, site.Longitude, nil)
+	if err != nil {
+		return err
+	}
+	var ticker *time.Ticker
+	if config.Speed > 0 {
+		ticker = time.NewTicker(time.Duration(float64(config.Step) / config.Speed))
+		defer ticker.Stop()
+	}
+
+	send := func(kind SimulationEventKind, dt time.Time) error {
+		sp.SetDate(dt)
+		err := sp.Calculate()
+		if err != nil {
+			return err
+		}
+		select {
+		case sink <- SimulationEvent{Kind: kind, Time: dt, Result: sp.GetResult()}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var previous time.Time
+	for dt := config.Start.In(loc); !dt.After(config.End); dt = dt.Add(config.Step) {
+		if !previous.IsZero() {
+			events, err := riseSetBetween(sp, previous, dt)
+			if err != nil {
+				return err
+			}
+			for _, e := range events {
+				err = send(e.Kind, e.Time)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = send(SimulationPosition, dt)
+		if err != nil {
+			return err
+		}
+		previous = dt
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// riseSetBetween returns the sunrises and sunsets after from up to and including to, in time order
+func riseSetBetween(sp Solpos, from time.Time, to time.Time) ([]SimulationEvent, error) {
+	var events []SimulationEvent
+	y, m, d := from.Date()
+	for day := time.Date(y, m, d, 12, 0, 0, 0, from.Location()); !day.After(to.Add(12 * time.Hour)); day = day.AddDate(0, 0, 1) {
+		sp.SetDate(day)
+		err := sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		/* no sunrise and sunset during 24 hours of sun up or down (sretr and ssetr are +/- 2999) */
+		if sp.GetSretr() < -1440.0 || sp.GetSretr() > 2880.0 {
+			continue
+		}
+		rise, set := sp.GetSunrise().In(from.Location()), sp.GetSunset().In(from.Location())
+		if rise.After(from) && !rise.After(to) {
+			events = append(events, SimulationEvent{Kind: SimulationSunrise, Time: rise})
+		}
+		if set.After(from) && !set.After(to) {
+			events = append(events, SimulationEvent{Kind: SimulationSunset, Time: set})
+		}
+	}
+	return events, nil
+}
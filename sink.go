@@ -0,0 +1,14 @@
+package solpos
+
+// ResultsSink consumes results one at a time, e.g. while streaming a long series to disk or a pipe
+type ResultsSink interface {
+	// Write stores a single result
+	Write(r Result) error
+	// Close flushes any buffered results; the underlying writer stays open
+	Close() error
+}
+
+// Flusher is implemented by sinks buffering results, chunked series calculations flush them after every chunk
+type Flusher interface {
+	Flush() error
+}
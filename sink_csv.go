@@ -0,0 +1,45 @@
+package solpos
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// NewCSVSink creates a ResultsSink writing comma separated rows (site, time, outputs) to w, preceded by a header row
+func NewCSVSink(w io.Writer, site string) ResultsSink {
+	return &csvSink{w: csv.NewWriter(w), site: site}
+}
+
+type csvSink struct {
+	w      *csv.Writer
+	site   string
+	header bool
+	record []string
+}
+
+func (s *csvSink) Write(r Result) error {
+	if !s.header {
+		err := s.w.Write(append([]string{"site", "time"}, resultColumns...))
+		if err != nil {
+			return err
+		}
+		s.header = true
+	}
+	// reuse the record to keep allocations low on long series
+	s.record = append(s.record[:0], s.site, r.Time.Format(time.RFC3339))
+	for _, v := range r.Values() {
+		s.record = append(s.record, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return s.w.Write(s.record)
+}
+
+func (s *csvSink) Flush() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *csvSink) Close() error {
+	return s.Flush()
+}
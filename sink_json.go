@@ -0,0 +1,87 @@
+//go:build !tinygo
+// +build !tinygo
+
+package solpos
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// NewJSONLSink creates a ResultsSink writing one JSON object per line (JSON Lines) to w
+func NewJSONLSink(w io.Writer) ResultsSink {
+	bw := bufio.NewWriter(w)
+	return &jsonlSink{w: bw, enc: json.NewEncoder(bw)}
+}
+
+type jsonlSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (s *jsonlSink) Write(r Result) error {
+	// Encode terminates every value with a newline
+	return s.enc.Encode(r)
+}
+
+func (s *jsonlSink) Flush() error {
+	return s.w.Flush()
+}
+
+func (s *jsonlSink) Close() error {
+	return s.Flush()
+}
+
+// MarshalJSON encodes the result with the field names of its tags. Outputs which are not finite, e.g. the NaN of
+// outputs not calculated in strict mode, are encoded as null, JSON has no representation for them.
+func (r Result) MarshalJSON() ([]byte, error) {
+	t, err := r.Time.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, 512)
+	b = append(b, `{"time":`...)
+	b = append(b, t...)
+	for i, v := range r.Values() {
+		b = append(b, `,"`...)
+		b = append(b, resultColumns[i]...)
+		b = append(b, `":`...)
+		b = appendJSONFloat(b, v)
+	}
+	if r.SunObstructed {
+		b = append(b, `,"sunObstructed":true`...)
+	}
+	if len(r.Warnings) > 0 {
+		w, err := json.Marshal(r.Warnings)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, `,"warnings":`...)
+		b = append(b, w...)
+	}
+	return append(b, '}'), nil
+}
+
+// appendJSONFloat appends v formatted like encoding/json, null if v is not finite
+func appendJSONFloat(b []byte, v float64) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return append(b, "null"...)
+	}
+	abs := math.Abs(v)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, v, format, -1, 64)
+	if format == 'e' {
+		/* clean up e-09 to e-9 */
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
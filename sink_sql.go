@@ -0,0 +1,108 @@
+//go:build !tinygo
+// +build !tinygo
+
+package solpos
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sqlBatchSize is the number of rows inserted per transaction
+const sqlBatchSize = 1000
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewSQLiteSink creates a ResultsSink inserting rows (site, time, outputs) into the given table of a SQLite database.
+// The table is created if it does not exist yet, (site, time) is its primary key with the time in UTC (RFC 3339), so an
+// instant has the same key whatever the time zone of the result. Rows are inserted in transactions of sqlBatchSize rows,
+// a failed insert rolls back the uncommitted rows of its batch. The caller registers the database driver of choice and
+// keeps ownership of db.
+func NewSQLiteSink(db *sql.DB, table string, site string) (ResultsSink, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, errors.New("Please fix table name, only letters, digits and underscores are allowed")
+	}
+	columns := make([]string, len(resultColumns))
+	for i, c := range resultColumns {
+		columns[i] = c + " REAL"
+	}
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + table + " (site TEXT NOT NULL, time TEXT NOT NULL, " +
+		strings.Join(columns, ", ") + ", PRIMARY KEY (site, time))")
+	if err != nil {
+		return nil, err
+	}
+	insert := "INSERT OR REPLACE INTO " + table + " (site, time, " + strings.Join(resultColumns, ", ") + ") VALUES (?" +
+		strings.Repeat(", ?", len(resultColumns)+1) + ")"
+	return &sqlSink{db: db, insert: insert, site: site}, nil
+}
+
+type sqlSink struct {
+	db     *sql.DB
+	insert string
+	site   string
+	tx     *sql.Tx
+	stmt   *sql.Stmt
+	rows   int
+}
+
+func (s *sqlSink) Write(r Result) error {
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(s.insert)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		s.tx, s.stmt = tx, stmt
+	}
+	args := make([]interface{}, 0, len(resultColumns)+2)
+	args = append(args, s.site, r.Time.UTC().Format(time.RFC3339))
+	for _, v := range r.Values() {
+		args = append(args, v)
+	}
+	_, err := s.stmt.Exec(args...)
+	if err != nil {
+		/* the transaction may be unusable, the next Write starts a new one */
+		s.rollback()
+		return err
+	}
+	s.rows++
+	if s.rows >= sqlBatchSize {
+		return s.commit()
+	}
+	return nil
+}
+
+func (s *sqlSink) commit() error {
+	if s.tx == nil {
+		return nil
+	}
+	err := s.stmt.Close()
+	if err != nil {
+		_ = s.tx.Rollback()
+	} else {
+		err = s.tx.Commit()
+	}
+	s.tx, s.stmt, s.rows = nil, nil, 0
+	return err
+}
+
+func (s *sqlSink) rollback() {
+	_ = s.stmt.Close()
+	_ = s.tx.Rollback()
+	s.tx, s.stmt, s.rows = nil, nil, 0
+}
+
+func (s *sqlSink) Flush() error {
+	return s.commit()
+}
+
+func (s *sqlSink) Close() error {
+	return s.Flush()
+}
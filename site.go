@@ -0,0 +1,18 @@
+package solpos
+
+import "time"
+
+// Site is a named location on Earth
+type Site struct {
+	Name      string         // Name or identifier of the site
+	Latitude  float64        // Latitude, degrees north (south negative)
+	Longitude float64        // Longitude, degrees east (west negative)
+	Location  *time.Location // Time zone of local times (including DST rules), DEFAULT (nil) = UTC
+}
+
+func (s Site) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
@@ -0,0 +1,34 @@
+package solpos
+
+import (
+	"math"
+	"time"
+)
+
+func (sp *PosData) GetSolarNoon() (time.Time, error) {
+	err := sp.Computed(LTst)
+	if err != nil {
+		return time.Time{}, err
+	}
+	/* true solar time = local standard time + tstfix */
+	return sp.eventTime(720.0 - sp.Tstfix), nil
+}
+
+func (sp *PosData) GetMaxElevation() (float64, error) {
+	err := sp.Computed(LGeom)
+	if err != nil {
+		return math.NaN(), err
+	}
+	/* at solar noon the sun is in the meridian, the zenith angle is the difference of latitude and declination */
+	elevetr := 90.0 - math.Abs(sp.Latitude-sp.Declin)
+	return elevetr + refraction(elevetr, sp.Press, sp.Temp), nil
+}
+
+func (sp *PosData) GetDayLength() (time.Duration, error) {
+	err := sp.Computed(LSsha)
+	if err != nil {
+		return 0, err
+	}
+	/* the sun moves 15 degrees of hour angle per hour, from -ssha to +ssha; ssha is 180 for polar day and 0 for polar night */
+	return time.Duration(sp.riseSetHourAngle() * 8.0 * float64(time.Minute)), nil
+}
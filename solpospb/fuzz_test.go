@@ -0,0 +1,61 @@
+//go:build go1.18
+
+package solpospb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// fuzzStable checks that decoding arbitrary bytes never panics and that messages which decode are stable: encoding the
+// decoded value and decoding it again yields the same encoding
+func fuzzStable(f *testing.F, golden string, decode func(b []byte) ([]byte, error)) {
+	b, err := hex.DecodeString(golden)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(b)
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		first, err := decode(b)
+		if err != nil {
+			return
+		}
+		second, err := decode(first)
+		if err != nil {
+			t.Fatalf("decoding the encoding %x of %x: %v", first, b, err)
+		}
+		if !bytes.Equal(first, second) {
+			t.Fatalf("encoding of %x not stable: %x, %x", b, first, second)
+		}
+	})
+}
+
+func FuzzUnmarshalInputs(f *testing.F) {
+	fuzzStable(f, goldenInputsHex, func(b []byte) ([]byte, error) {
+		in, err := UnmarshalInputs(b)
+		return MarshalInputs(in), err
+	})
+}
+
+func FuzzUnmarshalResult(f *testing.F) {
+	fuzzStable(f, goldenResultHex, func(b []byte) ([]byte, error) {
+		r, err := UnmarshalResult(b)
+		return MarshalResult(r), err
+	})
+}
+
+func FuzzUnmarshalEvent(f *testing.F) {
+	fuzzStable(f, goldenEventHex, func(b []byte) ([]byte, error) {
+		e, err := UnmarshalEvent(b)
+		return MarshalEvent(e), err
+	})
+}
+
+func FuzzUnmarshalSeries(f *testing.F) {
+	fuzzStable(f, goldenSeriesHex, func(b []byte) ([]byte, error) {
+		s, err := UnmarshalSeries(b)
+		return MarshalSeries(s), err
+	})
+}
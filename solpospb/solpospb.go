@@ -0,0 +1,364 @@
+// Package solpospb encodes go-solpos data as protocol buffers, following the messages of solpos.proto in this directory.
+// The schema has no service definition.
+// It has no dependencies: the messages are encoded and decoded directly, so any protobuf implementation in another
+// language can exchange them with the schema.
+package solpospb
+
+import (
+	"time"
+
+	solpos "github.com/maltegrosse/go-solpos"
+)
+
+// Inputs are the inputs of a calculation (message Inputs)
+type Inputs struct {
+	Time      time.Time
+	Latitude  float64
+	Longitude float64
+	Press     float64
+	Temp      float64
+	Tilt      float64
+	Aspect    float64
+	Function  solpos.SPFunctions
+}
+
+// NewInputs returns inputs for a time and location with the default values of NewSolpos
+func NewInputs(dt time.Time, latitude float64, longitude float64) Inputs {
+	return Inputs{Time: dt, Latitude: latitude, Longitude: longitude, Press: 1013.0, Temp: 15.0, Aspect: 180.0, Function: solpos.SAll}
+}
+
+// InputsOf returns the inputs of a Solpos instance
+func InputsOf(sp solpos.Solpos) Inputs {
+	return Inputs{Time: sp.Getdate(), Latitude: sp.GetLatitude(), Longitude: sp.GetLongitude(), Press: sp.GetPress(),
+		Temp: sp.GetTemp(), Tilt: sp.GetTilt(), Aspect: sp.GetAspect(), Function: sp.GetFunction()}
+}
+
+// Solpos creates new instance of Solpos with the inputs, all values are used as they are (0 is not a default)
+func (in Inputs) Solpos() (solpos.Solpos, error) {
+	return solpos.NewSolpos(in.Time, in.Latitude, in.Longitude, map[string]interface{}{
+		"press": in.Press, "temp": in.Temp, "tilt": in.Tilt, "aspect": in.Aspect, "function": in.Function,
+	})
+}
+
+// EventKind is the kind of an event of the sun
+type EventKind int
+
+const (
+	Sunrise EventKind = iota + 1 // EVENT_KIND_SUNRISE
+	Sunset                       // EVENT_KIND_SUNSET
+)
+
+// Event is an event of the sun at a location (message Event)
+type Event struct {
+	Kind      EventKind
+	Time      time.Time
+	Latitude  float64
+	Longitude float64
+}
+
+// Series are the results of a site (message Series)
+type Series struct {
+	Site    string
+	Results []solpos.Result
+}
+
+// MarshalInputs encodes inputs as message Inputs
+func MarshalInputs(in Inputs) []byte {
+	var b []byte
+	b = appendTimestamp(b, 1, in.Time)
+	b = appendSint32(b, 2, offset(in.Time))
+	b = appendDouble(b, 3, in.Latitude)
+	b = appendDouble(b, 4, in.Longitude)
+	b = appendDouble(b, 5, in.Press)
+	b = appendDouble(b, 6, in.Temp)
+	b = appendDouble(b, 7, in.Tilt)
+	b = appendDouble(b, 8, in.Aspect)
+	return appendUvarint(b, 9, uint64(in.Function))
+}
+
+// UnmarshalInputs decodes message Inputs
+func UnmarshalInputs(b []byte) (Inputs, error) {
+	var in Inputs
+	var seconds, nanos int64
+	var utcOffset int32
+	var present bool
+	r := reader{b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return Inputs{}, err
+		}
+		switch field {
+		case 1:
+			err = expect(field, wire, wireBytes)
+			if err == nil {
+				var ts []byte
+				ts, err = r.bytes()
+				if err == nil {
+					seconds, nanos, err = timestamp(ts)
+					present = true
+				}
+			}
+		case 2:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				utcOffset, err = r.sint32()
+			}
+		case 3, 4, 5, 6, 7, 8:
+			err = expect(field, wire, wireFixed64)
+			if err == nil {
+				v := [...]*float64{&in.Latitude, &in.Longitude, &in.Press, &in.Temp, &in.Tilt, &in.Aspect}[field-3]
+				*v, err = r.double()
+			}
+		case 9:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				var v uint64
+				v, err = r.varint()
+				in.Function = solpos.SPFunctions(v)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return Inputs{}, err
+		}
+	}
+	/* a message without function (the proto3 default 0) calculates everything */
+	if in.Function == 0 {
+		in.Function = solpos.SAll
+	}
+	in.Time = localTime(seconds, nanos, utcOffset, present)
+	return in, nil
+}
+
+// resultFields returns the numeric fields of a result in the order of their field numbers, starting at 3
+func resultFields(r *solpos.Result) []*float64 {
+	return []*float64{&r.Latitude, &r.Longitude, &r.Amass, &r.Ampress, &r.Azim, &r.Cosinc, &r.Coszen, &r.Declin, &r.Elevetr,
+		&r.Elevref, &r.Eqntim, &r.Etr, &r.Etrn, &r.Etrtilt, &r.Hrang, &r.Prime, &r.Sbcf, &r.Sretr, &r.Ssetr, &r.Ssha, &r.Tstfix,
+		&r.Unprime, &r.Zenetr, &r.Zenref}
+}
+
+const (
+	resultWarnings      = 27
+	resultSunObstructed = 28
+)
+
+// MarshalResult encodes a result as message Result
+func MarshalResult(r solpos.Result) []byte {
+	return appendResult(nil, r)
+}
+
+func appendResult(b []byte, r solpos.Result) []byte {
+	b = appendTimestamp(b, 1, r.Time)
+	b = appendSint32(b, 2, offset(r.Time))
+	for i, v := range resultFields(&r) {
+		b = appendDouble(b, i+3, *v)
+	}
+	if len(r.Warnings) > 0 {
+		/* repeated enums are packed in proto3 */
+		var packed []byte
+		for _, w := range r.Warnings {
+			packed = appendVarint(packed, uint64(w)+1)
+		}
+		b = appendBytes(b, resultWarnings, packed)
+	}
+	if r.SunObstructed {
+		b = appendUvarint(b, resultSunObstructed, 1)
+	}
+	return b
+}
+
+// UnmarshalResult decodes message Result, the time is in a fixed zone of the encoded UTC offset
+func UnmarshalResult(b []byte) (solpos.Result, error) {
+	var res solpos.Result
+	var seconds, nanos int64
+	var utcOffset int32
+	var present bool
+	fields := resultFields(&res)
+	r := reader{b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return solpos.Result{}, err
+		}
+		switch {
+		case field == 1:
+			err = expect(field, wire, wireBytes)
+			if err == nil {
+				var ts []byte
+				ts, err = r.bytes()
+				if err == nil {
+					seconds, nanos, err = timestamp(ts)
+					present = true
+				}
+			}
+		case field == 2:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				utcOffset, err = r.sint32()
+			}
+		case field >= 3 && field < 3+len(fields):
+			err = expect(field, wire, wireFixed64)
+			if err == nil {
+				*fields[field-3], err = r.double()
+			}
+		case field == resultWarnings && wire == wireBytes:
+			var packed []byte
+			packed, err = r.bytes()
+			p := reader{packed}
+			for err == nil && !p.done() {
+				err = appendWarning(&res, &p)
+			}
+		case field == resultWarnings:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				err = appendWarning(&res, &r)
+			}
+		case field == resultSunObstructed:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				var v uint64
+				v, err = r.varint()
+				res.SunObstructed = v != 0
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return solpos.Result{}, err
+		}
+	}
+	res.Time = localTime(seconds, nanos, utcOffset, present)
+	return res, nil
+}
+
+// MarshalEvent encodes an event as message Event
+func MarshalEvent(e Event) []byte {
+	var b []byte
+	b = appendUvarint(b, 1, uint64(e.Kind))
+	b = appendTimestamp(b, 2, e.Time)
+	b = appendSint32(b, 3, offset(e.Time))
+	b = appendDouble(b, 4, e.Latitude)
+	return appendDouble(b, 5, e.Longitude)
+}
+
+// UnmarshalEvent decodes message Event
+func UnmarshalEvent(b []byte) (Event, error) {
+	var e Event
+	var seconds, nanos int64
+	var utcOffset int32
+	var present bool
+	r := reader{b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return Event{}, err
+		}
+		switch field {
+		case 1:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				var v uint64
+				v, err = r.varint()
+				e.Kind = EventKind(v)
+			}
+		case 2:
+			err = expect(field, wire, wireBytes)
+			if err == nil {
+				var ts []byte
+				ts, err = r.bytes()
+				if err == nil {
+					seconds, nanos, err = timestamp(ts)
+					present = true
+				}
+			}
+		case 3:
+			err = expect(field, wire, wireVarint)
+			if err == nil {
+				utcOffset, err = r.sint32()
+			}
+		case 4:
+			err = expect(field, wire, wireFixed64)
+			if err == nil {
+				e.Latitude, err = r.double()
+			}
+		case 5:
+			err = expect(field, wire, wireFixed64)
+			if err == nil {
+				e.Longitude, err = r.double()
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return Event{}, err
+		}
+	}
+	e.Time = localTime(seconds, nanos, utcOffset, present)
+	return e, nil
+}
+
+// MarshalSeries encodes a series as message Series
+func MarshalSeries(s Series) []byte {
+	var b []byte
+	if s.Site != "" {
+		b = appendBytes(b, 1, []byte(s.Site))
+	}
+	var buf []byte
+	for _, r := range s.Results {
+		buf = appendResult(buf[:0], r)
+		b = appendBytes(b, 2, buf)
+	}
+	return b
+}
+
+// UnmarshalSeries decodes message Series
+func UnmarshalSeries(b []byte) (Series, error) {
+	var s Series
+	r := reader{b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return Series{}, err
+		}
+		switch field {
+		case 1:
+			err = expect(field, wire, wireBytes)
+			if err == nil {
+				var v []byte
+				v, err = r.bytes()
+				s.Site = string(v)
+			}
+		case 2:
+			err = expect(field, wire, wireBytes)
+			if err == nil {
+				var v []byte
+				v, err = r.bytes()
+				if err == nil {
+					var res solpos.Result
+					res, err = UnmarshalResult(v)
+					s.Results = append(s.Results, res)
+				}
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return Series{}, err
+		}
+	}
+	return s, nil
+}
+
+// appendWarning decodes a warning, WARNING_UNSPECIFIED is left out
+func appendWarning(res *solpos.Result, r *reader) error {
+	v, err := r.varint()
+	if err != nil {
+		return err
+	}
+	if v > 0 {
+		res.Warnings = append(res.Warnings, solpos.Warning(v-1))
+	}
+	return nil
+}
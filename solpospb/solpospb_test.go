@@ -0,0 +1,288 @@
+package solpospb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	solpos "github.com/maltegrosse/go-solpos"
+)
+
+/* The golden messages were encoded once by the reference implementation (google.golang.org/protobuf v1.36.12,
+dynamicpb with the descriptors of solpos.proto, deterministic marshaling) from the values below. */
+
+var goldenInputs = Inputs{
+	Time:     time.Date(2021, 6, 1, 12, 30, 15, 500000000, time.FixedZone("", 7200)),
+	Latitude: 52.52, Longitude: 13.40, Press: 1006.0, Temp: -5.5, Tilt: 33.65, Aspect: 0.0, Function: solpos.SAll,
+}
+
+const goldenInputsHex = "0a0c08b798d885061080cab5ee0110c07019c3f5285c8f424a4021cdcccccccccc2a40290000000000708f40310000000000" +
+	"0016c0393333333333d3404048fe7f"
+
+var goldenResult = solpos.Result{
+	Time:     time.Date(2021, 6, 21, 3, 0, 0, 0, time.FixedZone("", -21600)),
+	Latitude: 89.995, Longitude: -105.18, Amass: -1.0, Azim: 123.25, Hrang: math.Copysign(0, -1), Sretr: -2999.0, Zenref: 66.5625,
+	Warnings:      []solpos.Warning{solpos.WarnPole, solpos.WarnPolarDay},
+	SunObstructed: true,
+}
+
+const goldenResultHex = "0a060890aac1860610bfd1021948e17a14ae7f564021ec51b81e854b5ac029000000000000f0bf390000000000d05e40890100" +
+	"00000000000080a10100000000006ea7c0d1010000000000a45040da01020203e00101"
+
+var goldenEvent = Event{
+	Kind: Sunset, Time: time.Date(1969, 7, 20, 21, 56, 20, 0, time.FixedZone("", -18000)), Latitude: 28.573, Longitude: -80.649,
+}
+
+const goldenEventHex = "0802120b08d4e69ff9ffffffffff01189f990221a69bc420b0923c4029a8c64b37892954c0"
+
+var goldenSeries = Series{
+	Site:    "Golden, CO",
+	Results: []solpos.Result{{Time: time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC), Azim: 90.5}, {}},
+}
+
+const goldenSeriesHex = "0a0a476f6c64656e2c20434f12110a0608a898d88506390000000000a056401200"
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// sameTime reports whether two times are the same instant in the same UTC offset
+func sameTime(a time.Time, b time.Time) bool {
+	return a.Equal(b) && offset(a) == offset(b) && a.IsZero() == b.IsZero()
+}
+
+// sameResult compares results like reflect.DeepEqual, the time by sameTime and the sign of zeros
+func sameResult(a solpos.Result, b solpos.Result) bool {
+	if !sameTime(a.Time, b.Time) {
+		return false
+	}
+	fa, fb := resultFields(&a), resultFields(&b)
+	for i := range fa {
+		if math.Float64bits(*fa[i]) != math.Float64bits(*fb[i]) {
+			return false
+		}
+	}
+	a.Time, b.Time = time.Time{}, time.Time{}
+	return reflect.DeepEqual(a, b)
+}
+
+func TestGoldenInputs(t *testing.T) {
+	want := mustHex(t, goldenInputsHex)
+	if got := MarshalInputs(goldenInputs); !bytes.Equal(got, want) {
+		t.Errorf("MarshalInputs() = %x, want %x", got, want)
+	}
+	in, err := UnmarshalInputs(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameTime(in.Time, goldenInputs.Time) {
+		t.Errorf("time = %v, want %v", in.Time, goldenInputs.Time)
+	}
+	in.Time = goldenInputs.Time
+	if in != goldenInputs {
+		t.Errorf("UnmarshalInputs() = %+v, want %+v", in, goldenInputs)
+	}
+}
+
+func TestGoldenResult(t *testing.T) {
+	want := mustHex(t, goldenResultHex)
+	if got := MarshalResult(goldenResult); !bytes.Equal(got, want) {
+		t.Errorf("MarshalResult() = %x, want %x", got, want)
+	}
+	r, err := UnmarshalResult(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameResult(r, goldenResult) {
+		t.Errorf("UnmarshalResult() = %+v, want %+v", r, goldenResult)
+	}
+}
+
+func TestGoldenEvent(t *testing.T) {
+	want := mustHex(t, goldenEventHex)
+	if got := MarshalEvent(goldenEvent); !bytes.Equal(got, want) {
+		t.Errorf("MarshalEvent() = %x, want %x", got, want)
+	}
+	e, err := UnmarshalEvent(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameTime(e.Time, goldenEvent.Time) {
+		t.Errorf("time = %v, want %v", e.Time, goldenEvent.Time)
+	}
+	e.Time = goldenEvent.Time
+	if e != goldenEvent {
+		t.Errorf("UnmarshalEvent() = %+v, want %+v", e, goldenEvent)
+	}
+}
+
+func TestGoldenSeries(t *testing.T) {
+	want := mustHex(t, goldenSeriesHex)
+	if got := MarshalSeries(goldenSeries); !bytes.Equal(got, want) {
+		t.Errorf("MarshalSeries() = %x, want %x", got, want)
+	}
+	s, err := UnmarshalSeries(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Site != goldenSeries.Site || len(s.Results) != len(goldenSeries.Results) {
+		t.Fatalf("UnmarshalSeries() = %+v, want %+v", s, goldenSeries)
+	}
+	for i := range s.Results {
+		if !sameResult(s.Results[i], goldenSeries.Results[i]) {
+			t.Errorf("result %d = %+v, want %+v", i, s.Results[i], goldenSeries.Results[i])
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	zones := []*time.Location{time.UTC, time.FixedZone("", 5*3600+1800), time.FixedZone("", -7*3600)}
+	var series Series
+	for i, dt := range []time.Time{
+		time.Date(2021, 6, 21, 12, 0, 0, 0, zones[0]),
+		time.Date(1950, 1, 1, 0, 0, 0, 1, zones[1]),
+		time.Date(2050, 12, 31, 23, 59, 59, 999999999, zones[2]),
+	} {
+		for _, latitude := range []float64{-89.999, -33.87, 0.0, 52.52, 78.22} {
+			sp, err := solpos.NewSolpos(dt, latitude, 13.40+float64(i)*50.0, map[string]interface{}{"tilt": 30.0})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := sp.GetResult()
+			got, err := UnmarshalResult(MarshalResult(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameResult(got, r) {
+				t.Errorf("round trip of %+v = %+v", r, got)
+			}
+			in := InputsOf(sp)
+			gotIn, err := UnmarshalInputs(MarshalInputs(in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameTime(gotIn.Time, in.Time) {
+				t.Errorf("round trip of the time %v = %v", in.Time, gotIn.Time)
+			}
+			gotIn.Time = in.Time
+			if gotIn != in {
+				t.Errorf("round trip of %+v = %+v", in, gotIn)
+			}
+			series.Results = append(series.Results, r)
+		}
+	}
+	got, err := UnmarshalSeries(MarshalSeries(series))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != len(series.Results) {
+		t.Fatalf("%d results, want %d", len(got.Results), len(series.Results))
+	}
+	for i := range got.Results {
+		if !sameResult(got.Results[i], series.Results[i]) {
+			t.Errorf("result %d = %+v, want %+v", i, got.Results[i], series.Results[i])
+		}
+	}
+}
+
+func TestUnpackedWarnings(t *testing.T) {
+	/* parsers must accept both encodings of repeated enums */
+	var b []byte
+	b = appendUvarint(b, resultWarnings, 1)
+	b = appendUvarint(b, resultWarnings, 5)
+	b = appendBytes(b, resultWarnings, []byte{6, 0})
+	r, err := UnmarshalResult(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []solpos.Warning{solpos.WarnAirmass, solpos.WarnYearRange, solpos.WarnTimezone}
+	if !reflect.DeepEqual(r.Warnings, want) {
+		t.Errorf("warnings = %v, want %v", r.Warnings, want)
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	var unknown []byte
+	unknown = appendUvarint(unknown, 100, 1<<40)
+	unknown = appendDouble(unknown, 101, 1.5)
+	unknown = appendBytes(unknown, 102, []byte("newer schema"))
+	unknown = append(appendTag(unknown, 103, wireFixed32), 1, 2, 3, 4)
+	b := append(append([]byte(nil), unknown...), mustHex(t, goldenResultHex)...)
+	b = append(b, unknown...)
+	r, err := UnmarshalResult(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameResult(r, goldenResult) {
+		t.Errorf("UnmarshalResult() = %+v, want %+v", r, goldenResult)
+	}
+	in, err := UnmarshalInputs(append(mustHex(t, goldenInputsHex), unknown...))
+	if err != nil || !sameTime(in.Time, goldenInputs.Time) {
+		t.Errorf("UnmarshalInputs() = %+v, %v", in, err)
+	}
+	e, err := UnmarshalEvent(append(unknown, mustHex(t, goldenEventHex)...))
+	if err != nil || e.Kind != Sunset {
+		t.Errorf("UnmarshalEvent() = %+v, %v", e, err)
+	}
+	s, err := UnmarshalSeries(append(mustHex(t, goldenSeriesHex), unknown...))
+	if err != nil || len(s.Results) != 2 {
+		t.Errorf("UnmarshalSeries() = %+v, %v", s, err)
+	}
+}
+
+// unmarshalers decode the messages, discarding the values
+var unmarshalers = map[string]func(b []byte) error{
+	"Inputs": func(b []byte) error { _, err := UnmarshalInputs(b); return err },
+	"Result": func(b []byte) error { _, err := UnmarshalResult(b); return err },
+	"Event":  func(b []byte) error { _, err := UnmarshalEvent(b); return err },
+	"Series": func(b []byte) error { _, err := UnmarshalSeries(b); return err },
+}
+
+var goldenHex = map[string]string{"Inputs": goldenInputsHex, "Result": goldenResultHex, "Event": goldenEventHex, "Series": goldenSeriesHex}
+
+func TestTruncated(t *testing.T) {
+	for name, unmarshal := range unmarshalers {
+		b := mustHex(t, goldenHex[name])
+		/* the last field is cut in every prefix not ending at a field boundary */
+		if err := unmarshal(b[:len(b)-1]); err == nil {
+			t.Errorf("%s: no error for a truncated message", name)
+		}
+		for n := range b {
+			_ = unmarshal(b[:n])
+		}
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"field number 0", []byte{0x00, 0x01}},
+		{"varint overflow", append([]byte{0x18}, bytes.Repeat([]byte{0xff}, 11)...)},
+		{"length beyond the message", []byte{0x0a, 0x05, 0x08, 0x01}},
+		{"group wire type", []byte{0x1b, 0x1c}},
+		{"wire type 7", []byte{0x1f}},
+		{"wrong wire type of a double", []byte{0x18, 0x01}},
+		{"wrong wire type of the time", []byte{0x09, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{"truncated timestamp", []byte{0x0a, 0x02, 0x08, 0x80}},
+	}
+	for _, tt := range tests {
+		for name, unmarshal := range unmarshalers {
+			if name == "Event" || name == "Series" {
+				continue
+			}
+			if err := unmarshal(tt.b); err == nil {
+				t.Errorf("%s: no error for %s", name, tt.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package solpospb
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+/* protobuf wire format, see https://protobuf.dev/programming-guides/encoding */
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+func appendTag(b []byte, field int, wire int) []byte {
+	return appendVarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+/* like proto3, fields with the zero value are left out */
+
+func appendDouble(b []byte, field int, v float64) []byte {
+	if v == 0 && !math.Signbit(v) {
+		return b
+	}
+	b = appendTag(b, field, wireFixed64)
+	bits := math.Float64bits(v)
+	for i := 0; i < 8; i++ {
+		b = append(b, byte(bits>>(8*i)))
+	}
+	return b
+}
+
+func appendUvarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return appendVarint(appendTag(b, field, wireVarint), v)
+}
+
+func appendSint32(b []byte, field int, v int32) []byte {
+	return appendUvarint(b, field, uint64(uint32(v<<1)^uint32(v>>31)))
+}
+
+func appendBytes(b []byte, field int, v []byte) []byte {
+	b = appendVarint(appendTag(b, field, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+// appendTimestamp appends a google.protobuf.Timestamp, zero times are left out
+func appendTimestamp(b []byte, field int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var ts []byte
+	ts = appendUvarint(ts, 1, uint64(t.Unix()))
+	ts = appendUvarint(ts, 2, uint64(t.Nanosecond()))
+	return appendBytes(b, field, ts)
+}
+
+// offset returns the UTC offset of t in seconds
+func offset(t time.Time) int32 {
+	_, o := t.Zone()
+	return int32(o)
+}
+
+// reader decodes the fields of a message
+type reader struct {
+	b []byte
+}
+
+func (r *reader) done() bool {
+	return len(r.b) == 0
+}
+
+// next returns field number and wire type of the next field
+func (r *reader) next() (field int, wire int, err error) {
+	tag, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	if tag>>3 == 0 || tag>>3 > math.MaxInt32 {
+		return 0, 0, errors.New("invalid field number")
+	}
+	return int(tag >> 3), int(tag & 7), nil
+}
+
+func (r *reader) varint() (uint64, error) {
+	var v uint64
+	for i := 0; i < 10; i++ {
+		if i >= len(r.b) {
+			return 0, errTruncated
+		}
+		c := r.b[i]
+		v |= uint64(c&0x7f) << (7 * uint(i))
+		if c < 0x80 {
+			r.b = r.b[i+1:]
+			return v, nil
+		}
+	}
+	return 0, errors.New("varint overflow")
+}
+
+func (r *reader) double() (float64, error) {
+	if len(r.b) < 8 {
+		return 0, errTruncated
+	}
+	var bits uint64
+	for i := 0; i < 8; i++ {
+		bits |= uint64(r.b[i]) << (8 * uint(i))
+	}
+	r.b = r.b[8:]
+	return math.Float64frombits(bits), nil
+}
+
+func (r *reader) sint32() (int32, error) {
+	v, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+	u := uint32(v)
+	return int32(u>>1) ^ -int32(u&1), nil
+}
+
+func (r *reader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, errTruncated
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v, nil
+}
+
+// skip skips the value of an unknown field
+func (r *reader) skip(wire int) error {
+	var err error
+	switch wire {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.double()
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		if len(r.b) < 4 {
+			return errTruncated
+		}
+		r.b = r.b[4:]
+	default:
+		err = errors.New("unsupported wire type " + strconv.Itoa(wire))
+	}
+	return err
+}
+
+// expect checks the wire type of a known field
+func expect(field int, wire int, want int) error {
+	if wire != want {
+		return errors.New("wrong wire type of field " + strconv.Itoa(field))
+	}
+	return nil
+}
+
+// timestamp decodes a google.protobuf.Timestamp
+func timestamp(b []byte) (seconds int64, nanos int64, err error) {
+	r := reader{b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return 0, 0, err
+		}
+		if (field == 1 || field == 2) && wire == wireVarint {
+			v, err := r.varint()
+			if err != nil {
+				return 0, 0, err
+			}
+			if field == 1 {
+				seconds = int64(v)
+			} else {
+				nanos = int64(int32(v))
+			}
+			continue
+		}
+		err = r.skip(wire)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return seconds, nanos, nil
+}
+
+// localTime returns the time of a timestamp in the zone of the UTC offset
+func localTime(seconds int64, nanos int64, offset int32, present bool) time.Time {
+	if !present {
+		return time.Time{}
+	}
+	loc := time.UTC
+	if offset != 0 {
+		loc = time.FixedZone("", int(offset))
+	}
+	return time.Unix(seconds, nanos).In(loc)
+}
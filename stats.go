@@ -0,0 +1,106 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// IrradianceSample is an irradiance at a time, computed (e.g. clear-sky) or measured
+type IrradianceSample struct {
+	Time  time.Time // Time of the sample
+	Value float64   // Irradiance, W/sq m
+}
+
+// DailyInsolation is the insolation of a single day
+type DailyInsolation struct {
+	Date         time.Time // Local midnight of the day
+	Insolation   float64   // Insolation, kWh/sq m
+	PeakSunHours float64   // Hours of 1000 W/sq m giving the same insolation, numerically equal to Insolation
+}
+
+// ExceedancePoint is a point of an exceedance curve
+type ExceedancePoint struct {
+	Insolation  float64 // Daily insolation, kWh/sq m
+	Probability float64 // Fraction of days with at least this insolation
+}
+
+// InsolationStatistics are statistics of the daily insolation of an irradiance series
+type InsolationStatistics struct {
+	Days       []DailyInsolation // Insolation of every day of the series, in time order
+	Mean       float64           // Mean daily insolation, kWh/sq m
+	Min        float64           // Lowest daily insolation, kWh/sq m
+	Max        float64           // Highest daily insolation, kWh/sq m
+	P50        float64           // Daily insolation exceeded on 50 % of the days (median), kWh/sq m
+	P90        float64           // Daily insolation exceeded on 90 % of the days, kWh/sq m
+	Exceedance []ExceedancePoint // Exceedance curve, from the highest to the lowest daily insolation
+}
+
+// Percentile returns the daily insolation exceeded on the given fraction of the days (0.9 for P90), interpolated
+// linearly on the exceedance curve
+func (s InsolationStatistics) Percentile(exceedance float64) float64 {
+	n := len(s.Exceedance)
+	if n == 0 {
+		return math.NaN()
+	}
+	/* point i of the curve has the probability (i+1)/n */
+	pos := exceedance*float64(n) - 1.0
+	i := int(math.Floor(pos))
+	if i < 0 {
+		return s.Exceedance[0].Insolation
+	}
+	if i >= n-1 {
+		return s.Exceedance[n-1].Insolation
+	}
+	return s.Exceedance[i].Insolation + (pos-float64(i))*(s.Exceedance[i+1].Insolation-s.Exceedance[i].Insolation)
+}
+
+// Statistics integrates an irradiance series (W/sq m, times not decreasing) to daily insolation, local to the times of
+// the samples, and returns its statistics: peak sun hours per day, mean, P50, P90 and the exceedance curve. Every sample
+// stands for the time until the next one, the last for the step before it, gaps should be filled (or merged) first.
+func Statistics(series []IrradianceSample) (InsolationStatistics, error) {
+	var s InsolationStatistics
+	if len(series) == 0 {
+		return s, errors.New("Please fix series, at least one sample is required")
+	}
+	for i, sample := range series {
+		if i > 0 && sample.Time.Before(series[i-1].Time) {
+			return InsolationStatistics{}, errors.New("Please fix series, times must not decrease")
+		}
+		err := finite("irradiance", sample.Value)
+		if err != nil {
+			return InsolationStatistics{}, err
+		}
+		y, m, d := sample.Time.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, sample.Time.Location())
+		if len(s.Days) == 0 || !s.Days[len(s.Days)-1].Date.Equal(day) {
+			s.Days = append(s.Days, DailyInsolation{Date: day})
+		}
+		var step time.Duration
+		if i+1 < len(series) {
+			step = series[i+1].Time.Sub(sample.Time)
+		} else if i > 0 {
+			step = sample.Time.Sub(series[i-1].Time)
+		}
+		s.Days[len(s.Days)-1].Insolation += sample.Value * step.Hours() / 1000.0
+	}
+
+	s.Min, s.Max = math.Inf(1), math.Inf(-1)
+	s.Exceedance = make([]ExceedancePoint, len(s.Days))
+	for i := range s.Days {
+		s.Days[i].PeakSunHours = s.Days[i].Insolation
+		s.Mean += s.Days[i].Insolation
+		s.Min = math.Min(s.Min, s.Days[i].Insolation)
+		s.Max = math.Max(s.Max, s.Days[i].Insolation)
+		s.Exceedance[i].Insolation = s.Days[i].Insolation
+	}
+	s.Mean /= float64(len(s.Days))
+	sort.Slice(s.Exceedance, func(i, j int) bool { return s.Exceedance[i].Insolation > s.Exceedance[j].Insolation })
+	for i := range s.Exceedance {
+		s.Exceedance[i].Probability = float64(i+1) / float64(len(s.Exceedance))
+	}
+	s.P50 = s.Percentile(0.5)
+	s.P90 = s.Percentile(0.9)
+	return s, nil
+}
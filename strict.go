@@ -0,0 +1,62 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// ErrNotComputed is returned (wrapped) if an output was requested whose function did not run in the last calculation
+var ErrNotComputed = errors.New("output not computed")
+
+// outputFunctions are the functions calculating outputs, LDoy only selects the date input
+const outputFunctions = LGeom | LZenetr | LSsha | LSbcf | LTst | LSrss | LSolazm | LRefrac | LAmass | LPrime | LTilt | LEtr
+
+func (sp *PosData) SetStrict(strict bool) {
+	sp.Strict = strict
+}
+
+func (sp *PosData) GetStrict() bool {
+	return sp.Strict
+}
+
+func (sp *PosData) Computed(function SPFunctions) error {
+	missing := function & outputFunctions &^ sp.computed
+	if missing == 0 {
+		return nil
+	}
+	var names []string
+	for flag := LGeom; flag <= LEtr; flag <<= 1 {
+		if missing.HasFlag(flag) {
+			names = append(names, flag.String())
+		}
+	}
+	return wrap(ErrNotComputed, strings.Join(names, ", "))
+}
+
+func (sp *PosData) GetOutput(name string) (float64, error) {
+	for flag := LGeom; flag <= LEtr; flag <<= 1 {
+		o := sp.outputs(flag)
+		if flag == LRefrac {
+			o = append(o, output{"zenref", &sp.Zenref})
+		}
+		for _, v := range o {
+			if v.name != name {
+				continue
+			}
+			err := sp.Computed(flag)
+			if err != nil {
+				return math.NaN(), wrap(err, name)
+			}
+			return *v.value, nil
+		}
+	}
+	return math.NaN(), errors.New("Please fix the output name " + name + ", see ResultColumns")
+}
+
+// invalidate sets the outputs of the given functions to NaN
+func (sp *PosData) invalidate(functions SPFunctions) {
+	for _, o := range sp.outputs(functions) {
+		*o.value = math.NaN()
+	}
+}
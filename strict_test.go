@@ -0,0 +1,160 @@
+package solpos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetOutput(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), 52.52, 13.40, WithFunction(SZenetr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zenetr, err := sp.GetOutput("zenetr")
+	if err != nil || zenetr != sp.GetZenetr() {
+		t.Errorf("GetOutput(zenetr) = %v, %v, want %v", zenetr, err, sp.GetZenetr())
+	}
+	for _, name := range []string{"etr", "zenref", "azim"} {
+		if _, err := sp.GetOutput(name); !errors.Is(err, ErrNotComputed) {
+			t.Errorf("GetOutput(%s) error = %v, want ErrNotComputed", name, err)
+		}
+	}
+	if _, err := sp.GetOutput("nonsense"); err == nil || errors.Is(err, ErrNotComputed) {
+		t.Errorf("GetOutput(nonsense) error = %v", err)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), 52.52, 13.40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp.SetStrict(true)
+	sp.SetFunction(SZenetr)
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(sp.GetEtr()) {
+		t.Errorf("GetEtr() = %v, want NaN", sp.GetEtr())
+	}
+	if err := sp.Computed(LEtr); !errors.Is(err, ErrNotComputed) {
+		t.Errorf("Computed(LEtr) = %v, want ErrNotComputed", err)
+	}
+}
+
+func TestStrictJSONL(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), 52.52, 13.40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp.SetStrict(true)
+	sp.SetFunction(SZenetr)
+	var buf bytes.Buffer
+	sink := NewJSONLSink(&buf)
+	err = sp.StreamSeries(sp.Getdate(), sp.Getdate().Add(2*time.Hour), time.Hour, sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	for _, line := range lines {
+		var r struct {
+			Time   time.Time `json:"time"`
+			Zenetr *float64  `json:"zenetr"`
+			Etr    *float64  `json:"etr"`
+		}
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Zenetr == nil || r.Etr != nil {
+			t.Errorf("zenetr = %v, etr = %v, want a value and null: %s", r.Zenetr, r.Etr, line)
+		}
+	}
+}
+
+func TestResultMarshalJSON(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), 89.995, 13.40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := sp.GetResult()
+	r.Prime = 1e-7
+	r.Unprime = 1e21
+	r.SunObstructed = true
+	got, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	/* finite results encode like the plain struct */
+	type plain Result
+	want, err := json.Marshal(plain(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Warnings) == 0 {
+		t.Fatal("want warnings near the pole")
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestStrictOptionalParameter(t *testing.T) {
+	dt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	sp, err := NewSolpos(dt, 52.52, 13.40, map[string]interface{}{"strict": true, "function": SZenetr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sp.GetStrict() || !math.IsNaN(sp.GetEtr()) {
+		t.Errorf("strict = %v, etr = %v, want true, NaN", sp.GetStrict(), sp.GetEtr())
+	}
+	sp, err = New(dt, 52.52, 13.40, WithStrict(true), WithFunction(SZenetr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sp.GetStrict() || !math.IsNaN(sp.GetEtr()) {
+		t.Errorf("strict = %v, etr = %v, want true, NaN", sp.GetStrict(), sp.GetEtr())
+	}
+}
+
+func TestSolarNoonComputed(t *testing.T) {
+	sp, err := New(time.Date(2021, 6, 21, 12, 0, 0, 0, time.UTC), 52.52, 13.40, WithFunction(SZenetr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sp.GetSolarNoon(); !errors.Is(err, ErrNotComputed) {
+		t.Errorf("GetSolarNoon() error = %v, want ErrNotComputed", err)
+	}
+	if _, err := sp.GetMaxElevation(); err != nil {
+		t.Errorf("GetMaxElevation() error = %v", err)
+	}
+	sp.SetFunction(SAll)
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	noon, err := sp.GetSolarNoon()
+	if err != nil || noon.Hour() != 11 {
+		t.Errorf("GetSolarNoon() = %v, %v, want about 11:00 UTC", noon, err)
+	}
+	elevation, err := sp.GetMaxElevation()
+	if err != nil || math.Abs(elevation-60.95) > 0.1 {
+		t.Errorf("GetMaxElevation() = %v, %v, want about 60.95", elevation, err)
+	}
+	sp.SetFunction(SRefrac &^ LGeom)
+	if err := sp.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sp.GetMaxElevation(); !errors.Is(err, ErrNotComputed) {
+		t.Errorf("GetMaxElevation() error = %v, want ErrNotComputed", err)
+	}
+}
@@ -0,0 +1,29 @@
+package solpos
+
+import (
+	"math"
+	"time"
+)
+
+// SubsolarPoint returns the latitude and longitude (degrees north and east) of the point where the sun is at zenith at dt
+func SubsolarPoint(dt time.Time) (latitude float64, longitude float64, err error) {
+	/* the subsolar point does not depend on the location */
+	sp, err := NewSolpos(dt, 0.0, 0.0, map[string]interface{}{"function": LGeom})
+	if err != nil {
+		return 0, 0, err
+	}
+	latitude, longitude = sp.GetSubsolarPoint()
+	return latitude, longitude, nil
+}
+
+func (sp *PosData) GetSubsolarPoint() (latitude float64, longitude float64) {
+	/* the sun is at zenith where the hour angle is 0, west of Greenwich by the Greenwich hour angle gmst - rascen */
+	longitude = math.Mod(sp.Rascen-sp.Gmst*15.0, 360.0)
+	switch {
+	case longitude < -180.0:
+		longitude += 360.0
+	case longitude >= 180.0:
+		longitude -= 360.0
+	}
+	return sp.Declin, longitude
+}
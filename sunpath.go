@@ -0,0 +1,191 @@
+package solpos
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SunPathConfig configures NewSunPath
+type SunPathConfig struct {
+	Dates   []time.Time   // Days of the day curves (the date fields in the location of the site), e.g. SolsticeEquinoxDates or MonthlyDates
+	Step    time.Duration // Sampling interval of the day curves, DEFAULT (0) = 10 minutes
+	Options *Options      // Optional parameters, DEFAULT (nil) = DefaultOptions()
+}
+
+// SunPathPoint is a position of the sun on a sun path diagram
+type SunPathPoint struct {
+	Time      time.Time
+	Azimuth   float64 // Solar azimuth angle, N=0, E=90, S=180, W=270
+	Elevation float64 // Refracted solar elevation angle, degrees
+}
+
+// SunPathCurve is a line of a sun path diagram, the points above the horizon in order
+type SunPathCurve struct {
+	Label  string // Date (2006-01-02) of a day curve, true solar time (15:00) of an hour line
+	Points []SunPathPoint
+}
+
+// SunPath is the data of a sun path diagram of a site: the path of the sun across the sky on selected days and the
+// hour lines connecting the positions at the same true solar time on those days
+type SunPath struct {
+	Site  Site
+	Days  []SunPathCurve // One curve per date, from sunrise to sunset
+	Hours []SunPathCurve // One line per full hour of true solar time with the sun above the horizon on any of the dates
+}
+
+// SolsticeEquinoxDates returns the usual dates of the solstices and equinoxes of a year (Mar 20, Jun 21, Sep 22, Dec 21)
+func SolsticeEquinoxDates(year int) []time.Time {
+	return []time.Time{
+		time.Date(year, time.March, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.June, 21, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.September, 22, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.December, 21, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// MonthlyDates returns the 21st of every month of a year, the dates of the classic sun path charts
+func MonthlyDates(year int) []time.Time {
+	dates := make([]time.Time, 12)
+	for i := range dates {
+		dates[i] = time.Date(year, time.Month(i+1), 21, 0, 0, 0, 0, time.UTC)
+	}
+	return dates
+}
+
+// NewSunPath calculates the day curves and hour lines of a sun path diagram for a site, e.g. for shading studies and
+// passive solar design
+func NewSunPath(site Site, config SunPathConfig) (*SunPath, error) {
+	if len(config.Dates) == 0 {
+		return nil, errors.New("Please fix dates, at least one is required")
+	}
+	step := config.Step
+	if step == 0 {
+		step = 10 * time.Minute
+	}
+	if step < 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	options := DefaultOptions()
+	if config.Options != nil {
+		options = *config.Options
+	}
+	loc := site.location()
+	first := config.Dates[0]
+	s, err := NewSolposWithOptions(time.Date(first.Year(), first.Month(), first.Day(), 12, 0, 0, 0, loc), site.Latitude, site.Longitude, options)
+	if err != nil {
+		return nil, err
+	}
+	sp := s.(*PosData)
+	p := &SunPath{Site: site}
+	/* the points of the hour lines with the declination of their day */
+	type hourPoint struct {
+		declin float64
+		point  SunPathPoint
+	}
+	var hours [24][]hourPoint
+	for _, d := range config.Dates {
+		start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+		results, err := sp.CalculateSeries(start, start.AddDate(0, 0, 1), step)
+		if err != nil {
+			return nil, err
+		}
+		day := SunPathCurve{Label: start.Format("2006-01-02")}
+		for _, r := range results {
+			if r.Elevref >= 0.0 {
+				day.Points = append(day.Points, SunPathPoint{Time: r.Time, Azimuth: r.Azim, Elevation: r.Elevref})
+			}
+		}
+		p.Days = append(p.Days, day)
+
+		/* the hours of true solar time are whole hours off solar noon */
+		noon, err := solarNoon(start, site.Longitude)
+		if err != nil {
+			return nil, err
+		}
+		for h := range hours {
+			sp.SetDate(noon.Add(time.Duration(h-12) * time.Hour))
+			err = sp.Calculate()
+			if err != nil {
+				return nil, err
+			}
+			if sp.Elevref >= 0.0 {
+				hours[h] = append(hours[h], hourPoint{sp.Declin, SunPathPoint{Time: sp.Getdate(), Azimuth: sp.Azim, Elevation: sp.Elevref}})
+			}
+		}
+	}
+	for h, points := range hours {
+		if len(points) == 0 {
+			continue
+		}
+		/* an hour line runs from the lowest to the highest declination, whatever the order of the dates */
+		sort.SliceStable(points, func(i, j int) bool { return points[i].declin < points[j].declin })
+		line := SunPathCurve{Label: fmt.Sprintf("%02d:00", h)}
+		for _, hp := range points {
+			line.Points = append(line.Points, hp.point)
+		}
+		p.Hours = append(p.Hours, line)
+	}
+	return p, nil
+}
+
+// WriteSVG renders the sun path as polar plot of size x size pixels: the horizon is the outer circle and the zenith the
+// center, north is up and east to the right. The curves carry their label as title (tooltip).
+func (p *SunPath) WriteSVG(w io.Writer, size int) error {
+	if size < 100 {
+		return errors.New("Please fix size, must be at least 100 pixels")
+	}
+	c := float64(size) / 2.0
+	radius := c - 24.0
+	xy := func(azimuth float64, elevation float64) (float64, float64) {
+		r := radius * (90.0 - elevation) / 90.0
+		return c + r*math.Sin(raddeg*azimuth), c - r*math.Cos(raddeg*azimuth)
+	}
+	num := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", size, size, size, size)
+	bw.WriteString(`<g fill="none" stroke="#999">` + "\n")
+	for e := 0.0; e < 90.0; e += 30.0 {
+		dash := ""
+		if e > 0.0 {
+			dash = ` stroke-dasharray="4 4"`
+		}
+		fmt.Fprintf(bw, `<circle cx="%s" cy="%s" r="%s"%s/>`+"\n", num(c), num(c), num(radius*(90.0-e)/90.0), dash)
+	}
+	for a := 0.0; a < 360.0; a += 30.0 {
+		x, y := xy(a, 0.0)
+		fmt.Fprintf(bw, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke-dasharray="4 4"/>`+"\n", num(c), num(c), num(x), num(y))
+	}
+	bw.WriteString("</g>\n")
+	for i, label := range []string{"N", "E", "S", "W"} {
+		/* outside of the horizon, within the margin */
+		x, y := xy(float64(i)*90.0, -90.0*12.0/radius)
+		fmt.Fprintf(bw, `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n", num(x), num(y), label)
+	}
+	curve := func(curve SunPathCurve, style string) {
+		bw.WriteString(`<polyline ` + style + ` points="`)
+		for i, pt := range curve.Points {
+			if i > 0 {
+				bw.WriteByte(' ')
+			}
+			x, y := xy(pt.Azimuth, pt.Elevation)
+			bw.WriteString(num(x) + "," + num(y))
+		}
+		bw.WriteString(`"><title>` + curve.Label + "</title></polyline>\n")
+	}
+	for _, h := range p.Hours {
+		curve(h, `fill="none" stroke="#1f77b4" stroke-width="1"`)
+	}
+	for _, d := range p.Days {
+		curve(d, `fill="none" stroke="#ff7f0e" stroke-width="2"`)
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
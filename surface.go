@@ -0,0 +1,180 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+/*============================================================================
+*    Incidence on an arbitrary surface
+*
+*    Generalizes the tilt function (S_TILT) to a surface given by its normal
+*    vector, e.g. a terrain facet, a panel on a vehicle or a facet of a
+*    curved structure. Vectors are in local east, north, up coordinates.
+*----------------------------------------------------------------------------*/
+
+// SurfaceIncidence is the sun position relative to a surface
+type SurfaceIncidence struct {
+	Cosinc    float64 // Cosine of solar incidence angle on the surface
+	Incidence float64 // Solar incidence angle, degrees from the surface normal, above 90 when the sun is behind the surface
+	Etrtilt   float64 // Extraterrestrial (top-of-atmosphere) W/sq m irradiance on the surface (plane of array)
+}
+
+// SunVector returns the unit vector (east, north, up) pointing to the refraction corrected sun position of r
+func SunVector(r Result) [3]float64 {
+	sz := math.Sin(raddeg * r.Zenref)
+	return [3]float64{
+		sz * math.Sin(raddeg*r.Azim),
+		sz * math.Cos(raddeg*r.Azim),
+		math.Cos(raddeg * r.Zenref),
+	}
+}
+
+// SunVectorECEF returns the unit vector in Earth-centered, Earth-fixed coordinates (x toward latitude 0 and longitude 0,
+// z toward the north pole) pointing from the location of r to its refraction corrected sun position
+func SunVectorECEF(r Result) [3]float64 {
+	return ENUToECEF(SunVector(r), r.Latitude, r.Longitude)
+}
+
+// ENUToECEF rotates a direction (east, north, up) at the given latitude and longitude (degrees, geodetic like the inputs)
+// into Earth-centered, Earth-fixed coordinates
+func ENUToECEF(v [3]float64, latitude float64, longitude float64) [3]float64 {
+	sl, cl := math.Sincos(raddeg * latitude)
+	so, co := math.Sincos(raddeg * longitude)
+	return [3]float64{
+		-so*v[0] - sl*co*v[1] + cl*co*v[2],
+		co*v[0] - sl*so*v[1] + cl*so*v[2],
+		cl*v[1] + sl*v[2],
+	}
+}
+
+// SurfaceNormal returns the unit normal vector (east, north, up) of a surface with the given tilt from horizontal
+// and aspect (direction it faces) N=0, E=90, S=180, W=270, the surface of the Tilt and Aspect inputs
+func SurfaceNormal(tilt float64, aspect float64) [3]float64 {
+	st := math.Sin(raddeg * tilt)
+	return [3]float64{
+		st * math.Sin(raddeg*aspect),
+		st * math.Cos(raddeg*aspect),
+		math.Cos(raddeg * tilt),
+	}
+}
+
+// IncidenceOnSurface returns the incidence of the sun of a calculated result (Zenref, Azim and Etrn are used) on the
+// surface with the given normal vector (east, north, up). The normal does not need to be of unit length.
+func IncidenceOnSurface(r Result, normal [3]float64) (SurfaceIncidence, error) {
+	length := math.Sqrt(normal[0]*normal[0] + normal[1]*normal[1] + normal[2]*normal[2])
+	err := finite("normal", length)
+	if err != nil {
+		return SurfaceIncidence{}, err
+	}
+	if length == 0.0 {
+		return SurfaceIncidence{}, errors.New("Please fix normal, must not be the zero vector")
+	}
+	sun := SunVector(r)
+	cosinc := (sun[0]*normal[0] + sun[1]*normal[1] + sun[2]*normal[2]) / length
+	/* rounding may push the cosine slightly out of [-1, 1] */
+	cosinc = math.Max(-1.0, math.Min(1.0, cosinc))
+	s := SurfaceIncidence{Cosinc: cosinc, Incidence: degrad * math.Acos(cosinc)}
+	if cosinc > 0.0 {
+		s.Etrtilt = r.Etrn * cosinc
+	}
+	return s, nil
+}
+
+func (sp *PosData) PositionOnSurface(normal [3]float64) (SurfaceIncidence, error) {
+	err := sp.Computed(LSolazm | LRefrac | LEtr)
+	if err != nil {
+		return SurfaceIncidence{}, err
+	}
+	return IncidenceOnSurface(sp.GetResult(), normal)
+}
+
+func (sp *PosData) SunVectorENU() [3]float64 {
+	return SunVector(sp.GetResult())
+}
+
+func (sp *PosData) SunVectorECEF() [3]float64 {
+	return SunVectorECEF(sp.GetResult())
+}
+
+// Surface is a flat surface given by its tilt and the direction it faces, like the Tilt and Aspect inputs
+type Surface struct {
+	Tilt    float64 // Degrees tilt from horizontal of the surface
+	Azimuth float64 // Direction the surface faces, N=0, E=90, S=180, W=270
+}
+
+// TiltResult is the sun on a Surface
+type TiltResult struct {
+	Cosinc  float64 // Cosine of solar incidence angle on the surface
+	Etrtilt float64 // Extraterrestrial (top-of-atmosphere) W/sq m global irradiance on the surface
+}
+
+func (sp *PosData) IncidenceOn(surfaces []Surface) []TiltResult {
+	/* the sun geometry of the calculation is shared, only the orientation of the surface differs */
+	results := make([]TiltResult, len(surfaces))
+	for i, s := range surfaces {
+		results[i].Cosinc, results[i].Etrtilt = sp.incidence(s.Tilt, s.Azimuth)
+	}
+	return results
+}
+
+// Facet is a flat element of a meshed surface
+type Facet struct {
+	Normal [3]float64 // Normal vector (east, north, up) on the side facing outward, e.g. toward the panel's front
+	Area   float64    // Area, sq m
+}
+
+// MeshIncidence is the area-weighted sun incidence on a meshed surface
+type MeshIncidence struct {
+	Area       float64 // Total area of the facets, sq m
+	SunlitArea float64 // Area of the facets facing the sun (incidence below 90 degrees), sq m
+	Cosinc     float64 // Area-weighted mean cosine of solar incidence, facets facing away from the sun count as 0
+	Etrtilt    float64 // Area-weighted mean extraterrestrial (top-of-atmosphere) irradiance on the surface, W/sq m
+	POA        float64 // Area-weighted mean plane-of-array irradiance of the given clear sky (isotropic sky, ground reflection), W/sq m
+	Power      float64 // Irradiance integrated over the surface, POA times Area, W
+}
+
+// IncidenceOnMesh integrates the incidence of the sun of a calculated result over a meshed surface, e.g. a curved
+// vehicle roof or membrane structure. The plane-of-array irradiance of every facet is the beam part of cs on the facet,
+// the isotropic sky diffuse part it sees and the part reflected by the ground with the given albedo, pass a zero
+// ClearSky if only the extraterrestrial irradiance is needed. Self-shading of the surface is not accounted for.
+func IncidenceOnMesh(r Result, facets []Facet, cs ClearSky, albedo float64) (MeshIncidence, error) {
+	var m MeshIncidence
+	for i, f := range facets {
+		err := finite("area of facet "+strconv.Itoa(i), f.Area)
+		if err != nil {
+			return MeshIncidence{}, err
+		}
+		if f.Area < 0.0 {
+			return MeshIncidence{}, errors.New("Please fix the area of facet " + strconv.Itoa(i) + ", must not be negative")
+		}
+		s, err := IncidenceOnSurface(r, f.Normal)
+		if err != nil {
+			return MeshIncidence{}, wrap(err, "facet "+strconv.Itoa(i))
+		}
+		length := math.Sqrt(f.Normal[0]*f.Normal[0] + f.Normal[1]*f.Normal[1] + f.Normal[2]*f.Normal[2])
+		ct := f.Normal[2] / length
+		poa := isotropicPOA(cs, s.Cosinc, ct, albedo)
+		m.Area += f.Area
+		if s.Cosinc > 0.0 {
+			m.SunlitArea += f.Area
+			m.Cosinc += f.Area * s.Cosinc
+		}
+		m.Etrtilt += f.Area * s.Etrtilt
+		m.Power += f.Area * poa
+	}
+	if m.Area > 0.0 {
+		m.Cosinc /= m.Area
+		m.Etrtilt /= m.Area
+		m.POA = m.Power / m.Area
+	}
+	return m, nil
+}
+
+// isotropicPOA returns the plane-of-array irradiance of a clear sky on a surface with the given cosine of incidence
+// and cosine of tilt: the beam part, the isotropic sky diffuse part and the part reflected by the ground, the view
+// factors to sky and ground follow from the tilt
+func isotropicPOA(cs ClearSky, cosinc float64, costilt float64, albedo float64) float64 {
+	return cs.DNI*math.Max(0.0, cosinc) + cs.DHI*(1.0+costilt)/2.0 + cs.GHI*albedo*(1.0-costilt)/2.0
+}
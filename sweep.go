@@ -0,0 +1,78 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+)
+
+// SweepPattern selects the shape of a sun acquisition sweep
+type SweepPattern int
+
+const (
+	SweepSpiral SweepPattern = iota // Archimedean spiral outward from the predicted position, most likely positions first
+	SweepRaster                     // Back-and-forth rows from low to high elevation
+)
+
+// SweepPoint is a single pointing of a sun acquisition sweep
+type SweepPoint struct {
+	CrossElevation float64 // On-sky offset in azimuth direction from the predicted position, degrees
+	ElevationDelta float64 // Offset in elevation from the predicted position, degrees
+	Azimuth        float64 // Absolute azimuth to point to, N=0, E=90, S=180, W=270
+	Elevation      float64 // Absolute elevation to point to, degrees
+}
+
+// AcquisitionSweep returns pointings searching the sky around the predicted (refracted) sun position of r, e.g. after a power loss
+// or when feedback sensors disagree. The pointings cover a circle with radius uncertainty (degrees, the expected pointing or
+// position error) and are spaced by step (degrees, typically a bit less than the field of view of the sun sensor).
+// Offsets are on-sky angles, the azimuth is widened toward the zenith accordingly.
+func AcquisitionSweep(r Result, uncertainty float64, step float64, pattern SweepPattern) ([]SweepPoint, error) {
+	if uncertainty <= 0.0 {
+		return nil, errors.New("Please fix uncertainty, must be positive")
+	}
+	if step <= 0.0 || step > uncertainty {
+		return nil, errors.New("Please fix step, must be positive and not larger than uncertainty")
+	}
+	var points []SweepPoint
+	switch pattern {
+	case SweepRaster:
+		n := int(math.Floor(uncertainty / step))
+		for row := -n; row <= n; row++ {
+			dy := float64(row) * step
+			/* back and forth, every other row in reverse */
+			for col := -n; col <= n; col++ {
+				c := col
+				if (row+n)%2 == 1 {
+					c = -col
+				}
+				dx := float64(c) * step
+				if math.Hypot(dx, dy) <= uncertainty {
+					points = append(points, sweepPoint(r, dx, dy))
+				}
+			}
+		}
+	default:
+		/* spiral radius grows by one step per turn, points are about one step apart along the arc */
+		points = append(points, sweepPoint(r, 0.0, 0.0))
+		theta := 2.0 * math.Pi
+		for {
+			radius := step * theta / (2.0 * math.Pi)
+			if radius > uncertainty {
+				break
+			}
+			points = append(points, sweepPoint(r, radius*math.Cos(theta), radius*math.Sin(theta)))
+			theta += step / radius
+		}
+	}
+	return points, nil
+}
+
+func sweepPoint(r Result, crossElevation float64, elevationDelta float64) SweepPoint {
+	p := SweepPoint{CrossElevation: crossElevation, ElevationDelta: elevationDelta, Elevation: r.Elevref + elevationDelta}
+	/* (limit the widening close to the zenith) */
+	cosel := math.Max(math.Cos(raddeg*p.Elevation), 0.01)
+	p.Azimuth = math.Mod(r.Azim+crossElevation/cosel, 360.0)
+	if p.Azimuth < 0.0 {
+		p.Azimuth += 360.0
+	}
+	return p
+}
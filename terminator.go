@@ -0,0 +1,233 @@
+package solpos
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// TerminatorConfig configures NewTerminator
+type TerminatorConfig struct {
+	Depression float64 // Depression angle of the sun below the horizon along the line, degrees (0 - 90), DEFAULT (0) = the horizon, e.g. 6, 12 or 18 for the twilights
+	Refraction bool    // The depression of the refracted sun (standard atmosphere, 1013 mb and 10 degrees C) instead of the geometric one
+	Points     int     // Number of points of the line, DEFAULT (0) = 360
+}
+
+// Terminator is the line between day and night at one instant and the night side of the Earth, in [longitude, latitude]
+// points (degrees east and north, the order of GeoJSON). Lines and rings crossing the antimeridian are split there.
+type Terminator struct {
+	Time              time.Time
+	Elevation         float64        // Unrefracted elevation of the sun along the line, degrees
+	SubsolarLatitude  float64        // Latitude of the point where the sun is at zenith, degrees north
+	SubsolarLongitude float64        // Longitude of the point where the sun is at zenith, degrees east
+	Line              [][][2]float64 // Parts of the terminator
+	Night             [][][2]float64 // Closed counterclockwise rings of the area with the sun below Elevation
+}
+
+// NewTerminator calculates the terminator at dt from the subsolar point, without scanning a grid. Where the night contains a
+// pole the line is sampled at Points longitudes from -180 to 180, otherwise it is a circle around the antisolar point.
+func NewTerminator(dt time.Time, config TerminatorConfig) (*Terminator, error) {
+	if math.IsNaN(config.Depression) || config.Depression < 0.0 || config.Depression >= 90.0 {
+		return nil, errors.New("Please fix depression [0 - 90)")
+	}
+	n := config.Points
+	if n == 0 {
+		n = 360
+	}
+	if n < 3 {
+		return nil, errors.New("Please fix points, must be at least 3")
+	}
+	s, err := NewSolpos(dt, 0.0, 0.0, map[string]interface{}{"function": LGeom})
+	if err != nil {
+		return nil, err
+	}
+	sp := s.(*PosData)
+	t := &Terminator{Time: dt, Elevation: -config.Depression}
+	if config.Refraction {
+		t.Elevation = sp.unrefracted(-config.Depression)
+	}
+	t.SubsolarLatitude, t.SubsolarLongitude = sp.GetSubsolarPoint()
+	declin, h := t.SubsolarLatitude, t.Elevation
+	switch {
+	case declin < h:
+		/* winter of the northern hemisphere, the night contains the north pole */
+		t.Line = [][][2]float64{t.meridianLine(n, true)}
+		t.Night = [][][2]float64{poleRing(t.Line[0], 90.0)}
+	case declin > -h:
+		t.Line = [][][2]float64{t.meridianLine(n, false)}
+		t.Night = [][][2]float64{poleRing(reversed(t.Line[0]), -90.0)}
+	default:
+		ring := t.antisolarCircle(n)
+		t.Line = splitAntimeridian(ring)
+		t.Night = clipAntimeridian(ring)
+	}
+	return t, nil
+}
+
+// meridianLine returns the line from longitude -180 to 180, on each meridian the latitude at which the sun is at Elevation
+// with the night to the north (or south)
+func (t *Terminator) meridianLine(n int, north bool) [][2]float64 {
+	sd, cd := math.Sincos(raddeg * t.SubsolarLatitude)
+	sh := math.Sin(raddeg * t.Elevation)
+	line := make([][2]float64, 0, n+1)
+	for i := 0; i <= n; i++ {
+		lon := -180.0 + 360.0*float64(i)/float64(n)
+		/* sin(elevation) = sd sin(lat) + cd cos(hrang) cos(lat) = r cos(lat - alpha) */
+		a, b := sd, cd*math.Cos(raddeg*(lon-t.SubsolarLongitude))
+		r, alpha := math.Hypot(a, b), math.Atan2(a, b)
+		beta := math.Acos(math.Max(-1.0, math.Min(1.0, sh/r)))
+		/* the elevation decreases toward the north at alpha + beta and toward the south at alpha - beta */
+		lat := alpha - beta
+		if north {
+			lat = alpha + beta
+		}
+		line = append(line, [2]float64{lon, math.Max(-90.0, math.Min(90.0, degrad*lat))})
+	}
+	return line
+}
+
+// antisolarCircle returns the closed counterclockwise circle of points at the angular distance 90 + Elevation from the
+// antisolar point, with continuous longitudes (not wrapped at the antimeridian)
+func (t *Terminator) antisolarCircle(n int) [][2]float64 {
+	sc, cc := math.Sincos(-raddeg * t.SubsolarLatitude)
+	sr, cr := math.Sincos(raddeg * (90.0 + t.Elevation))
+	lonc := t.SubsolarLongitude + 180.0
+	ring := make([][2]float64, 0, n+1)
+	for i := 0; i <= n; i++ {
+		/* decreasing bearings run counterclockwise on the map */
+		st, ct := math.Sincos(-2.0 * math.Pi * float64(i%n) / float64(n))
+		sl := sc*cr + cc*sr*ct
+		lon := lonc + degrad*math.Atan2(st*sr*cc, cr-sc*sl)
+		ring = append(ring, [2]float64{lon, degrad * math.Asin(sl)})
+	}
+	return ring
+}
+
+// poleRing closes a line running from west to east (or east to west) through the pole, counterclockwise for the north
+// pole with a line from west to east
+func poleRing(line [][2]float64, pole float64) [][2]float64 {
+	ring := append([][2]float64{}, line...)
+	last, first := line[len(line)-1], line[0]
+	return append(ring, [2]float64{last[0], pole}, [2]float64{first[0], pole}, first)
+}
+
+func reversed(points [][2]float64) [][2]float64 {
+	r := make([][2]float64, len(points))
+	for i, p := range points {
+		r[len(points)-1-i] = p
+	}
+	return r
+}
+
+// antimeridianShift returns the multiple of 360 moving a longitude just west (side < 0) or east of edge into -180 to 180
+func antimeridianShift(edge float64, side float64) float64 {
+	lon := edge + side
+	return -360.0 * math.Floor((lon+180.0)/360.0)
+}
+
+// splitAntimeridian splits a line with continuous longitudes into parts within -180 to 180
+func splitAntimeridian(points [][2]float64) [][][2]float64 {
+	var parts [][][2]float64
+	var part [][2]float64
+	for i, p := range points {
+		if i > 0 {
+			q := points[i-1]
+			for _, edge := range []float64{-180.0, 180.0} {
+				if (q[0] < edge) == (p[0] < edge) {
+					continue
+				}
+				lat := q[1] + (p[1]-q[1])*(edge-q[0])/(p[0]-q[0])
+				end := antimeridianShift(edge, q[0]-edge)
+				start := antimeridianShift(edge, p[0]-edge)
+				parts = append(parts, append(part, [2]float64{edge + end, lat}))
+				part = [][2]float64{{edge + start, lat}}
+			}
+		}
+		part = append(part, [2]float64{p[0] + antimeridianShift(p[0], 0.0), p[1]})
+	}
+	return append(parts, part)
+}
+
+// clipAntimeridian returns the parts of a closed ring with continuous longitudes within -180 to 180 as closed rings
+func clipAntimeridian(ring [][2]float64) [][][2]float64 {
+	var rings [][][2]float64
+	for _, shift := range []float64{-360.0, 0.0, 360.0} {
+		shifted := make([][2]float64, len(ring)-1)
+		for i, p := range ring[:len(ring)-1] {
+			shifted[i] = [2]float64{p[0] + shift, p[1]}
+		}
+		clipped := clipLongitude(clipLongitude(shifted, -180.0, 1.0), 180.0, -1.0)
+		if len(clipped) < 3 {
+			continue
+		}
+		rings = append(rings, append(clipped, clipped[0]))
+	}
+	return rings
+}
+
+// clipLongitude clips an open ring to the side of a meridian given by side (1 = east, -1 = west), Sutherland-Hodgman
+func clipLongitude(ring [][2]float64, edge float64, side float64) [][2]float64 {
+	inside := func(p [2]float64) bool {
+		return (p[0]-edge)*side >= 0.0
+	}
+	var out [][2]float64
+	for i, p := range ring {
+		q := ring[(i+len(ring)-1)%len(ring)]
+		if inside(p) != inside(q) {
+			lat := q[1] + (p[1]-q[1])*(edge-q[0])/(p[0]-q[0])
+			out = append(out, [2]float64{edge, lat})
+		}
+		if inside(p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// WriteGeoJSON writes the terminator as GeoJSON FeatureCollection (RFC 7946) of the features "terminator" (MultiLineString),
+// "night" (MultiPolygon) and "subsolar" (Point), for day and night overlays on maps
+func (t *Terminator) WriteGeoJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	properties := func(name string) {
+		bw.WriteString(`{"type":"Feature","properties":{"name":"` + name + `","time":"` + t.Time.Format(time.RFC3339) +
+			`","elevation":` + strconv.FormatFloat(t.Elevation, 'f', -1, 64) + `},"geometry":`)
+	}
+	points := func(points [][2]float64) {
+		bw.WriteByte('[')
+		for i, p := range points {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString("[" + strconv.FormatFloat(p[0], 'f', 5, 64) + "," + strconv.FormatFloat(p[1], 'f', 5, 64) + "]")
+		}
+		bw.WriteByte(']')
+	}
+	bw.WriteString(`{"type":"FeatureCollection","features":[`)
+	properties("terminator")
+	bw.WriteString(`{"type":"MultiLineString","coordinates":[`)
+	for i, part := range t.Line {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		points(part)
+	}
+	bw.WriteString("]}},")
+	properties("night")
+	bw.WriteString(`{"type":"MultiPolygon","coordinates":[`)
+	for i, ring := range t.Night {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.WriteByte('[')
+		points(ring)
+		bw.WriteByte(']')
+	}
+	bw.WriteString("]}},")
+	properties("subsolar")
+	bw.WriteString(`{"type":"Point","coordinates":[` + strconv.FormatFloat(t.SubsolarLongitude, 'f', 5, 64) + "," +
+		strconv.FormatFloat(t.SubsolarLatitude, 'f', 5, 64) + "]}}]}\n")
+	return bw.Flush()
+}
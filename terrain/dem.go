@@ -0,0 +1,160 @@
+// Package terrain computes shadows and irradiance over digital elevation models, e.g. for solar access mapping
+// of rooftops and terrain.
+package terrain
+
+import (
+	"errors"
+	"github.com/maltegrosse/go-solpos"
+	"math"
+	"time"
+)
+
+const (
+	raddeg = math.Pi / 180.0 /* converts from degrees to radians */
+	degrad = 180.0 / math.Pi /* converts from radians to degrees */
+)
+
+// sunRadius is the apparent angular radius of the sun, degrees
+const sunRadius = 0.2665
+
+// DEM is a digital elevation model, a regular grid of terrain (or surface) heights. Row 0 is the northern edge
+// and column 0 the western edge, like in most raster formats. The grid is small compared to the Earth, the sun
+// position is the same for all cells.
+type DEM struct {
+	Rows      int       // Number of rows (north to south)
+	Cols      int       // Number of columns (west to east)
+	CellSize  float64   // Edge length of a cell, meters
+	Heights   []float64 // Heights of the cells row by row (row-major), meters
+	Latitude  float64   // Latitude of the grid, degrees north (south negative)
+	Longitude float64   // Longitude of the grid, degrees east (west negative)
+	maxHeight float64
+}
+
+// NewDEM creates new instance of DEM, heights are given row by row starting at the northern edge
+func NewDEM(rows int, cols int, cellSize float64, heights []float64, latitude float64, longitude float64) (*DEM, error) {
+	if rows < 1 || cols < 1 {
+		return nil, errors.New("Please fix rows and cols, must be positive")
+	}
+	if cellSize <= 0 {
+		return nil, errors.New("Please fix cell size, must be positive")
+	}
+	if len(heights) != rows*cols {
+		return nil, errors.New("Please fix heights, must have rows * cols values")
+	}
+	d := &DEM{Rows: rows, Cols: cols, CellSize: cellSize, Heights: heights, Latitude: latitude, Longitude: longitude}
+	d.maxHeight = math.Inf(-1)
+	for _, h := range heights {
+		if math.IsNaN(h) || math.IsInf(h, 0) {
+			return nil, errors.New("Please fix heights, not a finite number")
+		}
+		d.maxHeight = math.Max(d.maxHeight, h)
+	}
+	return d, nil
+}
+
+// Height returns the height of a cell
+func (d *DEM) Height(row int, col int) float64 {
+	return d.Heights[row*d.Cols+col]
+}
+
+// height returns the bilinearly interpolated height at a fractional cell position, ok is false outside of the grid
+func (d *DEM) height(row float64, col float64) (h float64, ok bool) {
+	if row < 0 || col < 0 || row > float64(d.Rows-1) || col > float64(d.Cols-1) {
+		return 0, false
+	}
+	r0, c0 := int(row), int(col)
+	r1, c1 := r0+1, c0+1
+	if r1 > d.Rows-1 {
+		r1 = r0
+	}
+	if c1 > d.Cols-1 {
+		c1 = c0
+	}
+	fr, fc := row-float64(r0), col-float64(c0)
+	top := d.Height(r0, c0)*(1-fc) + d.Height(r0, c1)*fc
+	bottom := d.Height(r1, c0)*(1-fc) + d.Height(r1, c1)*fc
+	return top*(1-fr) + bottom*fr, true
+}
+
+// Horizon returns the elevation angle in degrees of the terrain horizon seen from the center of a cell toward
+// the azimuth (N=0, E=90, S=180, W=270), found by marching along the ray one cell size at a time
+func (d *DEM) Horizon(row int, col int, azimuth float64) float64 {
+	h0 := d.Height(row, col)
+	dc, dr := math.Sin(raddeg*azimuth), -math.Cos(raddeg*azimuth)
+	horizon := math.Inf(-1)
+	for i := 1; ; i++ {
+		dist := float64(i) * d.CellSize
+		/* nothing further away can rise above the horizon found so far */
+		if horizon > math.Inf(-1) && (d.maxHeight-h0)/dist <= horizon {
+			break
+		}
+		h, ok := d.height(float64(row)+float64(i)*dr, float64(col)+float64(i)*dc)
+		if !ok {
+			break
+		}
+		horizon = math.Max(horizon, (h-h0)/dist)
+	}
+	if math.IsInf(horizon, -1) {
+		/* at the edge of the grid toward the azimuth */
+		return 0.0
+	}
+	return degrad * math.Atan(horizon)
+}
+
+// Raster is a grid of values aligned with a DEM
+type Raster struct {
+	Rows   int
+	Cols   int
+	Values []float64 // Values of the cells row by row (row-major)
+}
+
+// At returns the value of a cell
+func (r *Raster) At(row int, col int) float64 {
+	return r.Values[row*r.Cols+col]
+}
+
+// ShadowMap returns the fraction of the solar disk hidden by the terrain for every cell (0 = sunlit, 1 = in shadow)
+// for a calculated sun position (Elevref and Azim of r are used). All cells are in shadow while the sun is down.
+func (d *DEM) ShadowMap(r solpos.Result) *Raster {
+	ras := &Raster{Rows: d.Rows, Cols: d.Cols, Values: make([]float64, d.Rows*d.Cols)}
+	for row := 0; row < d.Rows; row++ {
+		for col := 0; col < d.Cols; col++ {
+			ras.Values[row*d.Cols+col] = hiddenFraction(r.Elevref, math.Max(0.0, d.Horizon(row, col, r.Azim)))
+		}
+	}
+	return ras
+}
+
+// ShadowMask returns for every cell (row-major) whether the center of the sun is hidden by the terrain
+func (d *DEM) ShadowMask(r solpos.Result) []bool {
+	mask := make([]bool, d.Rows*d.Cols)
+	for row := 0; row < d.Rows; row++ {
+		for col := 0; col < d.Cols; col++ {
+			mask[row*d.Cols+col] = r.Elevref <= math.Max(0.0, d.Horizon(row, col, r.Azim))
+		}
+	}
+	return mask
+}
+
+// ShadowMapAt calculates the sun position at the location of the grid at dt and returns its ShadowMap
+func (d *DEM) ShadowMapAt(dt time.Time) (*Raster, error) {
+	sp, err := solpos.NewSolpos(dt, d.Latitude, d.Longitude, nil)
+	if err != nil {
+		return nil, err
+	}
+	return d.ShadowMap(sp.GetResult()), nil
+}
+
+// hiddenFraction returns the fraction of the solar disk centered at elevation below a horizon at the given angle
+func hiddenFraction(elevation float64, horizon float64) float64 {
+	/* distance of the horizon line above the center of the disk, in sun radii */
+	x := (horizon - elevation) / sunRadius
+	switch {
+	case x >= 1.0:
+		return 1.0
+	case x <= -1.0:
+		return 0.0
+	}
+	/* area of the circular segment below the line, relative to the disk */
+	return 1.0 - (math.Acos(x)-x*math.Sqrt(1.0-x*x))/math.Pi
+}
@@ -0,0 +1,124 @@
+package terrain
+
+import (
+	"errors"
+	"github.com/maltegrosse/go-solpos"
+	"math"
+	"time"
+)
+
+// IrradianceConfig configures the clear-sky irradiance over a DEM
+type IrradianceConfig struct {
+	LinkeTurbidity     float64                // Linke turbidity of the clear-sky model (see solpos.ClearSkyIneichen), DEFAULT (0) = 3
+	Altitude           float64                // Altitude of the site above sea level, meters (DEM heights may be relative)
+	Albedo             float64                // Ground reflectance for the reflected part, 0 = no reflected irradiance
+	OptionalParameters map[string]interface{} // Optional parameters of the sun position calculation as for solpos.NewSolpos, e.g. "press"
+}
+
+func (c IrradianceConfig) linkeTurbidity() float64 {
+	if c.LinkeTurbidity == 0.0 {
+		return 3.0
+	}
+	return c.LinkeTurbidity
+}
+
+// SlopeAspect returns slope (degrees from horizontal) and aspect (the direction the cell faces, downhill,
+// N=0, E=90, S=180, W=270) of a cell from its 3x3 neighbourhood (Horn's method), edges repeat the border cells
+func (d *DEM) SlopeAspect(row int, col int) (slope float64, aspect float64) {
+	east, north := d.gradient(row, col)
+	slope = degrad * math.Atan(math.Hypot(east, north))
+	if east == 0.0 && north == 0.0 {
+		/* flat, facing up */
+		return slope, 180.0
+	}
+	aspect = degrad * math.Atan2(-east, -north)
+	if aspect < 0.0 {
+		aspect += 360.0
+	}
+	return slope, aspect
+}
+
+// gradient returns the rise of the terrain per meter toward east and north
+func (d *DEM) gradient(row int, col int) (east float64, north float64) {
+	z := func(r int, c int) float64 {
+		if r < 0 {
+			r = 0
+		} else if r > d.Rows-1 {
+			r = d.Rows - 1
+		}
+		if c < 0 {
+			c = 0
+		} else if c > d.Cols-1 {
+			c = d.Cols - 1
+		}
+		return d.Height(r, c)
+	}
+	east = ((z(row-1, col+1) + 2*z(row, col+1) + z(row+1, col+1)) -
+		(z(row-1, col-1) + 2*z(row, col-1) + z(row+1, col-1))) / (8.0 * d.CellSize)
+	/* rows run north to south */
+	north = ((z(row-1, col-1) + 2*z(row-1, col) + z(row-1, col+1)) -
+		(z(row+1, col-1) + 2*z(row+1, col) + z(row+1, col+1))) / (8.0 * d.CellSize)
+	return east, north
+}
+
+// Irradiance returns the clear-sky irradiance in W/sq m on the terrain surface of every cell for a calculated sun position:
+// the beam part on the slope reduced by the cast shadows (see ShadowMap), the isotropic sky diffuse part seen by the slope
+// and the part reflected by the surrounding ground.
+func (d *DEM) Irradiance(r solpos.Result, config IrradianceConfig) *Raster {
+	cs := solpos.ClearSkyIneichen(r, config.linkeTurbidity(), config.Altitude)
+	shadow := d.ShadowMap(r)
+	sun := solpos.SunVector(r)
+	ras := &Raster{Rows: d.Rows, Cols: d.Cols, Values: make([]float64, d.Rows*d.Cols)}
+	if cs.GHI <= 0.0 {
+		return ras
+	}
+	for row := 0; row < d.Rows; row++ {
+		for col := 0; col < d.Cols; col++ {
+			east, north := d.gradient(row, col)
+			/* surface normal (east, north, up) */
+			norm := math.Sqrt(east*east + north*north + 1.0)
+			cosinc := (-east*sun[0] - north*sun[1] + sun[2]) / norm
+			cosslope := 1.0 / norm
+			i := row*d.Cols + col
+			beam := cs.DNI * math.Max(0.0, cosinc) * (1.0 - shadow.Values[i])
+			diffuse := cs.DHI * (1.0 + cosslope) / 2.0
+			reflected := cs.GHI * config.Albedo * (1.0 - cosslope) / 2.0
+			ras.Values[i] = beam + diffuse + reflected
+		}
+	}
+	return ras
+}
+
+// DailyIrradiation returns the clear-sky irradiation in Wh/sq m of every cell over the local day of date,
+// integrating Irradiance at the given step (e.g. 15 minutes) in the time zone of date
+func (d *DEM) DailyIrradiation(date time.Time, step time.Duration, config IrradianceConfig) (*Raster, error) {
+	if step <= 0 {
+		return nil, errors.New("Please fix step, must be positive")
+	}
+	y, m, day := date.Date()
+	start := time.Date(y, m, day, 0, 0, 0, 0, date.Location())
+	end := time.Date(y, m, day+1, 0, 0, 0, 0, date.Location())
+	sp, err := solpos.NewSolpos(start, d.Latitude, d.Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+	ras := &Raster{Rows: d.Rows, Cols: d.Cols, Values: make([]float64, d.Rows*d.Cols)}
+	hours := step.Hours()
+	/* midpoint rule, every step is represented by its middle */
+	for dt := start.Add(step / 2); dt.Before(end); dt = dt.Add(step) {
+		sp.SetDate(dt)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, err
+		}
+		r := sp.GetResult()
+		if r.Zenref >= 90.0 {
+			continue
+		}
+		irr := d.Irradiance(r, config)
+		for i, v := range irr.Values {
+			ras.Values[i] += v * hours
+		}
+	}
+	return ras, nil
+}
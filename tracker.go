@@ -0,0 +1,226 @@
+package solpos
+
+import "math"
+
+/*============================================================================
+*    Single-axis tracker
+*
+*    Rotation angle of a single-axis tracker following the sun, with optional
+*    backtracking to avoid row-to-row shading.
+*       Lorenzo, E., Narvarte, L., Munoz, J.  2011.  Tracking and back-tracking.
+*            Progress in Photovoltaics 19 (6), pp. 747-753
+*       Marion, W. F., Dobos, A. P.  2013.  Rotation angle for the optimum
+*            tracking of one-axis trackers.  NREL/TP-6A20-58891
+*       Anderson, K., Mikofski, M.  2020.  Slope-aware backtracking for
+*            single-axis trackers.  NREL/TP-5K00-76626
+*
+*    Rotation angles follow the right-hand rule around the axis: for an axis
+*    pointing south, a rotation toward the west is positive and a rotation
+*    toward the east is negative. Zero is the rest position (panel parallel
+*    to the axis, not rolled).
+*----------------------------------------------------------------------------*/
+
+// Tracker describes a single-axis tracker and its controller settings
+type Tracker struct {
+	AxisTilt      float64 // Tilt of the rotation axis from horizontal, degrees (the end at AxisAzimuth is lower)
+	AxisAzimuth   float64 // Direction the rotation axis points to, N=0, E=90, S=180, W=270, 0 is treated as 180 (north-south axis)
+	Limited       bool    // The rotation is limited to MinAngle - MaxAngle, DEFAULT = false (unlimited)
+	MinAngle      float64 // Minimum (most negative) rotation with Limited, degrees, e.g. -60 or 0
+	MaxAngle      float64 // Maximum rotation with Limited, degrees, not below MinAngle
+	AngleOffset   float64 // Offset added to the rotation angle to get the drive angle (drive zero vs. rest position), degrees
+	Backlash      float64 // Mechanical backlash of the drive, degrees, compensated when the direction of motion changes (see TrackFrom)
+	Backtrack     bool    // Rotate back from the ideal angle to avoid row-to-row shading
+	GCR           float64 // Ground coverage ratio: collector width / row pitch, required for backtracking
+	CrossAxisTilt float64 // Tilt of the terrain perpendicular to the axis, degrees, positive when it slopes down toward positive rotation (see SlopeCrossAxisTilt)
+	StowAngle     float64 // Rotation angle while stowed because of wind or an explicit command, degrees
+	SnowStowAngle float64 // Rotation angle while stowed because of snow (usually steep to shed it), degrees
+	StowWindSpeed float64 // Wind speed (m/s) from which the tracker stows, 0 = wind stow disabled
+	StowSnowDepth float64 // Snow depth (cm) from which the tracker stows, 0 = snow stow disabled
+}
+
+// TrackerConditions are the site inputs a tracker controller reacts to
+type TrackerConditions struct {
+	WindSpeed float64 // Wind speed, m/s
+	SnowDepth float64 // Snow depth, cm
+	Stow      bool    // Explicit stow command (e.g. from the plant controller or maintenance)
+}
+
+// StowReason tells why a tracker is stowed
+type StowReason int
+
+const (
+	StowNone      StowReason = iota // tracking, not stowed
+	StowCommanded                   // explicit stow command
+	StowWind                        // wind speed at or above the limit
+	StowSnow                        // snow depth at or above the limit
+)
+
+func (r StowReason) String() string {
+	switch r {
+	case StowCommanded:
+		return "commanded"
+	case StowWind:
+		return "wind"
+	case StowSnow:
+		return "snow"
+	default:
+		return "none"
+	}
+}
+
+// TrackerState is the output of a tracker calculation
+type TrackerState struct {
+	Angle          float64    // Rotation angle to command, degrees (the stow angle while stowed)
+	TrackingAngle  float64    // Rotation angle from tracking (and backtracking), ignoring stow, degrees
+	Stowed         bool       // Angle is a stow angle instead of the tracking angle
+	StowReason     StowReason // Why the tracker is stowed
+	Saturated      bool       // Angle was clamped to MinAngle or MaxAngle
+	Direction      int        // Direction of motion from the previous state, -1, 0 or +1 (only set by TrackFrom)
+	Command        float64    // Drive angle to send to the hardware: Angle plus AngleOffset and backlash compensation, degrees
+	Night          bool       // Sun below the horizon, the tracker rests at 0
+	SurfaceTilt    float64    // Resulting tilt of the panel from horizontal, degrees
+	SurfaceAzimuth float64    // Resulting azimuth of the panel surface, N=0, E=90, S=180, W=270
+	Cosinc         float64    // Cosine of solar incidence angle on the panel
+	Etrtilt        float64    // Extraterrestrial (top-of-atmosphere) W/sq m irradiance on the panel
+}
+
+// Track calculates the tracker state for a calculated sun position (Zenref, Azim and Etrn of r are used)
+func (t Tracker) Track(r Result, c TrackerConditions) TrackerState {
+	var s TrackerState
+	if r.Zenref > 90.0 {
+		s.Night = true
+	} else {
+		s.TrackingAngle, s.Saturated = t.clamp(t.trackingAngle(r.Zenref, r.Azim))
+	}
+	s.Angle = s.TrackingAngle
+
+	/* stow overrides tracking, in the order of urgency */
+	switch {
+	case c.Stow:
+		s.Stowed, s.StowReason, s.Angle = true, StowCommanded, t.StowAngle
+	case t.StowWindSpeed > 0.0 && c.WindSpeed >= t.StowWindSpeed:
+		s.Stowed, s.StowReason, s.Angle = true, StowWind, t.StowAngle
+	case t.StowSnowDepth > 0.0 && c.SnowDepth >= t.StowSnowDepth:
+		s.Stowed, s.StowReason, s.Angle = true, StowSnow, t.SnowStowAngle
+	}
+	if s.Stowed {
+		s.Angle, s.Saturated = t.clamp(s.Angle)
+	}
+	s.Command = s.Angle + t.AngleOffset
+
+	s.SurfaceTilt, s.SurfaceAzimuth = t.surfaceOrientation(s.Angle)
+	s.Cosinc = incidence(s.SurfaceTilt, s.SurfaceAzimuth, r.Zenref, r.Azim)
+	if s.Cosinc > 0.0 && !s.Night {
+		s.Etrtilt = r.Etrn * s.Cosinc
+	}
+	return s
+}
+
+// TrackFrom calculates the tracker state like Track, continuing from the previous state: the direction of motion is
+// determined and the command compensates the backlash, so the panel reaches Angle whichever way the drive turns.
+func (t Tracker) TrackFrom(previous TrackerState, r Result, c TrackerConditions) TrackerState {
+	s := t.Track(r, c)
+	switch {
+	case s.Angle > previous.Angle:
+		s.Direction = 1
+	case s.Angle < previous.Angle:
+		s.Direction = -1
+	default:
+		/* standing still, the slack stays on the side of the last motion */
+		s.Direction = previous.Direction
+	}
+	s.Command += float64(s.Direction) * t.Backlash / 2.0
+	return s
+}
+
+// clamp limits a rotation angle to the configured range and reports whether it was changed
+func (t Tracker) clamp(angle float64) (float64, bool) {
+	if !t.Limited {
+		return angle, false
+	}
+	if angle > t.MaxAngle {
+		return t.MaxAngle, true
+	}
+	if angle < t.MinAngle {
+		return t.MinAngle, true
+	}
+	return angle, false
+}
+
+func (t Tracker) axisAzimuth() float64 {
+	if t.AxisAzimuth == 0.0 {
+		return 180.0
+	}
+	return t.AxisAzimuth
+}
+
+// trackingAngle returns the (backtracked) rotation angle for a sun position
+func (t Tracker) trackingAngle(zenith float64, azimuth float64) float64 {
+	axisAzimuth := t.axisAzimuth()
+
+	/* sun vector: x east, y north, z up */
+	x := math.Sin(raddeg*zenith) * math.Sin(raddeg*azimuth)
+	y := math.Sin(raddeg*zenith) * math.Cos(raddeg*azimuth)
+	z := math.Cos(raddeg * zenith)
+
+	/* sun vector in the tracker frame, y' along the axis (Marion & Dobos, eq. 4) */
+	xp := x*math.Cos(raddeg*axisAzimuth) - y*math.Sin(raddeg*axisAzimuth)
+	zp := x*math.Sin(raddeg*t.AxisTilt)*math.Sin(raddeg*axisAzimuth) +
+		y*math.Sin(raddeg*t.AxisTilt)*math.Cos(raddeg*axisAzimuth) +
+		z*math.Cos(raddeg*t.AxisTilt)
+
+	/* ideal angle puts the sun into the plane normal to the panel */
+	angle := degrad * math.Atan2(xp, zp)
+
+	if t.Backtrack && t.GCR > 0.0 {
+		/* Anderson & Mikofski, eq. 14: rotate back until the row shadow ends at the next row,
+		   which is higher or lower than this one on sloped terrain (Lorenzo et al. on flat terrain) */
+		axesDistance := 1.0 / (t.GCR * math.Cos(raddeg*t.CrossAxisTilt))
+		temp := math.Abs(axesDistance * math.Cos(raddeg*(angle-t.CrossAxisTilt)))
+		if temp < 1.0 {
+			angle -= math.Copysign(degrad*math.Acos(temp), angle)
+		}
+	}
+	return angle
+}
+
+// SlopeAxisTilt returns the tilt of a tracker axis laid out on terrain with the given slope tilt and slope azimuth (the direction the
+// slope faces, downhill), degrees, positive when the end at axisAzimuth is lower (Anderson & Mikofski, eq. 25)
+func SlopeAxisTilt(slopeTilt float64, slopeAzimuth float64, axisAzimuth float64) float64 {
+	return degrad * math.Atan(math.Tan(raddeg*slopeTilt)*math.Cos(raddeg*(axisAzimuth-slopeAzimuth)))
+}
+
+// SlopeCrossAxisTilt returns the cross-axis tilt of a tracker axis laid out on terrain with the given slope tilt and slope azimuth,
+// degrees. For an axis pointing south it is negative if the terrain slopes down to the east and positive if it slopes down to the west.
+func SlopeCrossAxisTilt(slopeTilt float64, slopeAzimuth float64, axisTilt float64, axisAzimuth float64) float64 {
+	/* slope normal projected into the plane perpendicular to the axis */
+	top := math.Sin(raddeg*slopeTilt) * math.Sin(raddeg*(slopeAzimuth-axisAzimuth))
+	bottom := math.Sin(raddeg*slopeTilt)*math.Sin(raddeg*axisTilt)*math.Cos(raddeg*(slopeAzimuth-axisAzimuth)) +
+		math.Cos(raddeg*slopeTilt)*math.Cos(raddeg*axisTilt)
+	return degrad * math.Atan2(top, bottom)
+}
+
+// surfaceOrientation returns the panel tilt and azimuth for a rotation angle (Marion & Dobos, eq. 1-4)
+func (t Tracker) surfaceOrientation(angle float64) (tilt float64, azimuth float64) {
+	tilt = degrad * math.Acos(math.Cos(raddeg*angle)*math.Cos(raddeg*t.AxisTilt))
+	var delta float64
+	if math.Sin(raddeg*tilt) == 0.0 {
+		delta = 90.0
+	} else {
+		delta = degrad * math.Asin(math.Max(-1.0, math.Min(1.0, math.Sin(raddeg*angle)/math.Sin(raddeg*tilt))))
+		if math.Abs(angle) >= 90.0 {
+			delta = -delta + math.Copysign(180.0, angle)
+		}
+	}
+	azimuth = math.Mod(t.axisAzimuth()+delta, 360.0)
+	if azimuth < 0.0 {
+		azimuth += 360.0
+	}
+	return tilt, azimuth
+}
+
+// incidence returns the cosine of the solar incidence angle on a surface, like the tilt calculation of Calculate
+func incidence(tilt float64, aspect float64, zenith float64, azimuth float64) float64 {
+	return math.Cos(raddeg*zenith)*math.Cos(raddeg*tilt) +
+		math.Sin(raddeg*zenith)*math.Sin(raddeg*tilt)*math.Cos(raddeg*(azimuth-aspect))
+}
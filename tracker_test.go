@@ -0,0 +1,27 @@
+package solpos
+
+import "testing"
+
+func TestTrackerLimits(t *testing.T) {
+	morning := Result{Zenref: 60.0, Azim: 90.0}
+	afternoon := Result{Zenref: 60.0, Azim: 270.0}
+	tests := []struct {
+		name      string
+		tracker   Tracker
+		r         Result
+		angle     float64
+		saturated bool
+	}{
+		{"unlimited", Tracker{}, morning, -60.0, false},
+		{"symmetric", Tracker{Limited: true, MinAngle: -45.0, MaxAngle: 45.0}, morning, -45.0, true},
+		{"west only", Tracker{Limited: true, MinAngle: 0.0, MaxAngle: 60.0}, morning, 0.0, true},
+		{"west only afternoon", Tracker{Limited: true, MinAngle: 0.0, MaxAngle: 60.0}, afternoon, 60.0, false},
+		{"east only", Tracker{Limited: true, MinAngle: -50.0, MaxAngle: -10.0}, afternoon, -10.0, true},
+	}
+	for _, tt := range tests {
+		s := tt.tracker.Track(tt.r, TrackerConditions{})
+		if diff := s.Angle - tt.angle; diff > 1e-6 || diff < -1e-6 || s.Saturated != tt.saturated {
+			t.Errorf("%s: angle %v, saturated %v, want %v, %v", tt.name, s.Angle, s.Saturated, tt.angle, tt.saturated)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package solpos
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TrackerLoopConfig configures RunTracker
+type TrackerLoopConfig struct {
+	Tracker    Tracker                             // Tracker model, including backtracking, limits and stow settings
+	Interval   time.Duration                       // Time between two commands
+	Conditions func(t time.Time) TrackerConditions // Optional, returns the current wind, snow and stow inputs
+}
+
+// TrackerCommand is emitted by RunTracker for every interval
+type TrackerCommand struct {
+	Time   time.Time    // Time of the calculation, local time of the site
+	Result Result       // Sun position the command is based on
+	State  TrackerState // Tracker state, State.Command is the drive angle to send
+}
+
+// RunTracker recomputes the tracker target of the site every interval and sends the commands to sink until ctx is done,
+// run it in its own goroutine. Every command is calculated from the current wall clock in the site's location, never
+// from accumulated ticks, so clock corrections and DST changes are picked up with the next command. The sink is
+// closed when RunTracker returns, the returned error is the reason (ctx.Err() for a regular stop).
+func RunTracker(ctx context.Context, site Site, config TrackerLoopConfig, sink chan<- TrackerCommand) error {
+	defer close(sink)
+	if config.Interval <= 0 {
+		return errors.New("Please fix interval, must be positive")
+	}
+	sp, err := NewSolpos(time.Now().In(site.location()), site.Latitude, site.Longitude, nil)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	var previous TrackerState
+	for {
+		now := time.Now().In(site.location())
+		sp.SetDate(now)
+		err = sp.Calculate()
+		if err != nil {
+			return err
+		}
+		var conditions TrackerConditions
+		if config.Conditions != nil {
+			conditions = config.Conditions(now)
+		}
+		r := sp.GetResult()
+		previous = config.Tracker.TrackFrom(previous, r, conditions)
+		select {
+		case sink <- TrackerCommand{Time: now, Result: r, State: previous}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
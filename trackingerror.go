@@ -0,0 +1,70 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// TrackerSample is a logged (actual) rotation angle of a tracker
+type TrackerSample struct {
+	Time  time.Time // Time of the measurement
+	Angle float64   // Measured rotation angle, degrees, same convention as TrackerState.Angle
+}
+
+// TrackingErrorReport summarizes the pointing error of a tracker against the ideal angles
+type TrackingErrorReport struct {
+	Samples      int       // Number of daytime samples evaluated (samples at night are skipped)
+	MeanError    float64   // Mean signed difference actual - ideal, degrees (bias)
+	RMSError     float64   // Root mean square difference actual - ideal, degrees
+	MaxError     float64   // Largest absolute difference, degrees
+	MaxErrorTime time.Time // Time of the largest difference
+	EnergyLoss   float64   // Fraction of the extraterrestrial irradiation on the panel lost by the pointing errors, 0 - 1
+}
+
+// EvaluateTracking compares logged tracker angles with the ideal angles of the tracker model at the site
+// (including its backtracking and limits, without stow) for commissioning and O&M analysis.
+func EvaluateTracking(site Site, tracker Tracker, samples []TrackerSample) (TrackingErrorReport, error) {
+	var report TrackingErrorReport
+	if len(samples) == 0 {
+		return report, errors.New("Please fix samples, at least one is required")
+	}
+	sp, err := NewSolpos(samples[0].Time.In(site.location()), site.Latitude, site.Longitude, nil)
+	if err != nil {
+		return report, err
+	}
+	var sum, sumSquares, ideal, actual float64
+	for _, sample := range samples {
+		sp.SetDate(sample.Time.In(site.location()))
+		err = sp.Calculate()
+		if err != nil {
+			return report, err
+		}
+		r := sp.GetResult()
+		state := tracker.Track(r, TrackerConditions{})
+		if state.Night {
+			continue
+		}
+		diff := sample.Angle - state.Angle
+		report.Samples++
+		sum += diff
+		sumSquares += diff * diff
+		if math.Abs(diff) > report.MaxError {
+			report.MaxError, report.MaxErrorTime = math.Abs(diff), sample.Time
+		}
+
+		/* energy weighting by the extraterrestrial irradiance on the panel */
+		tilt, aspect := tracker.surfaceOrientation(sample.Angle)
+		ideal += state.Etrtilt
+		actual += r.Etrn * math.Max(0.0, incidence(tilt, aspect, r.Zenref, r.Azim))
+	}
+	if report.Samples == 0 {
+		return report, errors.New("Please fix samples, none during daytime")
+	}
+	report.MeanError = sum / float64(report.Samples)
+	report.RMSError = math.Sqrt(sumSquares / float64(report.Samples))
+	if ideal > 0.0 {
+		report.EnergyLoss = 1.0 - actual/ideal
+	}
+	return report, nil
+}
@@ -0,0 +1,101 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNoTwilight is returned (wrapped) if the sun does not cross the depression angle of a twilight on the requested day,
+// e.g. in the white nights of summer or the polar night
+var ErrNoTwilight = errors.New("the sun does not cross the twilight depression angle")
+
+// Twilight selects the depression angle of the sun below the horizon at dawn and dusk
+type Twilight int
+
+const (
+	TwilightCivil        Twilight = iota // sun 6 degrees below the horizon
+	TwilightNautical                     // sun 12 degrees below the horizon
+	TwilightAstronomical                 // sun 18 degrees below the horizon
+)
+
+// Depression returns the depression angle of the twilight, degrees below the horizon
+func (t Twilight) Depression() float64 {
+	switch t {
+	case TwilightNautical:
+		return 12.0
+	case TwilightAstronomical:
+		return 18.0
+	default:
+		return 6.0
+	}
+}
+
+func (t Twilight) String() string {
+	switch t {
+	case TwilightCivil:
+		return "civil"
+	case TwilightNautical:
+		return "nautical"
+	case TwilightAstronomical:
+		return "astronomical"
+	default:
+		return "unknown"
+	}
+}
+
+func (sp *PosData) GetDawn(twilight Twilight) (time.Time, error) {
+	ha, err := sp.twilightHourAngle(twilight)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sp.eventTime(720.0 - 4.0*ha - sp.Tstfix), nil
+}
+
+func (sp *PosData) GetDusk(twilight Twilight) (time.Time, error) {
+	ha, err := sp.twilightHourAngle(twilight)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sp.eventTime(720.0 + 4.0*ha - sp.Tstfix), nil
+}
+
+// twilightHourAngle returns the hour angle of dawn and dusk of a twilight on the day of the last calculation
+func (sp *PosData) twilightHourAngle(twilight Twilight) (float64, error) {
+	err := sp.Computed(LGeom | LTst)
+	if err != nil {
+		return 0, err
+	}
+	ha := sp.crossingHourAngle(-twilight.Depression())
+	switch {
+	case ha <= 0.0:
+		return 0, wrap(ErrNoTwilight, twilight.String()+", the sun stays below")
+	case ha >= 180.0:
+		return 0, wrap(ErrNoTwilight, twilight.String()+", the sun stays above")
+	}
+	return ha, nil
+}
+
+// crossingHourAngle returns the hour angle (degrees, 0 to 180) at which the sun passes an unrefracted elevation with the
+// declination of the last calculation, like the sunset hour angle Ssha for the horizon. It is 0 if the sun stays below the
+// elevation all day and 180 if it stays above.
+func (sp *PosData) crossingHourAngle(elevation float64) float64 {
+	sl, cl := math.Sincos(raddeg * sp.Latitude)
+	sd, cd := math.Sincos(raddeg * sp.Declin)
+	cdcl := cd * cl
+	if math.Abs(cdcl) < 0.001 {
+		/* at the pole the elevation is the declination all day */
+		if sd*math.Copysign(1.0, sp.Latitude) >= math.Sin(raddeg*elevation) {
+			return 180.0
+		}
+		return 0.0
+	}
+	cosha := (math.Sin(raddeg*elevation) - sl*sd) / cdcl
+	switch {
+	case cosha <= -1.0:
+		return 180.0
+	case cosha >= 1.0:
+		return 0.0
+	}
+	return degrad * math.Acos(cosha)
+}
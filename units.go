@@ -0,0 +1,85 @@
+package solpos
+
+// Degrees is an angle in degrees, e.g. for the optional parameters "tilt" and "aspect"
+type Degrees float64
+
+// Millibars is a pressure in millibars (hectopascals), e.g. for the optional parameter "press"
+type Millibars float64
+
+// Celsius is a temperature in degrees Celsius, e.g. for the optional parameter "temp"
+type Celsius float64
+
+// Centimeters is a length in centimeters, e.g. for the optional parameters "sbwid" and "sbrad"
+type Centimeters float64
+
+// Radians converts an angle in radians to Degrees
+func Radians(rad float64) Degrees {
+	return Degrees(rad * degrad)
+}
+
+// Pascals converts a pressure in pascals to Millibars
+func Pascals(pa float64) Millibars {
+	return Millibars(pa / 100.0)
+}
+
+// InchesOfMercury converts a pressure in inches of mercury (e.g. an aviation altimeter setting) to Millibars
+func InchesOfMercury(inHg float64) Millibars {
+	return Millibars(inHg * 33.8639)
+}
+
+// Fahrenheit converts a temperature in degrees Fahrenheit to Celsius
+func Fahrenheit(f float64) Celsius {
+	return Celsius((f - 32.0) * 5.0 / 9.0)
+}
+
+// Kelvin converts a temperature in kelvin to Celsius
+func Kelvin(k float64) Celsius {
+	return Celsius(k - 273.15)
+}
+
+// Inches converts a length in inches to Centimeters
+func Inches(in float64) Centimeters {
+	return Centimeters(in * 2.54)
+}
+
+/* the optional parameters accept plain float64 values or the matching unit type, a value of another unit type is rejected */
+
+func degrees(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case Degrees:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func millibars(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case Millibars:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func celsius(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case Celsius:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func centimeters(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case Centimeters:
+		return float64(v), true
+	}
+	return 0, false
+}
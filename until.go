@@ -0,0 +1,129 @@
+package solpos
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPolarDay is returned (wrapped) if a sunrise or sunset was requested for a day with 24 hours of sun up
+var ErrPolarDay = errors.New("polar day, the sun does not set")
+
+// ErrPolarNight is returned (wrapped) if a sunrise or sunset was requested for a day with 24 hours of sun down
+var ErrPolarNight = errors.New("polar night, the sun does not rise")
+
+// ErrNoEvent is returned by NextSunrise and NextSunset if there is no such event within a year
+var ErrNoEvent = errors.New("no sunrise or sunset within a year")
+
+func (sp *PosData) Sunrise() (time.Time, error) {
+	err := sp.riseSetComputed()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sp.eventTime(sp.Sretr), nil
+}
+
+func (sp *PosData) Sunset() (time.Time, error) {
+	err := sp.riseSetComputed()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sp.eventTime(sp.Ssetr), nil
+}
+
+// riseSetComputed returns an error if sunrise and sunset of the last calculation are not available
+func (sp *PosData) riseSetComputed() error {
+	err := sp.Computed(LSrss)
+	if err != nil {
+		return err
+	}
+	return polarError(sp.Sretr, sp.Getdate())
+}
+
+func (sp *PosData) MinutesUntilSunset(t time.Time) (time.Duration, error) {
+	err := sp.calculateEvents(t, true)
+	if err != nil {
+		return 0, err
+	}
+	return sp.eventTime(sp.Ssetr).Sub(t), nil
+}
+
+func (sp *PosData) MinutesSinceSunrise(t time.Time) (time.Duration, error) {
+	err := sp.calculateEvents(t, true)
+	if err != nil {
+		return 0, err
+	}
+	return t.Sub(sp.eventTime(sp.Sretr)), nil
+}
+
+func (sp *PosData) TimeToSolarNoon(t time.Time) (time.Duration, error) {
+	err := sp.calculateEvents(t, false)
+	if err != nil {
+		return 0, err
+	}
+	noon, err := sp.GetSolarNoon()
+	if err != nil {
+		return 0, err
+	}
+	return noon.Sub(t), nil
+}
+
+func (sp *PosData) NextSunrise(after time.Time) (time.Time, error) {
+	return sp.nextEvent(after, func(c *PosData) float64 { return c.Sretr })
+}
+
+func (sp *PosData) NextSunset(after time.Time) (time.Time, error) {
+	return sp.nextEvent(after, func(c *PosData) float64 { return c.Ssetr })
+}
+
+// nextEventDays limits the forward search of nextEvent, a polar night or day never lasts longer than a year
+const nextEventDays = 367
+
+// nextEvent calculates a copy day by day from the day before after (sunset may fall past midnight) and returns the first
+// event later than after, days without sunrise and sunset are skipped. The calculation of sp is left as it is.
+func (sp *PosData) nextEvent(after time.Time, event func(c *PosData) float64) (time.Time, error) {
+	c := *sp
+	for d := -1; d <= nextEventDays; d++ {
+		err := c.calculateEvents(after.AddDate(0, 0, d), true)
+		if errors.Is(err, ErrPolarDay) || errors.Is(err, ErrPolarNight) {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		t := c.eventTime(event(&c))
+		if t.After(after) {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrNoEvent
+}
+
+// calculateEvents calculates the day of t, with riseSet an error is returned if the sun does not rise or set on that day
+func (sp *PosData) calculateEvents(t time.Time, riseSet bool) error {
+	sp.SetDate(t)
+	err := sp.Calculate()
+	if err != nil {
+		return err
+	}
+	if !riseSet {
+		return sp.Computed(LTst)
+	}
+	return sp.riseSetComputed()
+}
+
+// polarError returns an error wrapping ErrPolarDay or ErrPolarNight if sretr is one of the flag values of srss, nil otherwise
+func polarError(sretr float64, dt time.Time) error {
+	switch sretr {
+	case -2999.0:
+		return wrap(ErrPolarDay, dt.Format("2006-01-02"))
+	case 2999.0:
+		return wrap(ErrPolarNight, dt.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// eventTime converts minutes from midnight, local standard time of the calculated day, to a time
+func (sp *PosData) eventTime(minutes float64) time.Time {
+	dt := sp.Getdate()
+	return standardMidnight(dt).Add(time.Duration(minutes * float64(time.Minute))).In(dt.Location())
+}
@@ -0,0 +1,26 @@
+package solpos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextSunsetKeepsCalculation(t *testing.T) {
+	dt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	sp, err := New(dt, 78.2, 15.6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sp.GetResult()
+	/* polar day until August */
+	set, err := sp.NextSunset(dt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Month() != time.August {
+		t.Errorf("next sunset %v, want in August", set)
+	}
+	if got := sp.GetResult(); !got.Time.Equal(want.Time) || got.Zenetr != want.Zenetr || got.Sretr != want.Sretr {
+		t.Errorf("calculation changed to %v, zenetr %v, want %v, zenetr %v", got.Time, got.Zenetr, want.Time, want.Zenetr)
+	}
+}
@@ -0,0 +1,186 @@
+// Package validation provides reusable property checks for solpos calculations. The properties hold for every valid
+// configuration, so downstream users can run them against their own locations, dates and optional parameters.
+package validation
+
+import (
+	"fmt"
+	"github.com/maltegrosse/go-solpos"
+	"math"
+	"time"
+)
+
+// Config is the configuration the properties are checked for
+type Config struct {
+	Date               time.Time              // Day to check, in the local time zone of the location
+	Latitude           float64                // Latitude, degrees north (south negative)
+	Longitude          float64                // Longitude, degrees east (west negative)
+	OptionalParameters map[string]interface{} // Optional parameters as for solpos.NewSolpos
+	Step               time.Duration          // Sampling step over the day, DEFAULT (0) = 10 minutes
+	Tolerance          float64                // Tolerance of angle comparisons, degrees, DEFAULT (0) = 0.25
+}
+
+// Violation describes a property that does not hold
+type Violation struct {
+	Property string    // Name of the property
+	Time     time.Time // Time of the offending calculation
+	Message  string    // What went wrong
+}
+
+func (v Violation) Error() string {
+	return v.Property + " violated at " + v.Time.Format(time.RFC3339) + ": " + v.Message
+}
+
+// Check checks a single property and returns its violations
+type Check func(c Config) []Violation
+
+// Checks are all properties of this package
+var Checks = []Check{ZenithElevation, SolarNoonSymmetry, MorningAzimuth, EventOrder}
+
+// Run runs the given checks (all Checks if none are given) and returns all violations
+func Run(c Config, checks ...Check) []Violation {
+	if len(checks) == 0 {
+		checks = Checks
+	}
+	var violations []Violation
+	for _, check := range checks {
+		violations = append(violations, check(c)...)
+	}
+	return violations
+}
+
+// ZenithElevation checks that zenith and elevation angles add up to 90 degrees, refracted and unrefracted
+func ZenithElevation(c Config) []Violation {
+	var violations []Violation
+	err := c.day(func(r solpos.Result) {
+		if math.Abs(r.Zenref+r.Elevref-90.0) > 1e-9 {
+			violations = append(violations, Violation{"ZenithElevation", r.Time, fmt.Sprintf("zenref %v + elevref %v != 90", r.Zenref, r.Elevref)})
+		}
+		if math.Abs(r.Zenetr+r.Elevetr-90.0) > 1e-9 {
+			violations = append(violations, Violation{"ZenithElevation", r.Time, fmt.Sprintf("zenetr %v + elevetr %v != 90", r.Zenetr, r.Elevetr)})
+		}
+	})
+	return append(violations, err...)
+}
+
+// SolarNoonSymmetry checks that the elevation is symmetric about solar noon (up to the change of declination over the day)
+func SolarNoonSymmetry(c Config) []Violation {
+	sp, err := solpos.NewSolpos(c.noon(), c.Latitude, c.Longitude, c.OptionalParameters)
+	if err != nil {
+		return []Violation{{"SolarNoonSymmetry", c.Date, err.Error()}}
+	}
+	/* solar noon in minutes from midnight */
+	noon := c.midnight().Add(time.Duration((720.0 - sp.GetTstfix()) * float64(time.Minute)))
+	var violations []Violation
+	for offset := c.step(); offset <= 4*time.Hour; offset += c.step() {
+		before, err := elevation(sp, noon.Add(-offset))
+		if err != nil {
+			return append(violations, Violation{"SolarNoonSymmetry", noon.Add(-offset), err.Error()})
+		}
+		after, err := elevation(sp, noon.Add(offset))
+		if err != nil {
+			return append(violations, Violation{"SolarNoonSymmetry", noon.Add(offset), err.Error()})
+		}
+		if math.Abs(before-after) > c.tolerance() {
+			violations = append(violations, Violation{"SolarNoonSymmetry", noon.Add(offset),
+				fmt.Sprintf("elevation %v at noon+%v differs from %v at noon-%v", after, offset, before, offset)})
+		}
+	}
+	return violations
+}
+
+// MorningAzimuth checks that the azimuth changes monotonically from sunrise to solar noon. Days on which the latitude is
+// between the equator and the declination are skipped, the azimuth turns back on them (where cos(hrang) = tan(latitude) / tan(declin)).
+func MorningAzimuth(c Config) []Violation {
+	sp, err := solpos.NewSolpos(c.noon(), c.Latitude, c.Longitude, c.OptionalParameters)
+	if err != nil {
+		return []Violation{{"MorningAzimuth", c.Date, err.Error()}}
+	}
+	if c.Latitude*sp.GetDeclin() >= 0.0 && math.Abs(c.Latitude) <= math.Abs(sp.GetDeclin()) {
+		return nil
+	}
+	var violations []Violation
+	var previous *solpos.Result
+	var direction float64
+	errs := c.day(func(r solpos.Result) {
+		morning := r.Elevetr > 0.0 && r.Hrang < 0.0
+		if !morning {
+			previous = nil
+			return
+		}
+		if previous != nil {
+			/* signed change, wrapped to [-180, 180] */
+			delta := math.Mod(r.Azim-previous.Azim+540.0, 360.0) - 180.0
+			if direction == 0.0 {
+				direction = math.Copysign(1.0, delta)
+			} else if delta*direction < 0.0 {
+				violations = append(violations, Violation{"MorningAzimuth", r.Time,
+					fmt.Sprintf("azimuth turned from %v to %v", previous.Azim, r.Azim)})
+			}
+		}
+		tmp := r
+		previous = &tmp
+	})
+	return append(violations, errs...)
+}
+
+// EventOrder checks that sunrise is before solar noon and solar noon is before sunset (skipped during polar day and night)
+func EventOrder(c Config) []Violation {
+	sp, err := solpos.NewSolpos(c.noon(), c.Latitude, c.Longitude, c.OptionalParameters)
+	if err != nil {
+		return []Violation{{"EventOrder", c.Date, err.Error()}}
+	}
+	sunrise, sunset, noon := sp.GetSretr(), sp.GetSsetr(), 720.0-sp.GetTstfix()
+	if math.Abs(sunrise) >= 2999.0 || math.Abs(sunset) >= 2999.0 {
+		return nil
+	}
+	if !(sunrise < noon && noon < sunset) {
+		return []Violation{{"EventOrder", c.noon(), fmt.Sprintf("sunrise %v, solar noon %v and sunset %v (minutes) out of order", sunrise, noon, sunset)}}
+	}
+	return nil
+}
+
+// day calls f for every step of the day, violations are calculation errors
+func (c Config) day(f func(r solpos.Result)) []Violation {
+	start := c.midnight()
+	sp, err := solpos.NewSolpos(start, c.Latitude, c.Longitude, c.OptionalParameters)
+	if err != nil {
+		return []Violation{{"Calculate", start, err.Error()}}
+	}
+	for dt := start; dt.Before(start.AddDate(0, 0, 1)); dt = dt.Add(c.step()) {
+		sp.SetDate(dt)
+		err = sp.Calculate()
+		if err != nil {
+			return []Violation{{"Calculate", dt, err.Error()}}
+		}
+		f(sp.GetResult())
+	}
+	return nil
+}
+
+func elevation(sp solpos.Solpos, dt time.Time) (float64, error) {
+	sp.SetDate(dt)
+	err := sp.Calculate()
+	return sp.GetElevetr(), err
+}
+
+func (c Config) midnight() time.Time {
+	return time.Date(c.Date.Year(), c.Date.Month(), c.Date.Day(), 0, 0, 0, 0, c.Date.Location())
+}
+
+func (c Config) noon() time.Time {
+	return c.midnight().Add(12 * time.Hour)
+}
+
+func (c Config) step() time.Duration {
+	if c.Step <= 0 {
+		return 10 * time.Minute
+	}
+	return c.Step
+}
+
+func (c Config) tolerance() float64 {
+	if c.Tolerance <= 0.0 {
+		return 0.25
+	}
+	return c.Tolerance
+}
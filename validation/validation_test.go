@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	configs := []struct {
+		name   string
+		config Config
+	}{
+		{"mid-latitude", Config{Date: time.Date(2021, 3, 20, 0, 0, 0, 0, berlin), Latitude: 52.52, Longitude: 13.40}},
+		{"mid-latitude south", Config{Date: time.Date(2021, 12, 21, 0, 0, 0, 0, time.FixedZone("AEDT", 11*3600)), Latitude: -33.87, Longitude: 151.21}},
+		{"tropics", Config{Date: time.Date(2021, 8, 1, 0, 0, 0, 0, time.FixedZone("SGT", 8*3600)), Latitude: 1.35, Longitude: 103.82}},
+		{"polar day", Config{Date: time.Date(2021, 6, 21, 0, 0, 0, 0, berlin), Latitude: 78.22, Longitude: 15.65}},
+		{"polar night", Config{Date: time.Date(2021, 12, 21, 0, 0, 0, 0, berlin), Latitude: 78.22, Longitude: 15.65}},
+	}
+	for _, c := range configs {
+		for _, v := range Run(c.config) {
+			t.Errorf("%s: %v", c.name, v)
+		}
+	}
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	c := Config{Date: time.Date(2021, 3, 20, 0, 0, 0, 0, time.UTC), Latitude: 91.0, Longitude: 0.0}
+	if len(Run(c)) == 0 {
+		t.Error("no violation for latitude 91")
+	}
+}
@@ -0,0 +1,82 @@
+// Package vegalite exports go-solpos results as ready-to-render Vega-Lite specifications with the data inlined, to
+// display them in one line in Jupyter, Observable or any other Vega-Lite renderer.
+package vegalite
+
+import (
+	"encoding/json"
+	"github.com/maltegrosse/go-solpos"
+	"time"
+)
+
+// schema is the Vega-Lite version of the specifications
+const schema = "https://vega.github.io/schema/vega-lite/v5.json"
+
+// spec is a specification with a single view
+type spec map[string]interface{}
+
+func newSpec(title string, mark interface{}, values []map[string]interface{}, encoding map[string]interface{}) spec {
+	return spec{
+		"$schema":  schema,
+		"title":    title,
+		"width":    600,
+		"height":   300,
+		"data":     map[string]interface{}{"values": values},
+		"mark":     mark,
+		"encoding": encoding,
+	}
+}
+
+func field(name string, typ string, title string) map[string]interface{} {
+	return map[string]interface{}{"field": name, "type": typ, "title": title}
+}
+
+// Elevation returns a line chart of the refracted solar elevation over time of a series, negative values (sun below
+// the horizon) are included
+func Elevation(results []solpos.Result) ([]byte, error) {
+	values := make([]map[string]interface{}, len(results))
+	for i, r := range results {
+		values[i] = map[string]interface{}{"time": r.Time.Format(time.RFC3339), "elevation": r.Elevref}
+	}
+	return json.Marshal(newSpec("Solar elevation", "line", values, map[string]interface{}{
+		"x": field("time", "temporal", "Time"),
+		"y": field("elevation", "quantitative", "Elevation (°)"),
+	}))
+}
+
+// SunPath returns a scatter plot of the refracted solar elevation over the azimuth of a series, the points above the horizon
+func SunPath(results []solpos.Result) ([]byte, error) {
+	values := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		if r.Elevref < 0.0 {
+			continue
+		}
+		values = append(values, map[string]interface{}{"time": r.Time.Format(time.RFC3339), "azimuth": r.Azim, "elevation": r.Elevref})
+	}
+	x := field("azimuth", "quantitative", "Azimuth (°)")
+	x["scale"] = map[string]interface{}{"domain": []float64{0, 360}}
+	return json.Marshal(newSpec("Sun path", map[string]interface{}{"type": "point", "filled": true, "size": 10}, values, map[string]interface{}{
+		"x":       x,
+		"y":       field("elevation", "quantitative", "Elevation (°)"),
+		"tooltip": []interface{}{field("time", "temporal", "Time")},
+	}))
+}
+
+// MonthlyInsolation returns a grouped bar chart of the clear-sky insolation of monthly summaries (see
+// solpos.MonthlySummaries) on a horizontal and on the tilted surface
+func MonthlyInsolation(summaries []solpos.MonthlySummary) ([]byte, error) {
+	values := make([]map[string]interface{}, 0, 2*len(summaries))
+	for _, s := range summaries {
+		month := s.Month.String()[:3]
+		values = append(values,
+			map[string]interface{}{"month": month, "surface": "horizontal", "insolation": s.InsolationHorizontal},
+			map[string]interface{}{"month": month, "surface": "tilted", "insolation": s.InsolationTilt})
+	}
+	x := field("month", "ordinal", "Month")
+	x["sort"] = nil
+	return json.Marshal(newSpec("Monthly clear-sky insolation", "bar", values, map[string]interface{}{
+		"x":       x,
+		"xOffset": map[string]interface{}{"field": "surface"},
+		"y":       field("insolation", "quantitative", "Insolation (kWh/m²)"),
+		"color":   field("surface", "nominal", "Surface"),
+	}))
+}
@@ -0,0 +1,196 @@
+package solpos
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+/*============================================================================
+*    Moving vehicles
+*
+*    The vehicle frame follows ISO 8855: x points forward, y to the left and
+*    z up. The attitude rotates the local east, north, up frame into it by
+*    heading (yaw, clockwise from north), pitch (nose up positive) and roll
+*    (right side down positive), in this order.
+*----------------------------------------------------------------------------*/
+
+// Attitude is the orientation of a vehicle, vessel or aircraft
+type Attitude struct {
+	Heading float64 // Direction the vehicle points to, N=0, E=90, S=180, W=270
+	Pitch   float64 // Rotation about the lateral axis, degrees, nose up positive
+	Roll    float64 // Rotation about the longitudinal axis, degrees, right side down positive
+}
+
+// ToENU rotates a vector from the vehicle frame (forward, left, up) into the local east, north, up frame
+func (a Attitude) ToENU(v [3]float64) [3]float64 {
+	m := a.matrix()
+	/* the transpose rotates back */
+	return [3]float64{
+		m[0][0]*v[0] + m[1][0]*v[1] + m[2][0]*v[2],
+		m[0][1]*v[0] + m[1][1]*v[1] + m[2][1]*v[2],
+		m[0][2]*v[0] + m[1][2]*v[1] + m[2][2]*v[2],
+	}
+}
+
+// ToVehicle rotates a vector from the local east, north, up frame into the vehicle frame (forward, left, up)
+func (a Attitude) ToVehicle(v [3]float64) [3]float64 {
+	m := a.matrix()
+	return [3]float64{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2],
+	}
+}
+
+// matrix returns the rows of the rotation from east, north, up to forward, left, up
+func (a Attitude) matrix() [3][3]float64 {
+	sh, ch := math.Sin(raddeg*a.Heading), math.Cos(raddeg*a.Heading)
+	sp, cp := math.Sin(raddeg*a.Pitch), math.Cos(raddeg*a.Pitch)
+	sr, cr := math.Sin(raddeg*a.Roll), math.Cos(raddeg*a.Roll)
+	/* axes of the vehicle in east, north, up after heading and pitch */
+	forward := [3]float64{sh * cp, ch * cp, sp}
+	left := [3]float64{-ch, sh, 0.0}
+	up := [3]float64{-sh * sp, -ch * sp, cp}
+	/* roll about the forward axis, right side down lifts the left side */
+	return [3][3]float64{
+		forward,
+		{cr*left[0] + sr*up[0], cr*left[1] + sr*up[1], cr*left[2] + sr*up[2]},
+		{cr*up[0] - sr*left[0], cr*up[1] - sr*left[1], cr*up[2] - sr*left[2]},
+	}
+}
+
+// AttitudeFromQuaternion returns the attitude of a unit quaternion (w, x, y, z) rotating the local north, east, down
+// frame into the body frame (forward, right, down), the convention of most autopilots (e.g. PX4, ArduPilot)
+func AttitudeFromQuaternion(q [4]float64) Attitude {
+	w, x, y, z := q[0], q[1], q[2], q[3]
+	/* clamp, rounding may push the sine slightly out of [-1, 1] */
+	sp := math.Max(-1.0, math.Min(1.0, 2.0*(w*y-z*x)))
+	a := Attitude{
+		Heading: degrad * math.Atan2(2.0*(w*z+x*y), 1.0-2.0*(y*y+z*z)),
+		Pitch:   degrad * math.Asin(sp),
+		Roll:    degrad * math.Atan2(2.0*(w*x+y*z), 1.0-2.0*(x*x+y*y)),
+	}
+	if a.Heading < 0.0 {
+		a.Heading += 360.0
+	}
+	return a
+}
+
+// BodyPosition is the sun position relative to a vehicle
+type BodyPosition struct {
+	Vector    [3]float64 // Unit vector to the sun in the vehicle frame (forward, left, up)
+	Azimuth   float64    // Azimuth from the nose, clockwise seen from above (right = 90, tail = 180, left = 270), degrees
+	Elevation float64    // Elevation above the plane of the forward and lateral axes, degrees
+}
+
+// SunPosition returns the refraction corrected sun position of a calculated result in the frame of a vehicle with this
+// attitude, e.g. to keep a gimbal camera from pointing into the sun or to plan the energy of a solar-powered aircraft
+func (a Attitude) SunPosition(r Result) BodyPosition {
+	v := a.ToVehicle(SunVector(r))
+	b := BodyPosition{
+		Vector:    v,
+		Azimuth:   degrad * math.Atan2(-v[1], v[0]),
+		Elevation: degrad * math.Asin(math.Max(-1.0, math.Min(1.0, v[2]))),
+	}
+	if b.Azimuth < 0.0 {
+		b.Azimuth += 360.0
+	}
+	return b
+}
+
+// TrackPoint is a sample of a GPS track with the attitude of the vehicle
+type TrackPoint struct {
+	Time       time.Time // Time of the sample
+	Latitude   float64   // Latitude, degrees north (south negative)
+	Longitude  float64   // Longitude, degrees east (west negative)
+	Altitude   float64   // Altitude, meters, used for the clear sky
+	Attitude   Attitude  // Orientation of the vehicle
+	Irradiance *ClearSky // Measured global, direct and diffuse irradiance, DEFAULT (nil) = clear sky
+}
+
+// VehiclePanel is a PV panel mounted on a vehicle
+type VehiclePanel struct {
+	Name   string     // Name of the panel, e.g. "roof"
+	Normal [3]float64 // Normal vector of the panel in the vehicle frame (forward, left, up), e.g. {0, 0, 1} for a flat roof
+	Area   float64    // Area, sq m
+}
+
+// VehicleConfig configures VehicleIrradiance
+type VehicleConfig struct {
+	LinkeTurbidity     float64                // Linke turbidity of the clear sky (see ClearSkyIneichen), DEFAULT (0) = 3
+	Albedo             float64                // Ground reflectance, 0 = no reflected irradiance
+	OptionalParameters map[string]interface{} // Optional parameters of the calculation as for NewSolpos, e.g. "press"
+}
+
+// VehicleSample is the irradiance on the panels of a vehicle at a track point
+type VehicleSample struct {
+	Time   time.Time // Time of the track point
+	Result Result    // Sun position at the track point
+	POA    []float64 // Plane-of-array irradiance of every panel, W/sq m
+	Power  float64   // Irradiance integrated over all panels, W
+	Energy float64   // Irradiation on all panels since the first track point (trapezoidal rule), Wh
+}
+
+// VehicleIrradiance returns the irradiance on panels mounted on a vehicle along a GPS track, e.g. for solar cars or
+// vehicle-integrated PV. The irradiance of a panel is the beam part on it, the isotropic sky diffuse part it sees and
+// the part reflected by the ground, shading by the vehicle itself and the surroundings is not accounted for.
+func VehicleIrradiance(track []TrackPoint, panels []VehiclePanel, config VehicleConfig) ([]VehicleSample, error) {
+	if len(track) == 0 {
+		return nil, nil
+	}
+	for i, p := range panels {
+		length := math.Sqrt(p.Normal[0]*p.Normal[0] + p.Normal[1]*p.Normal[1] + p.Normal[2]*p.Normal[2])
+		if length == 0.0 || p.Area < 0.0 {
+			return nil, errors.New("Please fix panel " + strconv.Itoa(i) + ", normal must not be the zero vector and area not negative")
+		}
+	}
+	linke := config.LinkeTurbidity
+	if linke == 0.0 {
+		linke = 3.0
+	}
+	sp, err := NewSolpos(track[0].Time, track[0].Latitude, track[0].Longitude, config.OptionalParameters)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]VehicleSample, len(track))
+	for k, t := range track {
+		if k > 0 && t.Time.Before(track[k-1].Time) {
+			return nil, errors.New("Please fix track, times must not decrease")
+		}
+		sp.SetDate(t.Time)
+		sp.SetLatitude(t.Latitude)
+		sp.SetLongitude(t.Longitude)
+		err = sp.Calculate()
+		if err != nil {
+			return nil, wrap(err, "track point "+strconv.Itoa(k))
+		}
+		r := sp.GetResult()
+		cs := ClearSkyIneichen(r, linke, t.Altitude)
+		if t.Irradiance != nil {
+			cs = *t.Irradiance
+		}
+		s := VehicleSample{Time: t.Time, Result: r, POA: make([]float64, len(panels))}
+		for i, p := range panels {
+			normal := t.Attitude.ToENU(p.Normal)
+			inc, err := IncidenceOnSurface(r, normal)
+			if err != nil {
+				return nil, err
+			}
+			if r.Zenref >= 90.0 {
+				/* no beam part below the horizon, whatever the panel faces */
+				inc.Cosinc = 0.0
+			}
+			length := math.Sqrt(normal[0]*normal[0] + normal[1]*normal[1] + normal[2]*normal[2])
+			s.POA[i] = isotropicPOA(cs, inc.Cosinc, normal[2]/length, config.Albedo)
+			s.Power += s.POA[i] * p.Area
+		}
+		if k > 0 {
+			hours := t.Time.Sub(track[k-1].Time).Hours()
+			s.Energy = samples[k-1].Energy + (samples[k-1].Power+s.Power)/2.0*hours
+		}
+		samples[k] = s
+	}
+	return samples, nil
+}
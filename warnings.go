@@ -0,0 +1,80 @@
+package solpos
+
+import (
+	"math"
+)
+
+// Warning reports a near-degenerate condition of a calculation whose outputs are flag values or undefined
+type Warning int
+
+const (
+	WarnAirmass    Warning = iota // refracted zenith angle above 93 degrees, amass and ampress are -1, prime and unprime are invalid
+	WarnPole                      // latitude within 0.01 degrees of a pole, azimuth and sunset hour angle are undefined
+	WarnPolarDay                  // sun up for 24 hours, sretr is -2999 and ssetr is 2999
+	WarnPolarNight                // sun down for 24 hours, sretr is 2999 and ssetr is -2999
+	WarnYearRange                 // year outside the range of the Algorithm (1950-2050 for SOLPOS), calculated with a degraded accuracy (see YearPolicy)
+	WarnTimezone                  // timezone differs from longitude/15 by more than timezoneTolerance, likely a sign error in one of them
+)
+
+// timezoneTolerance is the difference in hours between timezone and the mean solar time of the longitude above which
+// WarnTimezone is reported. Real timezones differ by up to about 3 hours including daylight saving time (western China, Spain).
+const timezoneTolerance = 3.0
+
+func (w Warning) String() string {
+	switch w {
+	case WarnAirmass:
+		return "airmass"
+	case WarnPole:
+		return "pole"
+	case WarnPolarDay:
+		return "polar day"
+	case WarnPolarNight:
+		return "polar night"
+	case WarnYearRange:
+		return "year range"
+	case WarnTimezone:
+		return "timezone"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the warning by its name, e.g. in the JSON output of a Result
+func (w Warning) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+func (sp *PosData) GetWarnings() []Warning {
+	return sp.warnings
+}
+
+// checkWarnings collects the warnings of the functions run by the last calculation
+func (sp *PosData) checkWarnings() {
+	sp.warnings = nil
+	if first, last := sp.algorithm().years(); sp.computed.HasFlag(LGeom) && (sp.Year < first || sp.Year > last) {
+		sp.warnings = append(sp.warnings, WarnYearRange)
+	}
+	if sp.computed.HasFlag(LGeom) && math.Abs(timezoneOffset(sp.Timezone, sp.Longitude)) > timezoneTolerance {
+		sp.warnings = append(sp.warnings, WarnTimezone)
+	}
+	if sp.computed.HasFlag(LAmass) && sp.Zenref > 93.0 {
+		sp.warnings = append(sp.warnings, WarnAirmass)
+	}
+	if sp.computed&(LSolazm|LSsha) != 0 && math.Abs(sp.Latitude) > 89.99 {
+		sp.warnings = append(sp.warnings, WarnPole)
+	}
+	/* same limits as srss */
+	if sp.computed.HasFlag(LSsha) {
+		if sp.Ssha >= 179.0 {
+			sp.warnings = append(sp.warnings, WarnPolarDay)
+		} else if sp.Ssha <= 1.0 {
+			sp.warnings = append(sp.warnings, WarnPolarNight)
+		}
+	}
+}
+
+// timezoneOffset returns the difference in hours between a timezone and the mean solar time of a longitude,
+// within -12 to 12 hours so that timezones across the date line (e.g. +12 at 170 degrees west) are consistent
+func timezoneOffset(timezone float64, longitude float64) float64 {
+	return math.Remainder(timezone-longitude/15.0, 24.0)
+}